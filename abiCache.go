@@ -0,0 +1,293 @@
+package endless
+
+import (
+	"bytes"
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// CacheTTL is an option to [NewAbiCache], how long a module ABI fetched at the latest ledger version is kept
+type CacheTTL time.Duration
+
+// CacheSize is an option to [NewAbiCache], the maximum number of module ABIs kept
+type CacheSize int
+
+const (
+	defaultAbiCacheTTL  = 5 * time.Minute
+	defaultAbiCacheSize = 1000
+)
+
+// AbiCache keeps module ABIs in memory, so ABI based builders such as [NodeClient.EntryFunctionWithArgs] and
+// [NodeClient.ViewWithArgs] don't fetch the module on every call.  It's safe for concurrent use, and can be shared by
+// several clients of the same network.
+//
+// ABIs fetched at the latest ledger version expire after the [CacheTTL], while ABIs fetched at a specific ledger
+// version never change, and ABIs loaded by [AbiCache.Load] are preloaded on purpose, so both are kept until evicted.  Once the [CacheSize] is reached, the least recently used ABI is
+// evicted.  After upgrading a package, call [AbiCache.InvalidateAddress] to pick up the new ABIs straight away.
+//
+//	cache, err := NewAbiCache(CacheTTL(time.Hour), CacheSize(10_000))
+//	err = cache.LoadFile("abis/registry.json")
+//	client.SetAbiCache(cache)
+type AbiCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[abiCacheKey]*list.Element
+	lru     *list.List // lru has the most recently used entry at the front
+	now     func() time.Time
+}
+
+// abiCacheKey identifies a module, at the latest ledger version if pinned is false
+type abiCacheKey struct {
+	address       AccountAddress
+	module        string
+	pinned        bool
+	ledgerVersion uint64
+}
+
+type abiCacheEntry struct {
+	key     abiCacheKey
+	abi     *api.MoveModule
+	expires time.Time // expires is zero for entries that don't expire
+}
+
+// NewAbiCache creates an empty cache.
+//
+// Accepts options:
+//   - [CacheTTL] how long ABIs at the latest ledger version are kept, 5 minutes by default, 0 to never expire
+//   - [CacheSize] the maximum number of ABIs kept, 1000 by default
+func NewAbiCache(options ...any) (*AbiCache, error) {
+	cache := &AbiCache{
+		ttl:     defaultAbiCacheTTL,
+		size:    defaultAbiCacheSize,
+		entries: make(map[abiCacheKey]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+	for i, arg := range options {
+		switch value := arg.(type) {
+		case CacheTTL:
+			cache.ttl = time.Duration(value)
+		case CacheSize:
+			if value <= 0 {
+				return nil, errors.New("CacheSize must be greater than 0")
+			}
+			cache.size = int(value)
+		default:
+			return nil, fmt.Errorf("NewAbiCache arg %d bad type %T", i+1, arg)
+		}
+	}
+	return cache, nil
+}
+
+// Get returns the cached ABI of the module, at the latest ledger version or the given one
+func (cache *AbiCache) Get(address AccountAddress, moduleName string, ledgerVersion ...uint64) (*api.MoveModule, bool) {
+	key := newAbiCacheKey(address, moduleName, ledgerVersion)
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*abiCacheEntry)
+	if !entry.expires.IsZero() && !cache.now().Before(entry.expires) {
+		cache.remove(element)
+		return nil, false
+	}
+	cache.lru.MoveToFront(element)
+	return entry.abi, true
+}
+
+// Put adds the ABI of a module, as of the latest ledger version or the given one.  The module's address and name are
+// taken from the ABI.
+func (cache *AbiCache) Put(abi *api.MoveModule, ledgerVersion ...uint64) error {
+	if abi == nil || abi.Address == nil || abi.Name == "" {
+		return errors.New("module ABI must have an address and name")
+	}
+	cache.put(newAbiCacheKey(*abi.Address, abi.Name, ledgerVersion), abi, true)
+	return nil
+}
+
+// put adds the ABI of the module identified by key, expiring after the TTL if expire is set and it's not pinned
+func (cache *AbiCache) put(key abiCacheKey, abi *api.MoveModule, expire bool) {
+	entry := &abiCacheEntry{key: key, abi: abi}
+	if expire && !key.pinned && cache.ttl > 0 {
+		entry.expires = cache.now().Add(cache.ttl)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if element, ok := cache.entries[key]; ok {
+		element.Value = entry
+		cache.lru.MoveToFront(element)
+		return
+	}
+	cache.entries[key] = cache.lru.PushFront(entry)
+	for cache.lru.Len() > cache.size {
+		cache.remove(cache.lru.Back())
+	}
+}
+
+// Invalidate removes every cached ABI of the module, such as after it's upgraded
+func (cache *AbiCache) Invalidate(address AccountAddress, moduleName string) {
+	cache.removeIf(func(key abiCacheKey) bool {
+		return key.address == address && key.module == moduleName
+	})
+}
+
+// InvalidateAddress removes every cached ABI of the modules at an address, such as after a package is upgraded
+func (cache *AbiCache) InvalidateAddress(address AccountAddress) {
+	cache.removeIf(func(key abiCacheKey) bool {
+		return key.address == address
+	})
+}
+
+// Clear removes every cached ABI
+func (cache *AbiCache) Clear() {
+	cache.removeIf(func(key abiCacheKey) bool {
+		return true
+	})
+}
+
+// Len returns the number of cached ABIs, including any that have expired but not yet been removed
+func (cache *AbiCache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.lru.Len()
+}
+
+// Load adds module ABIs from JSON, which is a module as returned by the node e.g. {"bytecode": "0x...", "abi": {...}},
+// a module ABI on its own, or an array of either.  Loaded ABIs are kept as of the latest ledger version, and never
+// expire, though they can still be evicted or invalidated.  Load them again to pick up an upgrade.
+//
+//	curl https://rpc.endless.link/v1/accounts/0x1/modules > framework.json
+//	err := cache.Load(file)
+func (cache *AbiCache) Load(reader io.Reader) error {
+	blob, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	blob = bytes.TrimSpace(blob)
+
+	var modules []json.RawMessage
+	if len(blob) > 0 && blob[0] == '[' {
+		if err = json.Unmarshal(blob, &modules); err != nil {
+			return fmt.Errorf("bad module ABI JSON: %w", err)
+		}
+	} else {
+		modules = []json.RawMessage{blob}
+	}
+
+	for i, module := range modules {
+		bytecode := api.MoveBytecode{}
+		if err = json.Unmarshal(module, &bytecode); err != nil {
+			return fmt.Errorf("bad module ABI JSON at %d: %w", i, err)
+		}
+		abi := bytecode.Abi
+		if abi == nil {
+			// Not wrapped in bytecode, it's the ABI on its own
+			abi = &api.MoveModule{}
+			if err = json.Unmarshal(module, abi); err != nil {
+				return fmt.Errorf("bad module ABI JSON at %d: %w", i, err)
+			}
+		}
+		if abi.Address == nil || abi.Name == "" {
+			return fmt.Errorf("bad module ABI JSON at %d: module ABI must have an address and name", i)
+		}
+		cache.put(newAbiCacheKey(*abi.Address, abi.Name, nil), abi, false)
+	}
+	return nil
+}
+
+// LoadFile adds module ABIs from a JSON file, which never expire, see [AbiCache.Load]
+func (cache *AbiCache) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if err = cache.Load(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// removeIf removes every entry whose key matches
+func (cache *AbiCache) removeIf(matches func(key abiCacheKey) bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key, element := range cache.entries {
+		if matches(key) {
+			cache.remove(element)
+		}
+	}
+}
+
+// remove removes an entry, the lock must be held
+func (cache *AbiCache) remove(element *list.Element) {
+	cache.lru.Remove(element)
+	delete(cache.entries, element.Value.(*abiCacheEntry).key)
+}
+
+func newAbiCacheKey(address AccountAddress, moduleName string, ledgerVersion []uint64) abiCacheKey {
+	key := abiCacheKey{address: address, module: moduleName}
+	if len(ledgerVersion) > 0 {
+		key.pinned = true
+		key.ledgerVersion = ledgerVersion[0]
+	}
+	return key
+}
+
+// StructLayout returns the struct from the cached ABI of its module, without fetching it, for decoding offline with
+// ABIs loaded by [AbiCache.Load], which don't expire
+func (cache *AbiCache) StructLayout(structTag *StructTag) (*api.MoveStruct, error) {
+	abi, ok := cache.Get(structTag.Address, structTag.Module)
+	if !ok {
+		return nil, fmt.Errorf("no cached ABI for module of struct %s", structTag.String())
+	}
+	return findStructLayout(abi, structTag)
+}
+
+// SetAbiCache sets the cache of module ABIs used by the ABI based builders, nil disables caching.  By default, each
+// client has its own [AbiCache] with the default options.
+func (rc *NodeClient) SetAbiCache(cache *AbiCache) {
+	rc.abiCache = cache
+}
+
+// AbiCache returns the cache of module ABIs, nil if caching is disabled
+func (rc *NodeClient) AbiCache() *AbiCache {
+	return rc.abiCache
+}
+
+// ModuleAbi fetches the ABI of a module, from the [AbiCache] if it's there.  Optionally, a ledgerVersion can be given
+// to get the ABI at a specific ledger version.
+//
+//	abi, err := client.ModuleAbi(AccountOne, "fungible_asset")
+func (rc *NodeClient) ModuleAbi(address AccountAddress, moduleName string, ledgerVersion ...uint64) (*api.MoveModule, error) {
+	if rc.abiCache != nil {
+		if abi, ok := rc.abiCache.Get(address, moduleName, ledgerVersion...); ok {
+			return abi, nil
+		}
+	}
+	module, err := rc.AccountModule(address, moduleName, ledgerVersion...)
+	if err != nil {
+		return nil, err
+	}
+	if module.Abi == nil {
+		return nil, fmt.Errorf("module %s::%s has no ABI", address.String(), moduleName)
+	}
+	if rc.abiCache != nil {
+		rc.abiCache.put(newAbiCacheKey(address, moduleName, ledgerVersion), module.Abi, true)
+	}
+	return module.Abi, nil
+}
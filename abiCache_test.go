@@ -0,0 +1,132 @@
+package endless
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func testModuleAbi(address AccountAddress, name string) *api.MoveModule {
+	return &api.MoveModule{Address: &address, Name: name}
+}
+
+func TestNodeClient_EntryFunctionWithArgsCachesAbi(t *testing.T) {
+	var fetches atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasSuffix(r.URL.Path, "/module/registry"))
+		fetches.Add(1)
+		_, _ = w.Write([]byte(testViewModuleJson))
+	}))
+	cafe := AccountAddress{}
+	assert.NoError(t, cafe.ParseStringRelaxed("0xcafe"))
+
+	for range 3 {
+		_, err := client.EntryFunctionWithArgs(cafe, "registry", "register", nil, []any{})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Views share the cache
+	_, err := client.ModuleAbi(cafe, "registry")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// Pinned versions are cached separately
+	_, err = client.ModuleAbi(cafe, "registry", 10)
+	assert.NoError(t, err)
+	_, err = client.ModuleAbi(cafe, "registry", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), fetches.Load())
+
+	// After an upgrade, every version is fetched again
+	client.AbiCache().InvalidateAddress(cafe)
+	_, err = client.EntryFunctionWithArgs(cafe, "registry", "register", nil, []any{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+
+	// Without a cache, every call fetches
+	client.SetAbiCache(nil)
+	_, err = client.EntryFunctionWithArgs(cafe, "registry", "register", nil, []any{})
+	assert.NoError(t, err)
+	_, err = client.EntryFunctionWithArgs(cafe, "registry", "register", nil, []any{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), fetches.Load())
+}
+
+func TestAbiCache_ExpiryAndEviction(t *testing.T) {
+	now := time.Now()
+	cache, err := NewAbiCache(CacheTTL(time.Minute), CacheSize(2))
+	assert.NoError(t, err)
+	cache.now = func() time.Time { return now }
+
+	assert.NoError(t, cache.Put(testModuleAbi(AccountOne, "a")))
+	assert.NoError(t, cache.Put(testModuleAbi(AccountOne, "b"), 5))
+	now = now.Add(time.Minute)
+	_, ok := cache.Get(AccountOne, "a")
+	assert.False(t, ok)
+	// ABIs at a ledger version don't expire
+	_, ok = cache.Get(AccountOne, "b", 5)
+	assert.True(t, ok)
+	_, ok = cache.Get(AccountOne, "b")
+	assert.False(t, ok)
+
+	// The least recently used is evicted
+	assert.NoError(t, cache.Put(testModuleAbi(AccountOne, "c")))
+	assert.NoError(t, cache.Put(testModuleAbi(AccountOne, "d")))
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get(AccountOne, "b", 5)
+	assert.False(t, ok)
+	abi, ok := cache.Get(AccountOne, "d")
+	assert.True(t, ok)
+	assert.Equal(t, "d", abi.Name)
+
+	cache.Invalidate(AccountOne, "d")
+	_, ok = cache.Get(AccountOne, "d")
+	assert.False(t, ok)
+	cache.Clear()
+	assert.Equal(t, 0, cache.Len())
+
+	assert.Error(t, cache.Put(&api.MoveModule{Name: "no address"}))
+	_, err = NewAbiCache(CacheSize(0))
+	assert.Error(t, err)
+}
+
+func TestAbiCache_LoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abis.json")
+	contents := `[` + testViewModuleJson + `,{"address":"0x1","name":"coin","friends":[],"exposed_functions":[],"structs":[]}]`
+	assert.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	now := time.Now()
+	cache, err := NewAbiCache()
+	assert.NoError(t, err)
+	cache.now = func() time.Time { return now }
+	assert.NoError(t, cache.LoadFile(path))
+	assert.Equal(t, 2, cache.Len())
+
+	// Preloaded ABIs don't expire
+	now = now.Add(defaultAbiCacheTTL + time.Minute)
+
+	// Preloaded ABIs are used without fetching
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	client.SetAbiCache(cache)
+	cafe := AccountAddress{}
+	assert.NoError(t, cafe.ParseStringRelaxed("0xcafe"))
+	entryFunction, err := client.EntryFunctionWithArgs(cafe, "registry", "register", nil, []any{})
+	assert.NoError(t, err)
+	assert.Equal(t, "register", entryFunction.Function)
+	_, err = client.ModuleAbi(AccountOne, "coin")
+	assert.NoError(t, err)
+
+	assert.Error(t, cache.Load(strings.NewReader(`{"bytecode":"0x00"}`)))
+	assert.Error(t, cache.LoadFile(filepath.Join(t.TempDir(), "missing.json")))
+}
@@ -23,3 +23,8 @@ func (aar *AccountResourceRecord) UnmarshalBCS(des *bcs.Deserializer) {
 	aar.Tag.UnmarshalBCS(des)
 	aar.Data = des.ReadBytes()
 }
+
+// Decode decodes the resource's data, with struct layouts looked up in layouts, see [DecodeMoveValue]
+func (aar *AccountResourceRecord) Decode(layouts StructLayouts) (*MoveValue, error) {
+	return DecodeMoveValue(TypeTag{Value: &aar.Tag}, aar.Data, layouts)
+}
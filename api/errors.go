@@ -1,8 +1,18 @@
 package api
 
+import "fmt"
+
 // Error is an error from the REST API
 type Error struct {
 	Message     string `json:"message"`       // Message is the error message
 	ErrorCode   string `json:"error_code"`    // ErrorCode is the string name of the error
 	VmErrorCode uint64 `json:"vm_error_code"` // VmErrorCode is the number of the failure, optional 0 if not set
 }
+
+// Error returns the message and error code, so it can be returned as an error
+func (e *Error) Error() string {
+	if e.VmErrorCode != 0 {
+		return fmt.Sprintf("%s (%s, vm error %d)", e.Message, e.ErrorCode, e.VmErrorCode)
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, e.ErrorCode)
+}
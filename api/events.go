@@ -38,6 +38,7 @@ type Event struct {
 	Type           string         // Type is the fully qualified name of the event e.g. 0x1::coin::WithdrawEvent
 	Guid           *GUID          // GUID is the unique identifier of the event, only present in V1 events
 	SequenceNumber uint64         // SequenceNumber is the sequence number of the event, only present in V1 events
+	Version        uint64         // Version of the transaction that emitted the event, only present when fetched by event handle or creation number
 	Data           map[string]any // Data is the event data, a map of field name to value, this should match it's on-chain struct representation
 }
 
@@ -49,6 +50,7 @@ func (o *Event) UnmarshalJSON(b []byte) error {
 		Type           string         `json:"type"`
 		Guid           *GUID          `json:"guid"`
 		SequenceNumber U64            `json:"sequence_number"`
+		Version        U64            `json:"version"`
 		Data           map[string]any `json:"data"`
 	}
 	data := &inner{}
@@ -59,6 +61,7 @@ func (o *Event) UnmarshalJSON(b []byte) error {
 	o.Type = data.Type
 	o.Guid = data.Guid
 	o.SequenceNumber = data.SequenceNumber.ToUint64()
+	o.Version = data.Version.ToUint64()
 	o.Data = data.Data
 	return nil
 }
@@ -45,6 +45,24 @@ func (o *CommittedTransaction) Version() uint64 {
 	return *o.Inner.TxnVersion()
 }
 
+// Events emitted by the transaction, nil for transactions without events such as a StateCheckpointTransaction
+func (o *CommittedTransaction) Events() []*Event {
+	switch inner := o.Inner.(type) {
+	case *UserTransaction:
+		return inner.Events
+	case *GenesisTransaction:
+		return inner.Events
+	case *BlockMetadataTransaction:
+		return inner.Events
+	case *BlockEpilogueTransaction:
+		return inner.Events
+	case *ValidatorTransaction:
+		return inner.Events
+	default:
+		return nil
+	}
+}
+
 // UnmarshalJSON unmarshals the [Transaction] from JSON handling conversion between types
 func (o *CommittedTransaction) UnmarshalJSON(b []byte) error {
 	type inner struct {
@@ -2,7 +2,9 @@ package bcs
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"slices"
 )
@@ -19,11 +21,28 @@ import (
 //		return deserializer.Error()
 //	}
 type Deserializer struct {
-	source []byte // Underlying data to parse
-	pos    int    // Current position in the buffer
-	err    error  // Any error that has happened so far
+	source []byte    // Underlying data to parse, or the data read so far from the reader
+	pos    int       // Current position in the buffer
+	err    error     // Any error that has happened so far
+	reader io.Reader // reader is read into source as needed, for a streaming Deserializer
+	offset int64     // offset is the position of source[0] in the stream
+	limits Limits    // limits bound what is deserialized, zero for unlimited
+	depth  int       // depth is the number of nested Struct calls
+
+	capturing    bool  // capturing keeps the bytes from captureStart when reading more from the reader
+	captureStart int64 // captureStart is the offset in the stream of the bytes being captured
 }
 
+// Limits bound what a [Deserializer] accepts, for deserializing untrusted input.  A zero limit is unlimited.
+type Limits struct {
+	MaxSequenceLength int   // MaxSequenceLength is the longest sequence, byte array, or string
+	MaxDepth          int   // MaxDepth is the deepest nesting of structs, see [Deserializer.Struct]
+	MaxBytes          int64 // MaxBytes is the most bytes deserialized in total
+}
+
+// readChunkSize is the most read from a reader at once, so a bad length doesn't allocate more than is actually read
+const readChunkSize = 64 * 1024
+
 // NewDeserializer creates a new Deserializer from a byte array.
 func NewDeserializer(bytes []byte) *Deserializer {
 	return &Deserializer{
@@ -33,6 +52,60 @@ func NewDeserializer(bytes []byte) *Deserializer {
 	}
 }
 
+// NewReaderDeserializer creates a Deserializer streaming from a reader, such as a file or connection with many items.
+// Only the bytes needed are read, so it's best to wrap unbuffered readers with [bufio.NewReader].  Errors include the
+// offset in the stream.
+//
+//	des := NewReaderDeserializer(bufio.NewReader(file), Limits{MaxSequenceLength: 10_000, MaxDepth: 32})
+//	for des.More() {
+//		txn := &SignedTransaction{}
+//		des.Struct(txn)
+//		if des.Error() != nil {
+//			return des.Error()
+//		}
+//	}
+func NewReaderDeserializer(reader io.Reader, limits Limits) *Deserializer {
+	return &Deserializer{
+		reader: reader,
+		limits: limits,
+	}
+}
+
+// SetLimits sets the limits on what is deserialized from now on
+func (des *Deserializer) SetLimits(limits Limits) {
+	des.limits = limits
+}
+
+// Offset is the number of bytes deserialized so far
+func (des *Deserializer) Offset() int64 {
+	return des.offset + int64(des.pos)
+}
+
+// More tells whether there are more bytes to deserialize, reading from the reader of a streaming Deserializer if
+// needed.  It's false after any error.
+func (des *Deserializer) More() bool {
+	if des.err != nil {
+		return false
+	}
+	if des.pos < len(des.source) {
+		return true
+	}
+	if des.reader == nil {
+		return false
+	}
+	buffer := [1]byte{}
+	n, err := io.ReadFull(des.reader, buffer[:])
+	if n == 1 {
+		des.compact()
+		des.source = append(des.source, buffer[0])
+		return true
+	}
+	if !errors.Is(err, io.EOF) {
+		des.setError("failed to read: %w", err)
+	}
+	return false
+}
+
 // Deserialize deserializes a single item from bytes.
 //
 // This function will error if there are remaining bytes.
@@ -62,7 +135,8 @@ func (des *Deserializer) SetError(err error) {
 	des.err = err
 }
 
-// Remaining tells the remaining bytes, which can be useful if there were more bytes than expected
+// Remaining tells the remaining bytes, which can be useful if there were more bytes than expected.  For a streaming
+// Deserializer, it's the bytes read from the reader but not yet deserialized.
 //
 //	bytes := []byte{0x01, 0x02}
 //	deserializer := NewDeserializer(bytes)
@@ -74,40 +148,110 @@ func (des *Deserializer) Remaining() int {
 
 // Bool deserializes a single byte as a bool
 func (des *Deserializer) Bool() bool {
-	if des.pos >= len(des.source) {
-		des.setError("not enough bytes remaining to deserialize bool")
+	value := deserializeUint(des, "bool", 1, func(slice []byte) uint8 {
+		return slice[0]
+	})
+	if des.err != nil {
 		return false
 	}
 
 	out := false
-	switch des.U8() {
+	switch value {
 	case 0:
 		out = false
 	case 1:
 		out = true
 	default:
-		des.setError("bad bool at [%des]: %x", des.pos-1, des.source[des.pos-1])
+		des.pos--
+		des.setError("bad bool %x", value)
 	}
 	return out
 }
 
-func deserializeUint[T uint8 | uint16 | uint32 | uint64](des *Deserializer, typeName string, size int, decode func(slice []byte) T) T {
-	end := des.pos + size
-	if end > len(des.source) {
+// fill makes sure there are size bytes after pos, reading them from the reader if needed
+func (des *Deserializer) fill(typeName string, size int) bool {
+	if des.err != nil {
+		return false
+	}
+	if des.limits.MaxBytes > 0 && des.Offset()+int64(size) > des.limits.MaxBytes {
+		des.setError("deserializing %s would exceed the limit of %d bytes", typeName, des.limits.MaxBytes)
+		return false
+	}
+	if des.pos+size <= len(des.source) {
+		return true
+	}
+	if des.reader == nil {
 		des.setError("not enough bytes remaining to deserialize %s", typeName)
+		return false
+	}
+
+	des.compact()
+	end := des.pos + size
+	for len(des.source) < end {
+		have := len(des.source)
+		chunk := min(end-have, readChunkSize)
+		des.source = slices.Grow(des.source, chunk)[:have+chunk]
+		n, err := io.ReadFull(des.reader, des.source[have:])
+		des.source = des.source[:have+n]
+		if err != nil {
+			des.pos = len(des.source)
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				des.setError("not enough bytes remaining to deserialize %s", typeName)
+			} else {
+				des.setError("failed to read %s: %w", typeName, err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// compact drops the bytes already deserialized from a streaming Deserializer's buffer
+func (des *Deserializer) compact() {
+	drop := des.pos
+	if des.capturing {
+		drop = min(drop, int(des.captureStart-des.offset))
+	}
+	if drop == 0 {
+		return
+	}
+	des.offset += int64(drop)
+	des.source = des.source[:copy(des.source, des.source[drop:])]
+	des.pos -= drop
+}
+
+// capture returns a copy of the bytes deserialized by read
+func (des *Deserializer) capture(read func()) []byte {
+	start := des.Offset()
+	outer := des.capturing
+	if !outer {
+		des.capturing, des.captureStart = true, start
+	}
+	read()
+	if !outer {
+		des.capturing = false
+	}
+	if des.err != nil {
+		return nil
+	}
+	return slices.Clone(des.source[start-des.offset : des.pos])
+}
+
+func deserializeUint[T uint8 | uint16 | uint32 | uint64](des *Deserializer, typeName string, size int, decode func(slice []byte) T) T {
+	if !des.fill(typeName, size) {
 		return T(0)
 	}
+	end := des.pos + size
 	out := decode(des.source[des.pos:end])
 	des.pos = end
 	return out
 }
 
 func (des *Deserializer) deserializeUBigint(typeName string, size int) big.Int {
-	end := des.pos + size
-	if end > len(des.source) {
-		des.setError("not enough bytes remaining to deserialize %s", typeName)
+	if !des.fill(typeName, size) {
 		return *big.NewInt(-1)
 	}
+	end := des.pos + size
 	bytesBigEndian := make([]byte, size)
 	copy(bytesBigEndian[:], des.source[des.pos:end])
 	des.pos = end
@@ -160,8 +304,7 @@ func (des *Deserializer) Uleb128() uint32 {
 
 	for out < maxU32 {
 		// Ensure we still have bytes to process
-		if des.pos >= len(des.source) {
-			des.setError("not enough bytes remaining to deserialize uleb128")
+		if !des.fill("uleb128", 1) {
 			return 0
 		}
 
@@ -187,15 +330,33 @@ func (des *Deserializer) Uleb128() uint32 {
 	return uint32(out)
 }
 
+// SequenceLength reads the Uleb128 length prefixing a sequence, checked against the [Limits].  [Unmarshaler]
+// implementations should use it rather than [Deserializer.Uleb128] for lengths, and shouldn't allocate the whole
+// length up front, as the input may be untrusted.
+func (des *Deserializer) SequenceLength() int {
+	length := des.Uleb128()
+	if des.err != nil {
+		return 0
+	}
+	if des.limits.MaxSequenceLength > 0 && int64(length) > int64(des.limits.MaxSequenceLength) {
+		des.setError("sequence length %d exceeds the limit of %d", length, des.limits.MaxSequenceLength)
+		return 0
+	}
+	return int(length)
+}
+
 // ReadBytes reads bytes prefixed with a length
 func (des *Deserializer) ReadBytes() []byte {
-	length := des.Uleb128()
+	length := des.SequenceLength()
 	if des.err != nil {
 		return nil
 	}
 
+	if !des.fill("bytes", length) {
+		return nil
+	}
 	dest := make([]byte, length)
-	des.readBytes("bytes", int(length), dest)
+	des.readBytes("bytes", length, dest)
 	return dest
 }
 
@@ -218,24 +379,42 @@ func (des *Deserializer) ReadFixedBytesInto(dest []byte) {
 }
 
 func (des *Deserializer) readBytes(typeName string, length int, dest []byte) {
-	end := des.pos + length
-	if end > len(des.source) {
-		des.setError("not enough bytes remaining to deserialize %s", typeName)
+	if !des.fill(typeName, length) {
 		return
 	}
+	end := des.pos + length
 	copy(dest, des.source[des.pos:end])
 	des.pos = end
 }
 
 // Struct reads an Unmarshaler implementation from bcs bytes
 //
-// This is used for handling types outside the provided primitives
+// This is used for handling types outside the provided primitives.  Nesting is checked against [Limits.MaxDepth].
 func (des *Deserializer) Struct(v Unmarshaler) {
 	if v == nil {
 		des.setError("cannot deserialize into nil")
 		return
 	}
+	if !des.enter() {
+		return
+	}
 	v.UnmarshalBCS(des)
+	des.leave()
+}
+
+// enter starts deserializing a nested value, checking the depth against the limit
+func (des *Deserializer) enter() bool {
+	if des.limits.MaxDepth > 0 && des.depth >= des.limits.MaxDepth {
+		des.setError("nesting exceeds the limit of %d", des.limits.MaxDepth)
+		return false
+	}
+	des.depth++
+	return true
+}
+
+// leave finishes deserializing a nested value
+func (des *Deserializer) leave() {
+	des.depth--
 }
 
 // DeserializeSequence deserializes an Unmarshaler implementation array
@@ -246,7 +425,7 @@ func DeserializeSequence[T any](des *Deserializer) []T {
 	return DeserializeSequenceWithFunction(des, func(des *Deserializer, out *T) {
 		mv, ok := any(out).(Unmarshaler)
 		if ok {
-			mv.UnmarshalBCS(des)
+			des.Struct(mv)
 		} else {
 			// If it isn't of type Unmarshaler, we pass up an error
 			des.setError("type is not Unmarshaler")
@@ -259,18 +438,21 @@ func DeserializeSequence[T any](des *Deserializer) []T {
 // This lets you deserialize a whole sequence of any type, and will fail if any member fails.
 // All sequences are prefixed with an Uleb128 length.
 func DeserializeSequenceWithFunction[T any](des *Deserializer, deserialize func(des *Deserializer, out *T)) []T {
-	length := des.Uleb128()
+	length := des.SequenceLength()
 	if des.Error() != nil {
 		return nil
 	}
-	out := make([]T, length)
-	for i := 0; i < int(length); i++ {
-		deserialize(des, &out[i])
+	// Grow as items are deserialized, so a bad length doesn't allocate
+	out := make([]T, 0, min(length, readChunkSize))
+	for i := 0; i < length; i++ {
+		var item T
+		deserialize(des, &item)
 
 		if des.Error() != nil {
-			des.setError("could not deserialize sequence[%d] member of %w", i, des.Error())
+			des.err = fmt.Errorf("could not deserialize sequence[%d] member of %w", i, des.Error())
 			return nil
 		}
+		out = append(out, item)
 	}
 	return out
 }
@@ -311,12 +493,13 @@ func DeserializeOption[T any](des *Deserializer, deserialize func(des *Deseriali
 	return nil
 }
 
-// setError overrides the previous error, this can only be called from within the bcs package
+// setError sets the error with the current offset, unless there's already an error.  This can only be called from
+// within the bcs package.
 func (des *Deserializer) setError(msg string, args ...any) {
 	if des.err != nil {
 		return
 	}
-	des.err = fmt.Errorf(msg, args...)
+	des.err = fmt.Errorf("%w at offset %d", fmt.Errorf(msg, args...), des.Offset())
 }
 
 // DeserializeBytes deserializes a byte array prefixed with a length
@@ -2,5 +2,12 @@
 //
 // The bcs package can be used to serialize and deserialize complex types into a binary canonical format that is non-self describing.  Meaning that you will need to know the format ahead of time in order to serialize and deserialize. Check out [Serializer] for serialization and [Deserializer] for deserialization.
 //
+// For types without a hand-written [Marshaler] or [Unmarshaler], [Marshal] and [Unmarshal] use reflection and struct tags.
+//
+// Enums are registered with their variants in an [Enum], and [Tuple], [SerializeMap], and [DeserializeMap] handle
+// tuples and maps.
+//
+// [NewReaderDeserializer] streams from an [io.Reader], and [Limits] bound what is accepted from untrusted input.
+//
 // [BCS]: https://github.com/diem/bcs
 package bcs
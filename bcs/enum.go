@@ -0,0 +1,294 @@
+package bcs
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// UnknownVariantError is the error for a variant that isn't registered in an [Enum]
+type UnknownVariantError struct {
+	Enum    string // Enum is the name of the enum
+	Variant uint32 // Variant is the unknown variant
+}
+
+func (err *UnknownVariantError) Error() string {
+	return fmt.Sprintf("unknown variant %d of %s", err.Variant, err.Enum)
+}
+
+// Enum is a registry of the variants of a BCS enum, which is serialized as the Uleb128 variant followed by the value
+// of the variant.  T is usually an interface implemented by the type of each variant, which must implement
+// [Marshaler] and [Unmarshaler].
+//
+// Register all the variants before use, usually when declaring the Enum.
+//
+//	var authenticatorEnum = bcs.NewEnum[AuthenticatorImpl]("Authenticator").
+//		Register(0, func() AuthenticatorImpl { return &Ed25519Authenticator{} }).
+//		Register(1, func() AuthenticatorImpl { return &MultiEd25519Authenticator{} })
+//
+//	func (auth *Authenticator) MarshalBCS(ser *bcs.Serializer) {
+//		authenticatorEnum.Marshal(ser, uint32(auth.Variant), auth.Auth)
+//	}
+//
+//	func (auth *Authenticator) UnmarshalBCS(des *bcs.Deserializer) {
+//		variant, value := authenticatorEnum.Unmarshal(des)
+//		auth.Variant, auth.Auth = AuthenticatorVariant(variant), value
+//	}
+type Enum[T any] struct {
+	name         string
+	constructors map[uint32]func() T
+}
+
+// NewEnum creates an Enum without any variants, the name is used in errors
+func NewEnum[T any](name string) *Enum[T] {
+	return &Enum[T]{
+		name:         name,
+		constructors: make(map[uint32]func() T),
+	}
+}
+
+// Register adds a variant with the constructor for its empty value, to deserialize into.  It panics if the variant
+// is already registered.
+func (enum *Enum[T]) Register(variant uint32, constructor func() T) *Enum[T] {
+	if _, ok := enum.constructors[variant]; ok {
+		panic(fmt.Sprintf("variant %d of %s is already registered", variant, enum.name))
+	}
+	enum.constructors[variant] = constructor
+	return enum
+}
+
+// RegisterVariants adds variants without a constructor, for enums whose values aren't a [Marshaler] and [Unmarshaler],
+// serialized with [Enum.MarshalVariant] and [Enum.UnmarshalVariant] only.  It panics if a variant is already
+// registered.
+func (enum *Enum[T]) RegisterVariants(variants ...uint32) *Enum[T] {
+	for _, variant := range variants {
+		enum.Register(variant, nil)
+	}
+	return enum
+}
+
+// Name is the name of the enum
+func (enum *Enum[T]) Name() string {
+	return enum.name
+}
+
+// Variants are the registered variants in order
+func (enum *Enum[T]) Variants() []uint32 {
+	variants := make([]uint32, 0, len(enum.constructors))
+	for variant := range enum.constructors {
+		variants = append(variants, variant)
+	}
+	slices.Sort(variants)
+	return variants
+}
+
+// New creates the empty value of a variant, or errors with an [UnknownVariantError]
+func (enum *Enum[T]) New(variant uint32) (T, error) {
+	var zero T
+	constructor, ok := enum.constructors[variant]
+	if !ok {
+		return zero, &UnknownVariantError{Enum: enum.name, Variant: variant}
+	}
+	if constructor == nil {
+		return zero, fmt.Errorf("variant %d of %s has no constructor", variant, enum.name)
+	}
+	return constructor(), nil
+}
+
+// MarshalVariant serializes only the variant, for enums with values that aren't a [Marshaler].  It's false, with an
+// [UnknownVariantError], if the variant isn't registered.
+func (enum *Enum[T]) MarshalVariant(ser *Serializer, variant uint32) bool {
+	if ser.Error() != nil {
+		return false
+	}
+	if _, ok := enum.constructors[variant]; !ok {
+		ser.SetError(&UnknownVariantError{Enum: enum.name, Variant: variant})
+		return false
+	}
+	ser.Uleb128(variant)
+	return true
+}
+
+// UnmarshalVariant deserializes only the variant, for enums with values that aren't an [Unmarshaler].  It's false,
+// with an [UnknownVariantError], if the variant isn't registered.
+func (enum *Enum[T]) UnmarshalVariant(des *Deserializer) (uint32, bool) {
+	variant := des.Uleb128()
+	if des.Error() != nil {
+		return 0, false
+	}
+	if _, ok := enum.constructors[variant]; !ok {
+		des.setError("%w", &UnknownVariantError{Enum: enum.name, Variant: variant})
+		return 0, false
+	}
+	return variant, true
+}
+
+// Marshal serializes the variant followed by its value
+func (enum *Enum[T]) Marshal(ser *Serializer, variant uint32, value T) {
+	if !enum.MarshalVariant(ser, variant) {
+		return
+	}
+	marshaler, ok := any(value).(Marshaler)
+	if !ok || isNil(marshaler) {
+		ser.SetError(fmt.Errorf("variant %d of %s has no value to marshal, got %T", variant, enum.name, value))
+		return
+	}
+	ser.Struct(marshaler)
+}
+
+// Unmarshal deserializes the variant followed by its value, created with the variant's constructor
+func (enum *Enum[T]) Unmarshal(des *Deserializer) (uint32, T) {
+	var zero T
+	variant, ok := enum.UnmarshalVariant(des)
+	if !ok {
+		return 0, zero
+	}
+	constructor := enum.constructors[variant]
+	if constructor == nil {
+		des.setError("variant %d of %s has no value to unmarshal into", variant, enum.name)
+		return 0, zero
+	}
+	value := constructor()
+	unmarshaler, ok := any(value).(Unmarshaler)
+	if !ok || isNil(unmarshaler) {
+		des.setError("variant %d of %s has no value to unmarshal into, got %T", variant, enum.name, value)
+		return 0, zero
+	}
+	des.Struct(unmarshaler)
+	if des.Error() != nil {
+		return 0, zero
+	}
+	return variant, value
+}
+
+// isNil tells whether an interface holds a nil pointer, which can't be marshaled
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	reflected := reflect.ValueOf(value)
+	return reflected.Kind() == reflect.Pointer && reflected.IsNil()
+}
+
+// Tuple is a BCS tuple, its values one after another without a length.  Values are serialized with their
+// [Marshaler], or by [Marshal] otherwise, and are deserialized the same way into pointers.
+//
+//	bytes, err := bcs.Serialize(bcs.Tuple{&address, uint64(100)})
+//	err = bcs.Deserialize(bcs.Tuple{&address, &amount}, bytes)
+type Tuple []any
+
+// MarshalBCS serializes the values of the tuple in order
+//
+// Implements:
+//   - [Marshaler]
+func (tuple Tuple) MarshalBCS(ser *Serializer) {
+	for i, value := range tuple {
+		if value == nil {
+			ser.SetError(fmt.Errorf("cannot marshal nil tuple[%d]", i))
+			return
+		}
+		marshalValue(ser, reflect.ValueOf(value), noFieldOptions)
+		if ser.Error() != nil {
+			ser.SetError(fmt.Errorf("could not serialize tuple[%d]: %w", i, ser.Error()))
+			return
+		}
+	}
+}
+
+// UnmarshalBCS deserializes the values of the tuple in order, into the pointers of the tuple
+//
+// Implements:
+//   - [Unmarshaler]
+func (tuple Tuple) UnmarshalBCS(des *Deserializer) {
+	for i, value := range tuple {
+		UnmarshalFrom(des, value)
+		if des.Error() != nil {
+			des.err = fmt.Errorf("could not deserialize tuple[%d]: %w", i, des.Error())
+			return
+		}
+	}
+}
+
+// SerializeMap serializes a map as a sequence of key and value pairs, sorted by the serialized keys as BCS requires
+//
+//	bcs.SerializeMap(balances, ser, func(ser *bcs.Serializer, key AccountAddress) {
+//		ser.Struct(&key)
+//	}, func(ser *bcs.Serializer, value uint64) {
+//		ser.U64(value)
+//	})
+func SerializeMap[M ~map[K]V, K comparable, V any](values M, ser *Serializer, serializeKey func(ser *Serializer, key K), serializeValue func(ser *Serializer, value V)) {
+	type entry struct {
+		key      []byte
+		value    V
+		original K
+	}
+	entries := make([]entry, 0, len(values))
+	for key, value := range values {
+		keySer := &Serializer{}
+		serializeKey(keySer, key)
+		if keySer.Error() != nil {
+			ser.SetError(fmt.Errorf("could not serialize map key %v: %w", key, keySer.Error()))
+			return
+		}
+		entries = append(entries, entry{key: keySer.ToBytes(), value: value, original: key})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return bytes.Compare(a.key, b.key)
+	})
+
+	ser.Uleb128(uint32(len(entries)))
+	for i, entry := range entries {
+		if i > 0 && bytes.Equal(entries[i-1].key, entry.key) {
+			ser.SetError(fmt.Errorf("map keys %v and %v serialize the same", entries[i-1].original, entry.original))
+			return
+		}
+		ser.FixedBytes(entry.key)
+		serializeValue(ser, entry.value)
+		if ser.Error() != nil {
+			ser.SetError(fmt.Errorf("could not serialize map value of %v: %w", entry.original, ser.Error()))
+			return
+		}
+	}
+}
+
+// DeserializeMap deserializes a map serialized by [SerializeMap].  It errors if the keys aren't sorted and unique, as
+// the BCS would then not be canonical.
+//
+//	balances := bcs.DeserializeMap(des, func(des *bcs.Deserializer, key *AccountAddress) {
+//		des.Struct(key)
+//	}, func(des *bcs.Deserializer, value *uint64) {
+//		*value = des.U64()
+//	})
+func DeserializeMap[K comparable, V any](des *Deserializer, deserializeKey func(des *Deserializer, key *K), deserializeValue func(des *Deserializer, value *V)) map[K]V {
+	length := des.SequenceLength()
+	if des.Error() != nil {
+		return nil
+	}
+	out := make(map[K]V, min(length, readChunkSize))
+	var previous []byte
+	for i := range length {
+		var key K
+		keyBytes := des.capture(func() {
+			deserializeKey(des, &key)
+		})
+		if des.Error() != nil {
+			des.err = fmt.Errorf("could not deserialize map key[%d]: %w", i, des.Error())
+			return nil
+		}
+		if i > 0 && bytes.Compare(previous, keyBytes) >= 0 {
+			des.setError("map keys are not sorted and unique")
+			return nil
+		}
+		previous = keyBytes
+
+		var value V
+		deserializeValue(des, &value)
+		if des.Error() != nil {
+			des.err = fmt.Errorf("could not deserialize map value[%d]: %w", i, des.Error())
+			return nil
+		}
+		out[key] = value
+	}
+	return out
+}
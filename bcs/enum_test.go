@@ -0,0 +1,188 @@
+package bcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testShape is an enum of [testCircle] and [testSquare]
+type testShape struct {
+	Variant uint32
+	Shape   Struct
+}
+
+var testShapeEnum = NewEnum[Struct]("Shape").
+	Register(0, func() Struct { return &testCircle{} }).
+	Register(2, func() Struct { return &testSquare{} })
+
+func (shape *testShape) MarshalBCS(ser *Serializer) {
+	testShapeEnum.Marshal(ser, shape.Variant, shape.Shape)
+}
+
+func (shape *testShape) UnmarshalBCS(des *Deserializer) {
+	shape.Variant, shape.Shape = testShapeEnum.Unmarshal(des)
+}
+
+type testCircle struct {
+	radius uint8
+}
+
+func (circle *testCircle) MarshalBCS(ser *Serializer) {
+	ser.U8(circle.radius)
+}
+
+func (circle *testCircle) UnmarshalBCS(des *Deserializer) {
+	circle.radius = des.U8()
+}
+
+type testSquare struct {
+	side uint16
+}
+
+func (square *testSquare) MarshalBCS(ser *Serializer) {
+	ser.U16(square.side)
+}
+
+func (square *testSquare) UnmarshalBCS(des *Deserializer) {
+	square.side = des.U16()
+}
+
+func Test_Enum(t *testing.T) {
+	for _, test := range []struct {
+		shape testShape
+		bytes []byte
+	}{
+		{testShape{0, &testCircle{radius: 5}}, []byte{0, 5}},
+		{testShape{2, &testSquare{side: 0x0102}}, []byte{2, 2, 1}},
+	} {
+		serialized, err := Serialize(&test.shape)
+		assert.NoError(t, err)
+		assert.Equal(t, test.bytes, serialized)
+
+		shape := testShape{}
+		assert.NoError(t, Deserialize(&shape, serialized))
+		assert.Equal(t, test.shape, shape)
+	}
+	assert.Equal(t, "Shape", testShapeEnum.Name())
+	assert.Equal(t, []uint32{0, 2}, testShapeEnum.Variants())
+	square, err := testShapeEnum.New(2)
+	assert.NoError(t, err)
+	assert.Equal(t, &testSquare{}, square)
+
+	// Unknown variants are errors in both directions
+	unknown := &UnknownVariantError{}
+	_, err = Serialize(&testShape{Variant: 1, Shape: &testCircle{}})
+	assert.ErrorAs(t, err, &unknown)
+	assert.Equal(t, &UnknownVariantError{Enum: "Shape", Variant: 1}, unknown)
+	err = Deserialize(&testShape{}, []byte{1, 5})
+	assert.ErrorContains(t, err, "unknown variant 1 of Shape")
+	des := NewDeserializer([]byte{1, 5})
+	des.Struct(&testShape{})
+	assert.ErrorAs(t, des.Error(), &unknown)
+	assert.ErrorContains(t, des.Error(), "at offset 1")
+	_, err = testShapeEnum.New(3)
+	assert.ErrorAs(t, err, &unknown)
+
+	// Values must be set
+	_, err = Serialize(&testShape{Variant: 0})
+	assert.ErrorContains(t, err, "no value")
+	var nilCircle *testCircle
+	_, err = Serialize(&testShape{Variant: 0, Shape: nilCircle})
+	assert.ErrorContains(t, err, "no value")
+
+	assert.Panics(t, func() {
+		testShapeEnum.Register(0, func() Struct { return &testCircle{} })
+	})
+}
+
+func Test_Enum_Variants(t *testing.T) {
+	colors := NewEnum[any]("Color").RegisterVariants(0, 1)
+	assert.Equal(t, []uint32{0, 1}, colors.Variants())
+
+	// Only the variant is serialized, the value is up to the caller
+	ser := &Serializer{}
+	assert.True(t, colors.MarshalVariant(ser, 1))
+	assert.Equal(t, []byte{1}, ser.ToBytes())
+	variant, ok := colors.UnmarshalVariant(NewDeserializer([]byte{1}))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), variant)
+	assert.False(t, colors.MarshalVariant(&Serializer{}, 2))
+
+	// There's nothing to construct
+	_, err := colors.New(0)
+	assert.ErrorContains(t, err, "no constructor")
+	des := NewDeserializer([]byte{0})
+	colors.Unmarshal(des)
+	assert.ErrorContains(t, des.Error(), "no value")
+
+	assert.Panics(t, func() {
+		colors.RegisterVariants(1)
+	})
+}
+
+func Test_Tuple(t *testing.T) {
+	inner := TestStruct{num: 1, b: true}
+	serialized, err := Serialize(Tuple{&inner, uint64(2), "three", []uint16{4}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 1, 2, 0, 0, 0, 0, 0, 0, 0, 5, 't', 'h', 'r', 'e', 'e', 1, 4, 0}, serialized)
+
+	decoded := TestStruct{}
+	var num uint64
+	var str string
+	var nums []uint16
+	assert.NoError(t, Deserialize(Tuple{&decoded, &num, &str, &nums}, serialized))
+	assert.Equal(t, inner, decoded)
+	assert.Equal(t, uint64(2), num)
+	assert.Equal(t, "three", str)
+	assert.Equal(t, []uint16{4}, nums)
+
+	_, err = Serialize(Tuple{nil})
+	assert.ErrorContains(t, err, "tuple[0]")
+	assert.ErrorContains(t, Deserialize(Tuple{&num, str}, serialized[1:]), "tuple[1]")
+}
+
+func Test_Map(t *testing.T) {
+	serializeKey := func(ser *Serializer, key string) {
+		ser.WriteString(key)
+	}
+	serializeValue := func(ser *Serializer, value uint8) {
+		ser.U8(value)
+	}
+	deserializeKey := func(des *Deserializer, key *string) {
+		*key = des.ReadString()
+	}
+	deserializeValue := func(des *Deserializer, value *uint8) {
+		*value = des.U8()
+	}
+
+	// Sorted by the serialized keys, so the shorter key is first
+	values := map[string]uint8{"b": 1, "aa": 2, "c": 3}
+	ser := &Serializer{}
+	SerializeMap(values, ser, serializeKey, serializeValue)
+	assert.NoError(t, ser.Error())
+	serialized := ser.ToBytes()
+	assert.Equal(t, []byte{3, 1, 'b', 1, 1, 'c', 3, 2, 'a', 'a', 2}, serialized)
+
+	des := NewDeserializer(serialized)
+	assert.Equal(t, values, DeserializeMap(des, deserializeKey, deserializeValue))
+	assert.NoError(t, des.Error())
+	des = NewReaderDeserializer(&oneByteReader{source: serialized}, Limits{})
+	assert.Equal(t, values, DeserializeMap(des, deserializeKey, deserializeValue))
+	assert.NoError(t, des.Error())
+
+	// Keys must be sorted and unique
+	des = NewDeserializer([]byte{2, 1, 'c', 3, 1, 'b', 1})
+	assert.Nil(t, DeserializeMap(des, deserializeKey, deserializeValue))
+	assert.ErrorContains(t, des.Error(), "not sorted")
+	des = NewDeserializer([]byte{2, 1, 'b', 3, 1, 'b', 1})
+	assert.Nil(t, DeserializeMap(des, deserializeKey, deserializeValue))
+	assert.ErrorContains(t, des.Error(), "not sorted")
+
+	// Keys that serialize the same can't be in a map
+	ser = &Serializer{}
+	SerializeMap(map[string]uint8{"a": 1, "b": 2}, ser, func(ser *Serializer, key string) {
+		ser.U8(0)
+	}, serializeValue)
+	assert.ErrorContains(t, ser.Error(), "serialize the same")
+}
@@ -0,0 +1,566 @@
+package bcs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Marshal serializes any value with reflection, so types don't need a hand-written [Marshaler].  Types which implement
+// [Marshaler] are serialized with it, otherwise:
+//   - bool, uint8, uint16, uint32, and uint64 are serialized as such, and uint as uint64
+//   - int8, int16, int32, int64, and int are serialized as two's complement of the same width, int as int64
+//   - big.Int is serialized as u128, or u256 with the tag bcs:"u256"
+//   - string is serialized as UTF-8 bytes, prefixed with a length
+//   - slices are prefixed with a length, unless tagged bcs:"fixed=N", while arrays are fixed length
+//   - pointers are serialized as the value they point to, or as an Option with the tag bcs:"optional"
+//   - structs are serialized field by field, skipping unexported fields and those tagged bcs:"-"
+//   - structs with fields tagged bcs:"variant=N" are enums, see below
+//
+// Field tags are comma separated, for example:
+//
+//	type Deposit struct {
+//		Account  [32]byte
+//		Amount   big.Int    `bcs:"u256"`
+//		Index    uint32     `bcs:"uleb128"`
+//		Memo     *string    `bcs:"optional"`
+//		Hash     []byte     `bcs:"fixed=32"`
+//		Previous []*big.Int `bcs:"u256"` // Widths apply to the items of slices, arrays, and pointers
+//		cached   []byte     // Unexported fields are skipped
+//	}
+//
+// The u128 and u256 widths only apply to big.Int, and uleb128 only to unsigned integers, any other use is an error.
+//
+// An enum is a struct with a pointer field for each variant, tagged with the variant's index.  Exactly one variant is
+// set, and it's serialized as its index followed by its value.  Variants without a value can be *struct{}.
+//
+//	type Authenticator struct {
+//		Ed25519      *Ed25519Authenticator      `bcs:"variant=0"`
+//		MultiEd25519 *MultiEd25519Authenticator `bcs:"variant=1"`
+//		NoAccount    *struct{}                  `bcs:"variant=4"`
+//	}
+func Marshal(value any) ([]byte, error) {
+	return SerializeSingle(func(ser *Serializer) {
+		if value == nil {
+			ser.SetError(errors.New("cannot marshal nil"))
+			return
+		}
+		marshalValue(ser, reflect.ValueOf(value), noFieldOptions)
+	})
+}
+
+// Unmarshal deserializes bytes into the value dest points to with reflection, the reverse of [Marshal].  Types which
+// implement [Unmarshaler] are deserialized with it.
+//
+// This function will error if there are remaining bytes.
+//
+//	deposit := Deposit{}
+//	err := bcs.Unmarshal(bytes, &deposit)
+func Unmarshal(bytes []byte, dest any) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("cannot unmarshal into %T, must be a non-nil pointer", dest)
+	}
+	des := NewDeserializer(bytes)
+	UnmarshalFrom(des, dest)
+	if des.err != nil {
+		return des.err
+	}
+	if des.Remaining() > 0 {
+		return fmt.Errorf("deserialize failed: remaining %d byte(s)", des.Remaining())
+	}
+	return nil
+}
+
+// UnmarshalFrom unmarshals the next value from the Deserializer into dest as [Unmarshal] does, such as from a
+// streaming Deserializer.  Any error is in [Deserializer.Error].
+//
+//	for des.More() {
+//		deposit := Deposit{}
+//		UnmarshalFrom(des, &deposit)
+//	}
+func UnmarshalFrom(des *Deserializer, dest any) {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		des.setError("cannot unmarshal into %T, must be a non-nil pointer", dest)
+		return
+	}
+	unmarshalValue(des, target.Elem(), noFieldOptions)
+}
+
+var (
+	marshalerType   = reflect.TypeFor[Marshaler]()
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+	bigIntType      = reflect.TypeFor[big.Int]()
+)
+
+// fieldOptions are the options from a field's bcs tag
+type fieldOptions struct {
+	width    string // width is u128 or u256 for big.Int, or uleb128 for unsigned integers
+	optional bool   // optional serializes a pointer as an Option
+	fixed    int    // fixed is the length of a slice without a length prefix, or -1
+	variant  int    // variant is the index of an enum variant, or -1
+	skip     bool
+}
+
+// noFieldOptions are the options of a value without a bcs tag
+var noFieldOptions = fieldOptions{fixed: -1, variant: -1}
+
+// itemOptions are the options applying to the items of a slice, array, or pointer
+func (options fieldOptions) itemOptions() fieldOptions {
+	itemOptions := noFieldOptions
+	itemOptions.width = options.width
+	return itemOptions
+}
+
+func parseFieldOptions(tag string) (fieldOptions, error) {
+	options := noFieldOptions
+	if tag == "" {
+		return options, nil
+	}
+	for _, part := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "-":
+			options.skip = true
+		case "u128", "u256", "uleb128":
+			options.width = name
+		case "optional":
+			options.optional = true
+		case "fixed", "variant":
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 0 {
+				return options, fmt.Errorf("bad bcs tag %q", tag)
+			}
+			if name == "fixed" {
+				options.fixed = number
+			} else {
+				options.variant = number
+			}
+		default:
+			return options, fmt.Errorf("unknown bcs tag %q", part)
+		}
+	}
+	return options, nil
+}
+
+// checkWidth returns an error if a width from a bcs tag doesn't apply to a field of fieldType, or to the items it holds.
+// uleb128 only applies to unsigned integers, and u128 and u256 only to big.Int.
+func checkWidth(fieldType reflect.Type, width string) error {
+	if width == "" {
+		return nil
+	}
+	itemType := fieldType
+	for itemType.Kind() == reflect.Pointer || itemType.Kind() == reflect.Slice || itemType.Kind() == reflect.Array {
+		itemType = itemType.Elem()
+	}
+	if !reflect.PointerTo(itemType).Implements(marshalerType) {
+		switch itemType.Kind() {
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+			if width == "uleb128" {
+				return nil
+			}
+		case reflect.Struct:
+			if itemType == bigIntType && width != "uleb128" {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("bcs %s tag doesn't apply to %s", width, fieldType.String())
+}
+
+// structField is an exported field of a struct, with the options from its bcs tag
+type structField struct {
+	index   int
+	name    string
+	options fieldOptions
+}
+
+// structInfo is how a struct type is serialized
+type structInfo struct {
+	fields []structField
+	isEnum bool
+	err    error
+}
+
+var structInfos sync.Map // structInfos caches the structInfo of each struct type
+
+func getStructInfo(structType reflect.Type) *structInfo {
+	if info, ok := structInfos.Load(structType); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{}
+	variants := make(map[int]bool)
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		options, err := parseFieldOptions(field.Tag.Get("bcs"))
+		if err != nil {
+			info.err = fmt.Errorf("%s.%s: %w", structType.String(), field.Name, err)
+			break
+		}
+		if options.skip {
+			continue
+		}
+		if err := checkWidth(field.Type, options.width); err != nil {
+			info.err = fmt.Errorf("%s.%s: %w", structType.String(), field.Name, err)
+			break
+		}
+		if options.variant >= 0 {
+			if field.Type.Kind() != reflect.Pointer {
+				info.err = fmt.Errorf("%s.%s: enum variant must be a pointer", structType.String(), field.Name)
+				break
+			}
+			if variants[options.variant] {
+				info.err = fmt.Errorf("%s.%s: duplicate enum variant %d", structType.String(), field.Name, options.variant)
+				break
+			}
+			variants[options.variant] = true
+		}
+		info.fields = append(info.fields, structField{index: i, name: field.Name, options: options})
+	}
+	if info.err == nil && len(variants) > 0 {
+		info.isEnum = true
+		if len(variants) != len(info.fields) {
+			info.err = fmt.Errorf("%s: every field of an enum must be a variant", structType.String())
+		}
+	}
+	actual, _ := structInfos.LoadOrStore(structType, info)
+	return actual.(*structInfo)
+}
+
+// asMarshaler returns the value as a Marshaler, through a pointer if needed
+func asMarshaler(value reflect.Value) (Marshaler, bool) {
+	if value.Kind() == reflect.Pointer && value.IsNil() {
+		return nil, false
+	}
+	if value.Type().Implements(marshalerType) && value.Kind() != reflect.Interface {
+		return value.Interface().(Marshaler), true
+	}
+	if reflect.PointerTo(value.Type()).Implements(marshalerType) {
+		if value.CanAddr() {
+			return value.Addr().Interface().(Marshaler), true
+		}
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		return pointer.Interface().(Marshaler), true
+	}
+	return nil, false
+}
+
+func marshalValue(ser *Serializer, value reflect.Value, options fieldOptions) {
+	if ser.err != nil {
+		return
+	}
+	if options.optional {
+		if value.Kind() != reflect.Pointer {
+			ser.SetError(fmt.Errorf("bcs optional must be a pointer, not %s", value.Type().String()))
+			return
+		}
+		if value.IsNil() {
+			ser.Uleb128(0)
+			return
+		}
+		ser.Uleb128(1)
+		marshalValue(ser, value.Elem(), options.itemOptions())
+		return
+	}
+	if marshaler, ok := asMarshaler(value); ok {
+		ser.Struct(marshaler)
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		ser.Bool(value.Bool())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		marshalUint(ser, value, options)
+	case reflect.Int8:
+		ser.U8(uint8(value.Int()))
+	case reflect.Int16:
+		ser.U16(uint16(value.Int()))
+	case reflect.Int32:
+		ser.U32(uint32(value.Int()))
+	case reflect.Int64, reflect.Int:
+		ser.U64(uint64(value.Int()))
+	case reflect.String:
+		ser.WriteString(value.String())
+	case reflect.Slice:
+		if options.fixed >= 0 {
+			if value.Len() != options.fixed {
+				ser.SetError(fmt.Errorf("bcs fixed length is %d, got %d", options.fixed, value.Len()))
+				return
+			}
+		} else {
+			ser.Uleb128(uint32(value.Len()))
+		}
+		marshalItems(ser, value, options.itemOptions())
+	case reflect.Array:
+		marshalItems(ser, value, options.itemOptions())
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			ser.SetError(fmt.Errorf("cannot marshal nil %s, use bcs:\"optional\" for an Option", value.Type().String()))
+			return
+		}
+		marshalValue(ser, value.Elem(), options)
+	case reflect.Struct:
+		if value.Type() == bigIntType {
+			marshalBigInt(ser, value, options)
+			return
+		}
+		marshalStruct(ser, value)
+	default:
+		ser.SetError(fmt.Errorf("cannot marshal %s", value.Type().String()))
+	}
+}
+
+func marshalUint(ser *Serializer, value reflect.Value, options fieldOptions) {
+	if options.width == "uleb128" {
+		if value.Uint() > 0xFFFFFFFF {
+			ser.SetError(fmt.Errorf("%d is too large for uleb128", value.Uint()))
+			return
+		}
+		ser.Uleb128(uint32(value.Uint()))
+		return
+	}
+	switch value.Kind() {
+	case reflect.Uint8:
+		ser.U8(uint8(value.Uint()))
+	case reflect.Uint16:
+		ser.U16(uint16(value.Uint()))
+	case reflect.Uint32:
+		ser.U32(uint32(value.Uint()))
+	default:
+		ser.U64(value.Uint())
+	}
+}
+
+func marshalBigInt(ser *Serializer, value reflect.Value, options fieldOptions) {
+	num := value.Interface().(big.Int)
+	if options.width == "u256" {
+		ser.U256(num)
+	} else {
+		ser.U128(num)
+	}
+}
+
+func marshalItems(ser *Serializer, value reflect.Value, options fieldOptions) {
+	if value.Type().Elem().Kind() == reflect.Uint8 && options.width == "" {
+		ser.FixedBytes(bytesOf(value))
+		return
+	}
+	for i := range value.Len() {
+		marshalValue(ser, value.Index(i), options)
+	}
+}
+
+// bytesOf returns the contents of a byte slice or array
+func bytesOf(value reflect.Value) []byte {
+	if value.Kind() == reflect.Slice {
+		return value.Bytes()
+	}
+	out := make([]byte, value.Len())
+	reflect.Copy(reflect.ValueOf(out), value)
+	return out
+}
+
+func marshalStruct(ser *Serializer, value reflect.Value) {
+	info := getStructInfo(value.Type())
+	if info.err != nil {
+		ser.SetError(info.err)
+		return
+	}
+	if !info.isEnum {
+		for _, field := range info.fields {
+			marshalValue(ser, value.Field(field.index), field.options)
+		}
+		return
+	}
+
+	var variant *structField
+	for _, field := range info.fields {
+		if !value.Field(field.index).IsNil() {
+			if variant != nil {
+				ser.SetError(fmt.Errorf("%s has more than one variant set", value.Type().String()))
+				return
+			}
+			variant = &field
+		}
+	}
+	if variant == nil {
+		ser.SetError(fmt.Errorf("%s has no variant set", value.Type().String()))
+		return
+	}
+	ser.Uleb128(uint32(variant.options.variant))
+	marshalValue(ser, value.Field(variant.index).Elem(), variant.options.itemOptions())
+}
+
+func unmarshalValue(des *Deserializer, target reflect.Value, options fieldOptions) {
+	if des.err != nil {
+		return
+	}
+	if options.optional {
+		if target.Kind() != reflect.Pointer {
+			des.setError("bcs optional must be a pointer, not %s", target.Type().String())
+			return
+		}
+		switch length := des.Uleb128(); length {
+		case 0:
+			target.SetZero()
+		case 1:
+			target.Set(reflect.New(target.Type().Elem()))
+			unmarshalValue(des, target.Elem(), options.itemOptions())
+		default:
+			des.setError("expected 0 or 1 element as an option, got %d", length)
+		}
+		return
+	}
+	if reflect.PointerTo(target.Type()).Implements(unmarshalerType) {
+		des.Struct(target.Addr().Interface().(Unmarshaler))
+		return
+	}
+
+	switch target.Kind() {
+	case reflect.Bool:
+		target.SetBool(des.Bool())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint:
+		unmarshalUint(des, target, options)
+	case reflect.Int8:
+		target.SetInt(int64(int8(des.U8())))
+	case reflect.Int16:
+		target.SetInt(int64(int16(des.U16())))
+	case reflect.Int32:
+		target.SetInt(int64(int32(des.U32())))
+	case reflect.Int64, reflect.Int:
+		target.SetInt(int64(des.U64()))
+	case reflect.String:
+		target.SetString(des.ReadString())
+	case reflect.Slice:
+		length := options.fixed
+		if length < 0 {
+			length = des.SequenceLength()
+			if des.err != nil {
+				return
+			}
+		}
+		if des.reader == nil && length > des.Remaining() && target.Type().Elem().Size() > 0 {
+			// Every item takes at least a byte, so don't allocate for a bad length
+			des.setError("not enough bytes remaining to deserialize %d items", length)
+			return
+		}
+		if target.Type().Elem().Kind() == reflect.Uint8 && options.width == "" {
+			target.SetBytes(des.ReadFixedBytes(length))
+			return
+		}
+		unmarshalSlice(des, target, length, options.itemOptions())
+	case reflect.Array:
+		unmarshalItems(des, target, options.itemOptions())
+	case reflect.Pointer:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		unmarshalValue(des, target.Elem(), options)
+	case reflect.Struct:
+		if target.Type() == bigIntType {
+			var num big.Int
+			if options.width == "u256" {
+				num = des.U256()
+			} else {
+				num = des.U128()
+			}
+			target.Set(reflect.ValueOf(num))
+			return
+		}
+		unmarshalStruct(des, target)
+	default:
+		des.setError("cannot unmarshal %s", target.Type().String())
+	}
+}
+
+func unmarshalUint(des *Deserializer, target reflect.Value, options fieldOptions) {
+	var num uint64
+	switch {
+	case options.width == "uleb128":
+		num = uint64(des.Uleb128())
+		if target.OverflowUint(num) {
+			des.setError("%d is too large for %s", num, target.Type().String())
+			return
+		}
+	case target.Kind() == reflect.Uint8:
+		num = uint64(des.U8())
+	case target.Kind() == reflect.Uint16:
+		num = uint64(des.U16())
+	case target.Kind() == reflect.Uint32:
+		num = uint64(des.U32())
+	default:
+		num = des.U64()
+	}
+	target.SetUint(num)
+}
+
+func unmarshalItems(des *Deserializer, target reflect.Value, options fieldOptions) {
+	if target.Kind() == reflect.Array && target.Type().Elem().Kind() == reflect.Uint8 && options.width == "" {
+		reflect.Copy(target, reflect.ValueOf(des.ReadFixedBytes(target.Len())))
+		return
+	}
+	for i := range target.Len() {
+		unmarshalValue(des, target.Index(i), options)
+		if des.err != nil {
+			return
+		}
+	}
+}
+
+// unmarshalSlice grows the slice as items are deserialized, as a streaming Deserializer can't check the length first
+func unmarshalSlice(des *Deserializer, target reflect.Value, length int, options fieldOptions) {
+	slice := reflect.MakeSlice(target.Type(), 0, min(length, readChunkSize))
+	item := reflect.New(target.Type().Elem()).Elem()
+	for range length {
+		item.SetZero()
+		unmarshalValue(des, item, options)
+		if des.err != nil {
+			return
+		}
+		slice = reflect.Append(slice, item)
+	}
+	target.Set(slice)
+}
+
+func unmarshalStruct(des *Deserializer, target reflect.Value) {
+	info := getStructInfo(target.Type())
+	if info.err != nil {
+		des.setError("%w", info.err)
+		return
+	}
+	if !des.enter() {
+		return
+	}
+	defer des.leave()
+	if !info.isEnum {
+		for _, field := range info.fields {
+			unmarshalValue(des, target.Field(field.index), field.options)
+		}
+		return
+	}
+
+	variant := des.Uleb128()
+	if des.err != nil {
+		return
+	}
+	for _, field := range info.fields {
+		if field.options.variant == int(variant) {
+			target.SetZero()
+			value := reflect.New(target.Type().Field(field.index).Type.Elem())
+			unmarshalValue(des, value.Elem(), field.options.itemOptions())
+			target.Field(field.index).Set(value)
+			return
+		}
+	}
+	des.setError("unknown variant %d of %s", variant, target.Type().String())
+}
@@ -0,0 +1,164 @@
+package bcs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDeposit struct {
+	Account  [4]byte
+	Amount   big.Int `bcs:"u256"`
+	Fee      big.Int
+	Index    uint32     `bcs:"uleb128"`
+	Memo     *string    `bcs:"optional"`
+	Hash     []byte     `bcs:"fixed=2"`
+	Previous []*big.Int `bcs:"u256"`
+	Delta    int16
+	Inner    *TestStruct // Inner is serialized with its Marshaler
+	Flags    []bool
+	Skipped  string `bcs:"-"`
+	cached   string
+}
+
+type testAuthenticator struct {
+	Single *testSingle `bcs:"variant=0"`
+	Multi  *[]uint8    `bcs:"variant=2"`
+	None   *struct{}   `bcs:"variant=3"`
+}
+
+type testSingle struct {
+	Key       []byte
+	Signature [2]byte
+}
+
+func Test_MarshalStruct(t *testing.T) {
+	memo := "hi"
+	deposit := &testDeposit{
+		Account:  [4]byte{1, 2, 3, 4},
+		Amount:   *big.NewInt(5),
+		Fee:      *big.NewInt(6),
+		Index:    300,
+		Memo:     &memo,
+		Hash:     []byte{7, 8},
+		Previous: []*big.Int{big.NewInt(9)},
+		Delta:    -2,
+		Inner:    &TestStruct{num: 10, b: true},
+		Flags:    []bool{true, false},
+		Skipped:  "skipped",
+		cached:   "cached",
+	}
+	expected := []byte{1, 2, 3, 4}
+	expected = append(append(expected, 5), make([]byte, 31)...)
+	expected = append(append(expected, 6), make([]byte, 15)...)
+	expected = append(expected, 0xac, 0x02, 1, 2, 'h', 'i', 7, 8)
+	expected = append(append(expected, 1, 9), make([]byte, 31)...)
+	expected = append(expected, 0xfe, 0xff, 10, 1, 2, 1, 0)
+
+	bytes, err := Marshal(deposit)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, bytes)
+	// By value works too
+	bytes, err = Marshal(*deposit)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, bytes)
+
+	decoded := testDeposit{}
+	assert.NoError(t, Unmarshal(bytes, &decoded))
+	deposit.Skipped, deposit.cached = "", ""
+	assert.Equal(t, *deposit, decoded)
+
+	// Options
+	deposit.Memo = nil
+	bytes, err = Marshal(deposit)
+	assert.NoError(t, err)
+	decoded = testDeposit{}
+	assert.NoError(t, Unmarshal(bytes, &decoded))
+	assert.Nil(t, decoded.Memo)
+
+	// Errors
+	deposit.Hash = []byte{1}
+	_, err = Marshal(deposit)
+	assert.ErrorContains(t, err, "fixed length")
+	deposit.Hash, deposit.Inner = []byte{1, 2}, nil
+	_, err = Marshal(deposit)
+	assert.ErrorContains(t, err, "optional")
+	_, err = Marshal(nil)
+	assert.Error(t, err)
+	_, err = Marshal(map[string]int{})
+	assert.Error(t, err)
+	assert.Error(t, Unmarshal(bytes, decoded))
+	assert.ErrorContains(t, Unmarshal(append(bytes, 0), &decoded), "remaining")
+	assert.Error(t, Unmarshal(bytes[:10], &decoded))
+}
+
+func Test_MarshalEnum(t *testing.T) {
+	tests := []struct {
+		value testAuthenticator
+		bytes []byte
+	}{
+		{testAuthenticator{Single: &testSingle{Key: []byte{1}, Signature: [2]byte{2, 3}}}, []byte{0, 1, 1, 2, 3}},
+		{testAuthenticator{Multi: &[]uint8{4, 5}}, []byte{2, 2, 4, 5}},
+		{testAuthenticator{None: &struct{}{}}, []byte{3}},
+	}
+	for _, test := range tests {
+		bytes, err := Marshal(&test.value)
+		assert.NoError(t, err)
+		assert.Equal(t, test.bytes, bytes)
+
+		decoded := testAuthenticator{Multi: &[]uint8{}}
+		assert.NoError(t, Unmarshal(bytes, &decoded))
+		assert.Equal(t, test.value, decoded)
+	}
+
+	_, err := Marshal(&testAuthenticator{})
+	assert.ErrorContains(t, err, "no variant")
+	_, err = Marshal(&testAuthenticator{Multi: &[]uint8{}, None: &struct{}{}})
+	assert.ErrorContains(t, err, "more than one")
+	assert.ErrorContains(t, Unmarshal([]byte{1}, &testAuthenticator{}), "unknown variant 1")
+
+	type badEnum struct {
+		A *uint8 `bcs:"variant=0"`
+		B uint8
+	}
+	_, err = Marshal(&badEnum{})
+	assert.ErrorContains(t, err, "every field")
+	type badTag struct {
+		A uint8 `bcs:"u512"`
+	}
+	_, err = Marshal(&badTag{})
+	assert.ErrorContains(t, err, "unknown bcs tag")
+	type badWidth struct {
+		A []int32 `bcs:"uleb128"`
+	}
+	_, err = Marshal(&badWidth{})
+	assert.ErrorContains(t, err, "bcs uleb128 tag doesn't apply to []int32")
+	assert.ErrorContains(t, Unmarshal([]byte{0}, &badWidth{}), "doesn't apply")
+	type badBigWidth struct {
+		A uint64 `bcs:"u256"`
+	}
+	_, err = Marshal(&badBigWidth{})
+	assert.ErrorContains(t, err, "bcs u256 tag doesn't apply to uint64")
+}
+
+func Test_MarshalPrimitives(t *testing.T) {
+	bytes, err := Marshal(uint16(0x0102))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2, 1}, bytes)
+	bytes, err = Marshal([]string{"a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 1, 'a'}, bytes)
+
+	var values []int64
+	assert.NoError(t, Unmarshal([]byte{1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, &values))
+	assert.Equal(t, []int64{-1}, values)
+
+	// A bad length doesn't allocate
+	assert.ErrorContains(t, Unmarshal([]byte{0xff, 0xff, 0xff, 0xff, 0x0f}, &values), "not enough bytes")
+
+	// Unmarshalers are used directly
+	st := TestStruct{}
+	assert.NoError(t, Unmarshal([]byte{1, 1}, &st))
+	assert.Equal(t, TestStruct{1, true}, st)
+}
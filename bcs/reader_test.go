@@ -0,0 +1,150 @@
+package bcs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// oneByteReader reads a byte at a time, to test reads across many calls
+type oneByteReader struct {
+	source []byte
+}
+
+func (reader *oneByteReader) Read(p []byte) (int, error) {
+	if len(reader.source) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = reader.source[0]
+	reader.source = reader.source[1:]
+	return 1, nil
+}
+
+func Test_ReaderDeserializer(t *testing.T) {
+	ser := &Serializer{}
+	ser.Struct(&TestStruct{num: 1, b: true})
+	SerializeSequence([]TestStruct{{num: 2}, {num: 3, b: true}}, ser)
+	ser.WriteString("hello")
+	ser.U256(*big.NewInt(0x1234))
+	stream := ser.ToBytes()
+
+	des := NewReaderDeserializer(&oneByteReader{source: stream}, Limits{})
+	assert.True(t, des.More())
+	st := TestStruct{}
+	des.Struct(&st)
+	assert.Equal(t, TestStruct{1, true}, st)
+	assert.Equal(t, []TestStruct{{num: 2}, {num: 3, b: true}}, DeserializeSequence[TestStruct](des))
+	assert.Equal(t, "hello", des.ReadString())
+	assert.Equal(t, *big.NewInt(0x1234), des.U256())
+	assert.NoError(t, des.Error())
+	assert.Equal(t, int64(len(stream)), des.Offset())
+	assert.False(t, des.More())
+	assert.NoError(t, des.Error())
+
+	// Values with struct tags work too
+	type deposit struct {
+		Account [2]byte
+		Amounts []uint64
+	}
+	stream, err := Marshal(&deposit{Account: [2]byte{1, 2}, Amounts: []uint64{3}})
+	assert.NoError(t, err)
+	des = NewReaderDeserializer(bytes.NewReader(append(stream, stream...)), Limits{MaxBytes: int64(2 * len(stream))})
+	count := 0
+	for des.More() {
+		decoded := deposit{}
+		UnmarshalFrom(des, &decoded)
+		assert.Equal(t, deposit{Account: [2]byte{1, 2}, Amounts: []uint64{3}}, decoded)
+		count++
+	}
+	assert.NoError(t, des.Error())
+	assert.Equal(t, 2, count)
+}
+
+func Test_ReaderDeserializerErrors(t *testing.T) {
+	// Truncated stream
+	des := NewReaderDeserializer(bytes.NewReader([]byte{1, 2, 3}), Limits{})
+	des.U8()
+	des.U64()
+	assert.ErrorContains(t, des.Error(), "not enough bytes remaining to deserialize u64 at offset 3")
+	assert.False(t, des.More())
+
+	// Read errors are kept
+	failure := errors.New("connection reset")
+	des = NewReaderDeserializer(io.MultiReader(bytes.NewReader([]byte{1}), &failingReader{failure}), Limits{})
+	des.U16()
+	assert.ErrorIs(t, des.Error(), failure)
+
+	// A bad length doesn't allocate it all before failing
+	des = NewReaderDeserializer(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x0f, 1}), Limits{})
+	assert.Nil(t, des.ReadBytes())
+	assert.ErrorContains(t, des.Error(), "not enough bytes")
+}
+
+func Test_DeserializerLimits(t *testing.T) {
+	ser := &Serializer{}
+	SerializeSequence([]TestStruct{{num: 1}, {num: 2}, {num: 3}}, ser)
+	stream := ser.ToBytes()
+
+	// Sequence length
+	des := NewReaderDeserializer(bytes.NewReader(stream), Limits{MaxSequenceLength: 2})
+	assert.Nil(t, DeserializeSequence[TestStruct](des))
+	assert.ErrorContains(t, des.Error(), "sequence length 3 exceeds the limit of 2 at offset 1")
+	var values []TestStruct
+	des = NewDeserializer(stream)
+	des.SetLimits(Limits{MaxSequenceLength: 2})
+	UnmarshalFrom(des, &values)
+	assert.ErrorContains(t, des.Error(), "exceeds the limit of 2")
+	des = NewDeserializer([]byte{3, 'a', 'b', 'c'})
+	des.SetLimits(Limits{MaxSequenceLength: 2})
+	des.ReadString()
+	assert.ErrorContains(t, des.Error(), "exceeds the limit of 2")
+
+	// Total bytes
+	des = NewReaderDeserializer(bytes.NewReader(stream), Limits{MaxBytes: 4})
+	assert.Nil(t, DeserializeSequence[TestStruct](des))
+	assert.ErrorContains(t, des.Error(), "sequence[1] member of deserializing bool would exceed the limit of 4 bytes at offset 4")
+
+	// Depth
+	type inner struct {
+		Value uint8
+	}
+	type outer struct {
+		Inner inner
+	}
+	des = NewReaderDeserializer(bytes.NewReader([]byte{1, 1}), Limits{MaxDepth: 1})
+	UnmarshalFrom(des, &outer{})
+	assert.ErrorContains(t, des.Error(), "nesting exceeds the limit of 1 at offset 0")
+	des = NewReaderDeserializer(bytes.NewReader([]byte{1, 1}), Limits{MaxDepth: 1})
+	des.Struct(&TestStruct{})
+	assert.NoError(t, des.Error())
+	des = NewReaderDeserializer(bytes.NewReader(stream), Limits{MaxDepth: 2})
+	assert.Len(t, DeserializeSequence[TestStruct](des), 3)
+	assert.NoError(t, des.Error())
+	des = NewReaderDeserializer(bytes.NewReader([]byte{1, 1}), Limits{MaxDepth: 1})
+	des.Struct(&nestedStruct{})
+	assert.ErrorContains(t, des.Error(), "nesting exceeds the limit of 1")
+}
+
+type failingReader struct {
+	err error
+}
+
+func (reader *failingReader) Read([]byte) (int, error) {
+	return 0, reader.err
+}
+
+// nestedStruct deserializes a TestStruct inside itself
+type nestedStruct struct {
+	inner TestStruct
+}
+
+func (st *nestedStruct) UnmarshalBCS(des *Deserializer) {
+	des.Struct(&st.inner)
+}
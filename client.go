@@ -1,7 +1,9 @@
 package endless
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"math/big"
 	"net/http"
 	"time"
@@ -15,10 +17,12 @@ import (
 //
 // If ChainId is 0, the ChainId wil be fetched on-chain
 // If IndexerUrl or FaucetUrl are an empty string "", clients will not be made for them.
+// If NodeUrls is set, requests are balanced across NodeUrl and NodeUrls, see [NewNodeClientWithEndpoints].
 type NetworkConfig struct {
 	Name       string
 	ChainId    uint8
 	NodeUrl    string
+	NodeUrls   []string // NodeUrls are additional full nodes to fail over to, NodeUrl remains the primary
 	IndexerUrl string
 }
 
@@ -142,10 +146,8 @@ type EndlessRpcClient interface {
 	//
 	//	data, err := client.TransactionByHash("0xabcd")
 	//	if err != nil {
-	//		if httpErr, ok := err.(endless.HttpError) {
-	//			if httpErr.StatusCode == 404 {
-	//				// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
-	//			}
+	//		if errors.Is(err, endless.ErrTransactionNotFound) {
+	//			// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
 	//		}
 	//	} else {
 	//		if data["type"] == "pending_transaction" {
@@ -159,29 +161,32 @@ type EndlessRpcClient interface {
 	//
 	//	data, err := client.TransactionByVersion("0xabcd")
 	//	if err != nil {
-	//		if httpErr, ok := err.(endless.HttpError) {
-	//			if httpErr.StatusCode == 404 {
-	//				// if we're sure this has been submitted, the full node might not be caught up to this version yet
-	//			}
+	//		if errors.Is(err, endless.ErrTransactionNotFound) {
+	//			// if we're sure this has been submitted, the full node might not be caught up to this version yet
 	//		}
 	//	}
 	TransactionByVersion(version uint64) (data *api.CommittedTransaction, err error)
 
 	// PollForTransactions Waits up to 10 seconds for transactions to be done, polling at 10Hz
-	// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values.
+	// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values, and [PollWorkers] to bound how
+	// many transactions are polled at once.  Returns a [TransactionResult] per hash, in order, and an error unless all
+	// were committed.
 	//
 	//	hashes := []string{"0x1234", "0x4567"}
-	//	err := client.PollForTransactions(hashes)
+	//	results, err := client.PollForTransactions(hashes)
 	//
 	// Can additionally configure different options
 	//
 	//	hashes := []string{"0x1234", "0x4567"}
-	//	err := client.PollForTransactions(hashes, PollPeriod(500 * time.Milliseconds), PollTimeout(5 * time.Seconds))
-	PollForTransactions(txnHashes []string, options ...any) error
+	//	results, err := client.PollForTransactions(hashes, PollPeriod(500 * time.Milliseconds), PollTimeout(5 * time.Seconds))
+	PollForTransactions(txnHashes []string, options ...any) ([]TransactionResult, error)
 
-	// WaitForTransaction Do a long-GET for one transaction and wait for it to complete
+	// WaitForTransaction Do a long-GET for one transaction and wait for it to complete.  Accepts options [PollPeriod],
+	// [PollTimeout], [TransactionExpiration], and [RequireSuccess].  It stops with [ErrTransactionExpired] if the
+	// transaction expires first.
 	//
 	//	data, err := client.WaitForTransaction("0x1234")
+	//	data, err = client.WaitForTransaction("0x1234", RequireSuccess(true))
 	WaitForTransaction(txnHash string, options ...any) (data *api.UserTransaction, err error)
 
 	// Transactions Get recent transactions.
@@ -378,8 +383,21 @@ type Client struct {
 }
 
 // NewClient Creates a new client with a specific network config that can be extended in the future
+//
+// Accepts options:
+//   - *http.Client to use for all requests
+//   - [*RetryPolicy] for retrying failed node requests
+//   - [EndpointStrategy], [MaxLedgerLag], and [FailoverCooldown], only if [NetworkConfig.NodeUrls] is set
+//   - [Interceptor] to wrap every node request, in the order given
+//   - [Instrumentation] to report spans and measurements to
+//   - [*AbiCache] to share module ABIs with other clients, see [NodeClient.SetAbiCache]
 func NewClient(config NetworkConfig, options ...any) (client *Client, err error) {
 	var httpClient *http.Client = nil
+	var retryPolicy *RetryPolicy = nil
+	interceptors := make([]Interceptor, 0)
+	var instrumentation Instrumentation = nil
+	var abiCache *AbiCache = nil
+	endpointOptions := make([]any, 0)
 	for i, arg := range options {
 		switch value := arg.(type) {
 		case *http.Client:
@@ -388,13 +406,30 @@ func NewClient(config NetworkConfig, options ...any) (client *Client, err error)
 				return
 			}
 			httpClient = value
+		case *RetryPolicy:
+			retryPolicy = value
+		case Interceptor:
+			interceptors = append(interceptors, value)
+		case EndpointStrategy, MaxLedgerLag, FailoverCooldown:
+			if len(config.NodeUrls) == 0 {
+				err = fmt.Errorf("NewClient arg %d %T needs NetworkConfig.NodeUrls", i+1, arg)
+				return
+			}
+			endpointOptions = append(endpointOptions, value)
+		case Instrumentation:
+			instrumentation = value
+		case *AbiCache:
+			abiCache = value
 		default:
 			err = fmt.Errorf("NewClient arg %d bad type %T", i+1, arg)
 			return
 		}
 	}
 	var nodeClient *NodeClient
-	if httpClient == nil {
+	if len(config.NodeUrls) > 0 {
+		rpcUrls := append([]string{config.NodeUrl}, config.NodeUrls...)
+		nodeClient, err = NewNodeClientWithEndpoints(rpcUrls, config.ChainId, httpClient, endpointOptions...)
+	} else if httpClient == nil {
 		nodeClient, err = NewNodeClient(config.NodeUrl, config.ChainId)
 	} else {
 		nodeClient, err = NewNodeClientWithHttpClient(config.NodeUrl, config.ChainId, httpClient)
@@ -402,6 +437,13 @@ func NewClient(config NetworkConfig, options ...any) (client *Client, err error)
 	if err != nil {
 		return nil, err
 	}
+	nodeClient.SetRetryPolicy(retryPolicy)
+	nodeClient.Use(interceptors...)
+	nodeClient.SetInstrumentation(instrumentation)
+	if abiCache != nil {
+		nodeClient.SetAbiCache(abiCache)
+	}
+
 	// Indexer may not be present
 	var indexerClient *IndexerClient = nil
 	if config.IndexerUrl != "" {
@@ -420,6 +462,24 @@ func NewClient(config NetworkConfig, options ...any) (client *Client, err error)
 	return
 }
 
+// WithContext returns a view of the client that applies ctx to every node and indexer request it makes.
+//
+// The returned client shares connections and configuration with the original.  Cancelling ctx aborts in-flight
+// requests, transaction polling, and concurrent page fetches.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	userTxn, err := client.WithContext(ctx).WaitForTransaction(hash)
+func (client *Client) WithContext(ctx context.Context) *Client {
+	view := &Client{
+		nodeClient: client.nodeClient.WithContext(ctx),
+	}
+	if client.indexerClient != nil {
+		view.indexerClient = client.indexerClient.WithContext(ctx)
+	}
+	return view
+}
+
 // SetTimeout adjusts the HTTP client timeout
 //
 //	client.SetTimeout(5 * time.Millisecond)
@@ -441,6 +501,45 @@ func (client *Client) RemoveHeader(key string) {
 	client.nodeClient.RemoveHeader(key)
 }
 
+// SetRetryPolicy sets the policy for retrying failed node requests, nil disables retries
+//
+//	client.SetRetryPolicy(DefaultRetryPolicy())
+func (client *Client) SetRetryPolicy(policy *RetryPolicy) {
+	client.nodeClient.SetRetryPolicy(policy)
+}
+
+// Use adds interceptors to the end of the chain for all future node requests
+//
+//	client.Use(APIKeyInterceptor("abcde"), LoggingInterceptor(slog.Default()))
+func (client *Client) Use(interceptors ...Interceptor) {
+	client.nodeClient.Use(interceptors...)
+}
+
+// SetInstrumentation sets where spans and measurements of node requests are reported, nil disables instrumentation
+//
+//	client.SetInstrumentation(myMetricsAdapter)
+func (client *Client) SetInstrumentation(instrumentation Instrumentation) {
+	client.nodeClient.SetInstrumentation(instrumentation)
+}
+
+// Endpoints returns the status of each node endpoint, nil if [NetworkConfig.NodeUrls] is not set
+func (client *Client) Endpoints() []EndpointStatus {
+	return client.nodeClient.Endpoints()
+}
+
+// CheckEndpoints probes every node endpoint with a health check and ledger version, and returns the updated statuses
+func (client *Client) CheckEndpoints() []EndpointStatus {
+	return client.nodeClient.CheckEndpoints()
+}
+
+// StartHealthChecks probes the node endpoints every interval in the background, until stop is called
+//
+//	stop := client.StartHealthChecks(5 * time.Second)
+//	defer stop()
+func (client *Client) StartHealthChecks(interval time.Duration) (stop func()) {
+	return client.nodeClient.StartHealthChecks(interval)
+}
+
 // Info Retrieves the node info about the network and it's current state
 func (client *Client) Info() (info NodeInfo, err error) {
 	return client.nodeClient.Info()
@@ -464,6 +563,14 @@ func (client *Client) AccountResource(address AccountAddress, resourceType strin
 	return client.nodeClient.AccountResource(address, resourceType, ledgerVersion...)
 }
 
+// AccountResourceBCS fetches a single resource of an account as the raw BCS of its Move struct
+// For decoding into a Go struct, See [AccountResourceAs]
+//
+//	blob, _ := client.AccountResourceBCS(address, "0x1::account::Account")
+func (client *Client) AccountResourceBCS(address AccountAddress, resourceType string, ledgerVersion ...uint64) (data []byte, err error) {
+	return client.nodeClient.AccountResourceBCS(address, resourceType, ledgerVersion...)
+}
+
 // AccountResources fetches resources for an account into a JSON-like map[string]any in AccountResourceInfo.Data
 // For fetching raw Move structs as BCS, See #AccountResourcesBCS
 //
@@ -510,10 +617,8 @@ func (client *Client) BlockByVersion(ledgerVersion uint64, withTransactions bool
 //
 //	data, err := client.TransactionByHash("0xabcd")
 //	if err != nil {
-//		if httpErr, ok := err.(endless.HttpError) {
-//			if httpErr.StatusCode == 404 {
-//				// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
-//			}
+//		if errors.Is(err, endless.ErrTransactionNotFound) {
+//			// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
 //		}
 //	} else {
 //		if data["type"] == "pending_transaction" {
@@ -529,25 +634,109 @@ func (client *Client) TransactionByHash(txnHash string) (data *api.Transaction,
 //
 //	data, err := client.TransactionByVersion("0xabcd")
 //	if err != nil {
-//		if httpErr, ok := err.(endless.HttpError) {
-//			if httpErr.StatusCode == 404 {
-//				// if we're sure this has been submitted, the full node might not be caught up to this version yet
-//			}
+//		if errors.Is(err, endless.ErrTransactionNotFound) {
+//			// if we're sure this has been submitted, the full node might not be caught up to this version yet
 //		}
 //	}
 func (client *Client) TransactionByVersion(version uint64) (data *api.CommittedTransaction, err error) {
 	return client.nodeClient.TransactionByVersion(version)
 }
 
+// TransactionsIter iterates over the ledger's transactions, fetching a page at a time.  Iteration stops at the first
+// error.  Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+//
+//	for txn, err := range client.TransactionsIter(endless.DirectionBackward) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(txn.Version())
+//	}
+func (client *Client) TransactionsIter(options ...any) iter.Seq2[*api.CommittedTransaction, error] {
+	return client.nodeClient.TransactionsIter(options...)
+}
+
+// AccountTransactionsIter iterates over the transactions sent by an account, by sequence number.  Iteration stops at
+// the first error.  Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+func (client *Client) AccountTransactionsIter(account AccountAddress, options ...any) iter.Seq2[*api.CommittedTransaction, error] {
+	return client.nodeClient.AccountTransactionsIter(account, options...)
+}
+
+// EventsByHandle fetches the events of an event handle, which is a field of a resource on the account
+//
+// Arguments:
+//   - handleStruct is the resource holding the handle e.g. "0x1::account::Account"
+//   - fieldName is the field of the resource that is the handle e.g. "coin_register_events"
+//   - start is the sequence number of the first event. Nil for the oldest events.
+//   - limit is the number of events to return. 'about a hundred' by default.
+func (client *Client) EventsByHandle(address AccountAddress, handleStruct string, fieldName string, start *uint64, limit *uint64) ([]*api.Event, error) {
+	return client.nodeClient.EventsByHandle(address, handleStruct, fieldName, start, limit)
+}
+
+// EventsByCreationNumber fetches the events of an event handle, by the creation number of its [api.GUID]
+//
+// Arguments:
+//   - creationNumber is the creation number of the event handle on the account
+//   - start is the sequence number of the first event. Nil for the oldest events.
+//   - limit is the number of events to return. 'about a hundred' by default.
+func (client *Client) EventsByCreationNumber(address AccountAddress, creationNumber uint64, start *uint64, limit *uint64) ([]*api.Event, error) {
+	return client.nodeClient.EventsByCreationNumber(address, creationNumber, start, limit)
+}
+
+// EventsByHandleIter iterates over the events of an event handle, a page at a time.  Iteration stops at the first
+// error.  Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+func (client *Client) EventsByHandleIter(address AccountAddress, handleStruct string, fieldName string, options ...any) iter.Seq2[*api.Event, error] {
+	return client.nodeClient.EventsByHandleIter(address, handleStruct, fieldName, options...)
+}
+
+// EventsByCreationNumberIter iterates over the events of an event handle by creation number, a page at a time.
+// Iteration stops at the first error.  Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+func (client *Client) EventsByCreationNumberIter(address AccountAddress, creationNumber uint64, options ...any) iter.Seq2[*api.Event, error] {
+	return client.nodeClient.EventsByCreationNumberIter(address, creationNumber, options...)
+}
+
+// TableItem fetches the value for the key in a Move table, decoded as JSON.  The key is converted from a Go value the
+// same way as entry function arguments, see [ConvertArg].  A missing key returns an error matching
+// [ErrTableItemNotFound].
+//
+//	value, err := client.TableItem(handle, "address", "u64", AccountOne)
+//
+// Optionally, a ledgerVersion can be given to get the value at a specific ledger version
+func (client *Client) TableItem(handle string, keyType string, valueType string, key any, ledgerVersion ...uint64) (any, error) {
+	return client.nodeClient.TableItem(handle, keyType, valueType, key, ledgerVersion...)
+}
+
+// TableItemBCS fetches the value for the key in a Move table as raw BCS, see [NodeClient.TableItemBCS]
+//
+// Optionally, a ledgerVersion can be given to get the value at a specific ledger version
+func (client *Client) TableItemBCS(handle string, keyType string, key any, ledgerVersion ...uint64) ([]byte, error) {
+	return client.nodeClient.TableItemBCS(handle, keyType, key, ledgerVersion...)
+}
+
+// NewEventFollower creates an [EventFollower] tailing the ledger for events selected by filter, see
+// [NewEventFollower] for options.  A nil store keeps the checkpoint in memory.
+func (client *Client) NewEventFollower(filter EventFilter, store CheckpointStore, options ...any) (*EventFollower, error) {
+	return NewEventFollower(client.nodeClient, filter, store, options...)
+}
+
+// AccountResourcesIter iterates over an account's resources, following the node's pagination cursor.  Accepts option
+// [PageSize].
+func (client *Client) AccountResourcesIter(address AccountAddress, options ...any) iter.Seq2[AccountResourceInfo, error] {
+	return client.nodeClient.AccountResourcesIter(address, options...)
+}
+
+// AccountModulesIter iterates over the modules published by an account, following the node's pagination cursor.
+// Accepts option [PageSize].
+func (client *Client) AccountModulesIter(address AccountAddress, options ...any) iter.Seq2[*api.MoveBytecode, error] {
+	return client.nodeClient.AccountModulesIter(address, options...)
+}
+
 // TransactionsByVersions gets info on some transaction from its LedgerVersion.  It must have been
 // committed to have a ledger version
 //
 //	data, err := client.TransactionByVersion("[]{uint64}")
 //	if err != nil {
-//		if httpErr, ok := err.(endless.HttpError) {
-//			if httpErr.StatusCode == 404 {
-//				// if we're sure this has been submitted, the full node might not be caught up to this version yet
-//			}
+//		if errors.Is(err, endless.ErrTransactionNotFound) {
+//			// if we're sure this has been submitted, the full node might not be caught up to this version yet
 //		}
 //	}
 func (client *Client) TransactionsByVersions(version []uint64, prune bool) (data []*api.CommittedTransaction, err error) {
@@ -555,26 +744,41 @@ func (client *Client) TransactionsByVersions(version []uint64, prune bool) (data
 }
 
 // PollForTransactions Waits up to 10 seconds for transactions to be done, polling at 10Hz
-// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values.
+// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values, and [PollWorkers] to bound how
+// many transactions are polled at once.  Returns a [TransactionResult] per hash, in order, and an error unless all
+// were committed.
 //
 //	hashes := []string{"0x1234", "0x4567"}
-//	err := client.PollForTransactions(hashes)
+//	results, err := client.PollForTransactions(hashes)
 //
 // Can additionally configure different options
 //
 //	hashes := []string{"0x1234", "0x4567"}
-//	err := client.PollForTransactions(hashes, PollPeriod(500 * time.Milliseconds), PollTimeout(5 * time.Seconds))
-func (client *Client) PollForTransactions(txnHashes []string, options ...any) error {
+//	results, err := client.PollForTransactions(hashes, PollPeriod(500 * time.Milliseconds), PollTimeout(5 * time.Seconds))
+func (client *Client) PollForTransactions(txnHashes []string, options ...any) ([]TransactionResult, error) {
 	return client.nodeClient.PollForTransactions(txnHashes, options...)
 }
 
-// WaitForTransaction Do a long-GET for one transaction and wait for it to complete
+// WaitForTransaction Do a long-GET for one transaction and wait for it to complete.  Accepts options [PollPeriod],
+// [PollTimeout], [TransactionExpiration], and [RequireSuccess].  It stops with [ErrTransactionExpired] if the
+// transaction expires first.
 //
 //	data, err := client.WaitForTransaction("0x1234")
+//	data, err = client.WaitForTransaction("0x1234", RequireSuccess(true))
 func (client *Client) WaitForTransaction(txnHash string, options ...any) (data *api.UserTransaction, err error) {
 	return client.nodeClient.WaitForTransaction(txnHash, options...)
 }
 
+// WaitForSubmissions consumes the responses of submitting transactions, waits for each submitted transaction, yields
+// the results as they finish.  Closes output chan `results` when input chan `submissions` is closed.  See
+// [NodeClient.WaitForSubmissions].
+//
+//	go client.BatchSubmitTransactions(requests, submissions)
+//	go client.WaitForSubmissions(submissions, results)
+func (client *Client) WaitForSubmissions(submissions chan TransactionSubmissionResponse, results chan TransactionWaitResponse, options ...any) {
+	client.nodeClient.WaitForSubmissions(submissions, results, options...)
+}
+
 // Transactions Get recent transactions.
 // Start is a version number. Nil for most recent transactions.
 // Limit is a number of transactions to return. 'about a hundred' by default.
@@ -764,6 +968,16 @@ func (client *Client) View(payload *ViewPayload, ledgerVersion ...uint64) (vals
 	return client.nodeClient.View(payload, ledgerVersion...)
 }
 
+// ViewWithArgs calls a view function using the on-chain module ABI, converting simple inputs to BCS encoded ones, and
+// the results to Go values of their return types, see [NodeClient.ViewWithArgs].  For decoding into a Go type, see
+// [ViewInto].
+//
+//	values, err := client.ViewWithArgs(AccountOne, "primary_fungible_store", "balance", []any{"0x1::fungible_asset::Metadata"}, []any{address, metadata})
+//	balance := values[0].(*big.Int)
+func (client *Client) ViewWithArgs(moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, ledgerVersion ...uint64) ([]any, error) {
+	return viewWithArgs(client, moduleAddress, moduleName, functionName, typeArgs, args, ledgerVersion...)
+}
+
 // EstimateGasPrice Retrieves the gas estimate from the network.
 func (client *Client) EstimateGasPrice() (info EstimateGasInfo, err error) {
 	return client.nodeClient.EstimateGasPrice()
@@ -791,3 +1005,30 @@ func (client *Client) AccountModule(address AccountAddress, moduleName string, l
 func (client *Client) EntryFunctionWithArgs(address AccountAddress, moduleName string, functionName string, typeArgs []any, args []any) (*EntryFunction, error) {
 	return client.nodeClient.EntryFunctionWithArgs(address, moduleName, functionName, typeArgs, args)
 }
+
+// ModuleAbi fetches the ABI of a module, from the [AbiCache] if it's there
+//
+//	abi, err := client.ModuleAbi(AccountOne, "fungible_asset")
+func (client *Client) ModuleAbi(address AccountAddress, moduleName string, ledgerVersion ...uint64) (*api.MoveModule, error) {
+	return client.nodeClient.ModuleAbi(address, moduleName, ledgerVersion...)
+}
+
+// StructLayout returns the struct from the ABI of its module, see [NodeClient.StructLayout]
+func (client *Client) StructLayout(structTag *StructTag) (*api.MoveStruct, error) {
+	return client.nodeClient.StructLayout(structTag)
+}
+
+// SetAbiCache sets the cache of module ABIs used by the ABI based builders, nil disables caching
+//
+//	cache, err := NewAbiCache()
+//	err = cache.LoadFile("abis/registry.json")
+//	client.SetAbiCache(cache)
+func (client *Client) SetAbiCache(cache *AbiCache) {
+	client.nodeClient.SetAbiCache(cache)
+}
+
+// AbiCache returns the cache of module ABIs, nil if caching is disabled.  After upgrading a package, invalidate its
+// ABIs with [AbiCache.InvalidateAddress].
+func (client *Client) AbiCache() *AbiCache {
+	return client.nodeClient.AbiCache()
+}
@@ -9,6 +9,15 @@ import (
 // ClientHeader is the header key for the SDK version
 const ClientHeader = "x-endless-client"
 
+// HeaderLedgerVersion is the response header with the ledger version the node answered at
+const HeaderLedgerVersion = "X-Endless-Ledger-Version"
+
+// HeaderLedgerTimestamp is the response header with the timestamp in microseconds of the ledger the node answered at
+const HeaderLedgerTimestamp = "X-Endless-Ledger-TimestampUsec"
+
+// HeaderCursor is the response header with the cursor for the next page of a paginated listing, absent on the last page
+const HeaderCursor = "X-Endless-Cursor"
+
 // ClientHeaderValue is the header value for the SDK version
 var ClientHeaderValue = "endless-go-sdk/unk"
 
@@ -0,0 +1,531 @@
+package main
+
+//go:generate go run . -abi testdata/registry.json -out internal/registry/registry.go
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/endless-labs/endless-go-sdk"
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// generator writes the Go bindings for a single Move module
+type generator struct {
+	abi     *api.MoveModule
+	address endless.AccountAddress
+	body    strings.Builder
+	imports map[string]bool
+	names   map[string]bool   // names are the Go identifiers in use
+	structs map[string]string // structs maps the Move struct names that are generated to their Go names
+
+	needSerializeArgs bool
+	needDecodeResults bool
+}
+
+// generate returns the gofmt-ed source of a Go package with bindings for the module's entry functions, view
+// functions, and structs.  Anything with a type that can't be represented is skipped, and listed in a comment.
+func generate(abi *api.MoveModule, packageName string) ([]byte, error) {
+	if abi == nil || abi.Address == nil || abi.Name == "" {
+		return nil, errors.New("module ABI must have an address and name")
+	}
+	g := &generator{
+		abi:     abi,
+		address: *abi.Address,
+		imports: map[string]bool{"github.com/endless-labs/endless-go-sdk": true},
+		names:   map[string]bool{"Module": true, "ModuleAddress": true, "ModuleName": true},
+		structs: make(map[string]string),
+	}
+
+	skipped := g.resolveStructs()
+	g.writeModule()
+	g.writeTypeConstants()
+	for _, moveStruct := range abi.Structs {
+		if goName, ok := g.structs[moveStruct.Name]; ok {
+			g.writeStruct(moveStruct, goName)
+		}
+	}
+	for _, function := range abi.ExposedFunctions {
+		if function.IsEntry {
+			if err := g.writeEntryFunction(function); err != nil {
+				skipped = append(skipped, fmt.Sprintf("entry function %s: %s", function.Name, err))
+			}
+		}
+		if function.IsView {
+			if err := g.writeViewFunction(function); err != nil {
+				skipped = append(skipped, fmt.Sprintf("view function %s: %s", function.Name, err))
+			}
+		}
+	}
+	g.writeHelpers()
+
+	out := strings.Builder{}
+	out.WriteString(fmt.Sprintf("// Code generated by endless-bindgen from %s. DO NOT EDIT.\n\n", g.moduleString()))
+	out.WriteString(fmt.Sprintf("// Package %s has bindings for the %s Move module\n", packageName, g.moduleString()))
+	for _, skip := range skipped {
+		out.WriteString(fmt.Sprintf("//\n// Skipped %s\n", skip))
+	}
+	out.WriteString(fmt.Sprintf("package %s\n\nimport (\n", packageName))
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		// The standard library first, then the SDK
+		if !strings.Contains(path, ".") {
+			out.WriteString(fmt.Sprintf("\t%q\n", path))
+		}
+	}
+	out.WriteString("\n")
+	for _, path := range imports {
+		if strings.Contains(path, ".") {
+			out.WriteString(fmt.Sprintf("\t%q\n", path))
+		}
+	}
+	out.WriteString(")\n")
+	out.WriteString(g.body.String())
+
+	source, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go: %w", err)
+	}
+	return source, nil
+}
+
+// resolveStructs decides which structs are generated, removing any with a field that can't be represented until
+// every remaining struct's fields can be
+func (g *generator) resolveStructs() (skipped []string) {
+	for _, moveStruct := range g.abi.Structs {
+		if moveStruct.IsNative || len(moveStruct.GenericTypeParams) > 0 {
+			skipped = append(skipped, fmt.Sprintf("struct %s: generic and native structs aren't supported", moveStruct.Name))
+			continue
+		}
+		g.structs[moveStruct.Name] = g.uniqueName(goName(moveStruct.Name), "")
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, moveStruct := range g.abi.Structs {
+			if _, ok := g.structs[moveStruct.Name]; !ok {
+				continue
+			}
+			for _, field := range moveStruct.Fields {
+				if _, err := g.goTypeString(field.Type, ""); err != nil {
+					skipped = append(skipped, fmt.Sprintf("struct %s: field %s: %s", moveStruct.Name, field.Name, err))
+					delete(g.structs, moveStruct.Name)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return skipped
+}
+
+func (g *generator) moduleString() string {
+	return g.address.String() + "::" + g.abi.Name
+}
+
+func (g *generator) printf(format string, args ...any) {
+	g.body.WriteString(fmt.Sprintf(format, args...))
+}
+
+func (g *generator) writeModule() {
+	g.printf("\n// ModuleAddress is the address the module is published at\nvar ModuleAddress = endless.AccountAddress{")
+	for i, b := range g.address {
+		if i > 0 {
+			g.printf(", ")
+		}
+		g.printf("%#02x", b)
+	}
+	g.printf("}\n\n// ModuleName is the name of the module\nconst ModuleName = %q\n\n", g.abi.Name)
+	g.printf("// Module identifies the module, for building payloads by hand\nvar Module = endless.ModuleId{Address: ModuleAddress, Name: ModuleName}\n")
+}
+
+// writeTypeConstants writes the full type of every struct, for reading resources and filtering events
+func (g *generator) writeTypeConstants() {
+	if len(g.abi.Structs) == 0 {
+		return
+	}
+	g.printf("\n// Types of the structs in the module, for reading resources and filtering events\nconst (\n")
+	for _, moveStruct := range g.abi.Structs {
+		name := g.uniqueName(goName(moveStruct.Name)+"Type", "")
+		kind := "struct, e.g. for filtering events"
+		if slices.Contains(moveStruct.Abilities, api.MoveAbilityKey) {
+			kind = "resource"
+		}
+		g.printf("\t%s = %q // %s is the type of the %s %s\n", name, g.moduleString()+"::"+moveStruct.Name, name, moveStruct.Name, kind)
+	}
+	g.printf(")\n")
+}
+
+func (g *generator) writeStruct(moveStruct *api.MoveStruct, name string) {
+	g.imports["github.com/endless-labs/endless-go-sdk/bcs"] = true
+	g.printf("\n// %s is the %s::%s struct\ntype %s struct {\n", name, g.moduleString(), moveStruct.Name, name)
+	fieldNames := make([]string, len(moveStruct.Fields))
+	fieldTags := make([]endless.TypeTag, len(moveStruct.Fields))
+	for i, field := range moveStruct.Fields {
+		tag, _ := endless.ParseTypeTag(field.Type)
+		goType, _ := g.goType(*tag, "")
+		fieldNames[i] = goName(field.Name)
+		fieldTags[i] = *tag
+		g.printf("\t%s %s `json:%q` // %s is %s\n", fieldNames[i], goType, field.Name, fieldNames[i], field.Type)
+	}
+	g.printf("}\n\n// MarshalBCS serializes the struct as stored on chain\nfunc (s *%s) MarshalBCS(ser *bcs.Serializer) {\n", name)
+	for i := range moveStruct.Fields {
+		g.printf("%s", g.serialize(fieldTags[i], "s."+fieldNames[i], 0))
+	}
+	g.printf("}\n\n// UnmarshalBCS deserializes the struct as stored on chain\nfunc (s *%s) UnmarshalBCS(des *bcs.Deserializer) {\n", name)
+	for i := range moveStruct.Fields {
+		g.printf("%s", g.deserialize(fieldTags[i], "s."+fieldNames[i], 0))
+	}
+	g.printf("}\n")
+}
+
+// functionParams returns the Go parameters and serializers for a function's arguments, skipping signers
+func (g *generator) functionParams(function *api.MoveFunction) (params []string, marshals []string, err error) {
+	for i := range function.GenericTypeParams {
+		params = append(params, fmt.Sprintf("typeArg%d endless.TypeTag", i))
+	}
+	for _, param := range function.Params {
+		tag, err := endless.ParseTypeTag(param)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isSigner(*tag) {
+			continue
+		}
+		goType, err := g.goType(*tag, "bcs.Marshaler")
+		if err != nil {
+			return nil, nil, err
+		}
+		name := fmt.Sprintf("arg%d", len(marshals))
+		params = append(params, name+" "+goType)
+		marshals = append(marshals, g.serialize(*tag, name, 0))
+	}
+	return params, marshals, nil
+}
+
+func (g *generator) writePayloadArgs(marshals []string) {
+	g.printf("\targs, err := serializeArgs(\n")
+	for _, marshal := range marshals {
+		g.printf("\t\tfunc(ser *bcs.Serializer) {\n%s},\n", marshal)
+	}
+	g.printf("\t)\n")
+}
+
+func typeArgList(function *api.MoveFunction) string {
+	typeArgs := make([]string, len(function.GenericTypeParams))
+	for i := range typeArgs {
+		typeArgs[i] = fmt.Sprintf("typeArg%d", i)
+	}
+	return strings.Join(typeArgs, ", ")
+}
+
+func (g *generator) writeEntryFunction(function *api.MoveFunction) error {
+	params, marshals, err := g.functionParams(function)
+	if err != nil {
+		return err
+	}
+	g.imports["github.com/endless-labs/endless-go-sdk/bcs"] = true
+	g.needSerializeArgs = true
+	name := g.uniqueName(goName(function.Name), "Call")
+	g.printf("\n// %s builds a call to the entry function %s::%s\n", name, g.moduleString(), function.Name)
+	g.printf("func %s(%s) (*endless.EntryFunction, error) {\n", name, strings.Join(params, ", "))
+	g.writePayloadArgs(marshals)
+	g.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	g.printf("\treturn &endless.EntryFunction{\n\t\tModule: Module,\n\t\tFunction: %q,\n\t\tArgTypes: []endless.TypeTag{%s},\n\t\tArgs: args,\n\t}, nil\n}\n", function.Name, typeArgList(function))
+	return nil
+}
+
+func (g *generator) writeViewFunction(function *api.MoveFunction) error {
+	params, marshals, err := g.functionParams(function)
+	if err != nil {
+		return err
+	}
+	results := make([]string, 0, len(function.Return))
+	outs := make([]string, 0, len(function.Return))
+	for i, returnType := range function.Return {
+		goType, err := g.goTypeString(returnType, "any")
+		if err != nil {
+			return err
+		}
+		results = append(results, fmt.Sprintf("r%d %s", i, goType))
+		outs = append(outs, fmt.Sprintf("&r%d", i))
+	}
+	results = append(results, "err error")
+	g.imports["github.com/endless-labs/endless-go-sdk/bcs"] = true
+	g.needSerializeArgs = true
+	g.needDecodeResults = true
+
+	name := g.uniqueName(goName(function.Name), "View")
+	if function.IsEntry {
+		// The entry function has the plain name
+		name = g.uniqueName(goName(function.Name)+"View", "")
+	}
+	params = append([]string{"client endless.ViewClient"}, params...)
+	params = append(params, "ledgerVersion ...uint64")
+	g.printf("\n// %s calls the view function %s::%s\n", name, g.moduleString(), function.Name)
+	g.printf("func %s(%s) (%s) {\n", name, strings.Join(params, ", "), strings.Join(results, ", "))
+	g.writePayloadArgs(marshals)
+	g.printf("\tif err != nil {\n\t\treturn\n\t}\n")
+	g.printf("\tvalues, err := client.View(&endless.ViewPayload{\n\t\tModule: Module,\n\t\tFunction: %q,\n\t\tArgTypes: []endless.TypeTag{%s},\n\t\tArgs: args,\n\t}, ledgerVersion...)\n", function.Name, typeArgList(function))
+	g.printf("\tif err != nil {\n\t\treturn\n\t}\n")
+	g.printf("\terr = decodeResults(values%s)\n\treturn\n}\n", prefixEach(", ", outs))
+	return nil
+}
+
+func (g *generator) writeHelpers() {
+	if g.needSerializeArgs {
+		g.imports["fmt"] = true
+		g.printf(`
+// serializeArgs serializes each argument on its own
+func serializeArgs(marshals ...func(ser *bcs.Serializer)) ([][]byte, error) {
+	args := make([][]byte, len(marshals))
+	for i, marshal := range marshals {
+		arg, err := bcs.SerializeSingle(marshal)
+		if err != nil {
+			return nil, fmt.Errorf("arg %%d: %%w", i, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+`)
+	}
+	if g.needDecodeResults {
+		g.imports["encoding/json"] = true
+		g.imports["fmt"] = true
+		g.printf(`
+// decodeResults decodes the JSON results of a view function into outs
+func decodeResults(values []any, outs ...any) error {
+	if len(values) != len(outs) {
+		return fmt.Errorf("expected %%d results, got %%d", len(outs), len(values))
+	}
+	for i, value := range values {
+		blob, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if err = endless.UnmarshalMoveJson(blob, outs[i]); err != nil {
+			return fmt.Errorf("result %%d: %%w", i, err)
+		}
+	}
+	return nil
+}
+`)
+	}
+}
+
+// goTypeString returns the Go type for a Move type string, see [generator.goType]
+func (g *generator) goTypeString(moveType string, generic string) (string, error) {
+	tag, err := endless.ParseTypeTag(moveType)
+	if err != nil {
+		return "", err
+	}
+	return g.goType(*tag, generic)
+}
+
+// goType returns the Go type representing a Move type.  Generics are represented by the generic type, and aren't
+// supported if it's empty.
+func (g *generator) goType(tag endless.TypeTag, generic string) (string, error) {
+	switch inner := tag.Value.(type) {
+	case *endless.BoolTag:
+		return "bool", nil
+	case *endless.U8Tag:
+		return "uint8", nil
+	case *endless.U16Tag:
+		return "uint16", nil
+	case *endless.U32Tag:
+		return "uint32", nil
+	case *endless.U64Tag:
+		return "uint64", nil
+	case *endless.U128Tag, *endless.U256Tag:
+		g.imports["math/big"] = true
+		return "big.Int", nil
+	case *endless.AddressTag, *endless.SignerTag:
+		return "endless.AccountAddress", nil
+	case *endless.ReferenceTag:
+		return g.goType(inner.TypeParam, generic)
+	case *endless.GenericTag:
+		if generic == "" {
+			return "", fmt.Errorf("generic type %s isn't supported", tag.String())
+		}
+		return generic, nil
+	case *endless.VectorTag:
+		if _, ok := inner.TypeParam.Value.(*endless.U8Tag); ok {
+			return "[]byte", nil
+		}
+		itemType, err := g.goType(inner.TypeParam, generic)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemType, nil
+	case *endless.StructTag:
+		switch {
+		case isFrameworkStruct(inner, "string", "String"):
+			return "string", nil
+		case isFrameworkStruct(inner, "object", "Object"):
+			return "endless.AccountAddress", nil
+		case isFrameworkStruct(inner, "option", "Option") && len(inner.TypeParams) == 1:
+			itemType, err := g.goType(inner.TypeParams[0], generic)
+			if err != nil {
+				return "", err
+			}
+			return "*" + itemType, nil
+		case g.isOwnStruct(inner):
+			if name, ok := g.structs[inner.Name]; ok {
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("struct type %s isn't supported", tag.String())
+	default:
+		return "", fmt.Errorf("type %s isn't supported", tag.String())
+	}
+}
+
+// serialize returns the statements writing expr of the Move type to ser
+func (g *generator) serialize(tag endless.TypeTag, expr string, depth int) string {
+	switch inner := tag.Value.(type) {
+	case *endless.BoolTag:
+		return fmt.Sprintf("ser.Bool(%s)\n", expr)
+	case *endless.U8Tag:
+		return fmt.Sprintf("ser.U8(%s)\n", expr)
+	case *endless.U16Tag:
+		return fmt.Sprintf("ser.U16(%s)\n", expr)
+	case *endless.U32Tag:
+		return fmt.Sprintf("ser.U32(%s)\n", expr)
+	case *endless.U64Tag:
+		return fmt.Sprintf("ser.U64(%s)\n", expr)
+	case *endless.U128Tag:
+		return fmt.Sprintf("ser.U128(%s)\n", expr)
+	case *endless.U256Tag:
+		return fmt.Sprintf("ser.U256(%s)\n", expr)
+	case *endless.ReferenceTag:
+		return g.serialize(inner.TypeParam, expr, depth)
+	case *endless.GenericTag:
+		return fmt.Sprintf("ser.Struct(%s)\n", expr)
+	case *endless.VectorTag:
+		if _, ok := inner.TypeParam.Value.(*endless.U8Tag); ok {
+			return fmt.Sprintf("ser.WriteBytes(%s)\n", expr)
+		}
+		item := fmt.Sprintf("item%d", depth)
+		return fmt.Sprintf("ser.Uleb128(uint32(len(%s)))\nfor _, %s := range %s {\n%s}\n", expr, item, expr, g.serialize(inner.TypeParam, item, depth+1))
+	case *endless.StructTag:
+		switch {
+		case isFrameworkStruct(inner, "string", "String"):
+			return fmt.Sprintf("ser.WriteString(%s)\n", expr)
+		case isFrameworkStruct(inner, "option", "Option"):
+			return fmt.Sprintf("if %s == nil {\nser.Uleb128(0)\n} else {\nser.Uleb128(1)\n%s}\n", expr, g.serialize(inner.TypeParams[0], "*"+expr, depth+1))
+		}
+	}
+	// Addresses, objects, and the module's structs
+	return fmt.Sprintf("ser.Struct(%s)\n", addressOf(expr))
+}
+
+// deserialize returns the statements reading target of the Move type from des
+func (g *generator) deserialize(tag endless.TypeTag, target string, depth int) string {
+	switch inner := tag.Value.(type) {
+	case *endless.BoolTag:
+		return fmt.Sprintf("%s = des.Bool()\n", target)
+	case *endless.U8Tag:
+		return fmt.Sprintf("%s = des.U8()\n", target)
+	case *endless.U16Tag:
+		return fmt.Sprintf("%s = des.U16()\n", target)
+	case *endless.U32Tag:
+		return fmt.Sprintf("%s = des.U32()\n", target)
+	case *endless.U64Tag:
+		return fmt.Sprintf("%s = des.U64()\n", target)
+	case *endless.U128Tag:
+		return fmt.Sprintf("%s = des.U128()\n", target)
+	case *endless.U256Tag:
+		return fmt.Sprintf("%s = des.U256()\n", target)
+	case *endless.ReferenceTag:
+		return g.deserialize(inner.TypeParam, target, depth)
+	case *endless.VectorTag:
+		if _, ok := inner.TypeParam.Value.(*endless.U8Tag); ok {
+			return fmt.Sprintf("%s = des.ReadBytes()\n", target)
+		}
+		itemType, _ := g.goType(inner.TypeParam, "")
+		// Grow as items are read, so a bad length doesn't allocate
+		item := fmt.Sprintf("item%d", depth)
+		return fmt.Sprintf("%s = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, %s *%s) {\n%s})\n", target, item, itemType, g.deserialize(inner.TypeParam, "*"+item, depth+1))
+	case *endless.StructTag:
+		switch {
+		case isFrameworkStruct(inner, "string", "String"):
+			return fmt.Sprintf("%s = des.ReadString()\n", target)
+		case isFrameworkStruct(inner, "option", "Option"):
+			g.imports["fmt"] = true
+			itemType, _ := g.goType(inner.TypeParams[0], "")
+			length := fmt.Sprintf("length%d", depth)
+			return fmt.Sprintf("if %s := des.Uleb128(); %s > 1 {\ndes.SetError(fmt.Errorf(\"invalid option length %%d\", %s))\n} else if %s == 1 {\n%s = new(%s)\n%s}\n",
+				length, length, length, length, target, itemType, g.deserialize(inner.TypeParams[0], "*"+target, depth+1))
+		}
+	}
+	// Addresses, objects, and the module's structs
+	return fmt.Sprintf("des.Struct(%s)\n", addressOf(target))
+}
+
+func (g *generator) isOwnStruct(tag *endless.StructTag) bool {
+	return tag.Address == g.address && tag.Module == g.abi.Name && len(tag.TypeParams) == 0
+}
+
+// uniqueName returns name, or if it's already in use, name with the suffix and then a number appended
+func (g *generator) uniqueName(name string, suffix string) string {
+	unique := name
+	if g.names[unique] {
+		name += suffix
+		unique = name
+	}
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// addressOf returns an expression for the address of expr, which is either a variable, field, index, or dereference
+func addressOf(expr string) string {
+	if strings.HasPrefix(expr, "*") && !strings.Contains(expr, "[") {
+		return expr[1:]
+	}
+	return "&" + expr
+}
+
+func isFrameworkStruct(tag *endless.StructTag, module string, name string) bool {
+	return tag.Address == endless.AccountOne && tag.Module == module && tag.Name == name
+}
+
+func isSigner(tag endless.TypeTag) bool {
+	if reference, ok := tag.Value.(*endless.ReferenceTag); ok {
+		tag = reference.TypeParam
+	}
+	_, ok := tag.Value.(*endless.SignerTag)
+	return ok
+}
+
+// goName converts a Move snake_case name to an exported Go name e.g. register_name to RegisterName
+func goName(moveName string) string {
+	out := strings.Builder{}
+	for _, part := range strings.Split(moveName, "_") {
+		if part != "" {
+			out.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if out.Len() == 0 {
+		return "X"
+	}
+	return out.String()
+}
+
+func prefixEach(prefix string, items []string) string {
+	out := strings.Builder{}
+	for _, item := range items {
+		out.WriteString(prefix + item)
+	}
+	return out.String()
+}
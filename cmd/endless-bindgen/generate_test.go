@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// The bindings in internal/registry are generated from testdata/registry.json, and tested there
+func TestGenerate_UpToDate(t *testing.T) {
+	abi, err := readAbi(filepath.Join("testdata", "registry.json"))
+	assert.NoError(t, err)
+	source, err := generate(abi, "registry")
+	assert.NoError(t, err)
+
+	committed, err := os.ReadFile(filepath.Join("internal", "registry", "registry.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(committed), string(source), "run go generate ./cmd/endless-bindgen")
+
+	// Unsupported types are skipped, rather than failing the whole module
+	assert.Contains(t, string(source), "// Skipped entry function take:")
+	assert.Contains(t, string(source), "// Skipped struct Holder:")
+	assert.Contains(t, string(source), "// Skipped struct Indexed:")
+	assert.NotContains(t, string(source), "func Borrow")
+}
+
+func TestRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "bindings", "bindings.go")
+	assert.NoError(t, run(filepath.Join("testdata", "registry.json"), "", "", "", "bindings", out))
+	source, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(source), "// Code generated by endless-bindgen"))
+	assert.Contains(t, string(source), "package bindings\n")
+
+	// The ABI on its own works too
+	path := filepath.Join(t.TempDir(), "abi.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"address":"0x1","name":"coin","friends":[],"exposed_functions":[],"structs":[]}`), 0o600))
+	assert.NoError(t, run(path, "", "", "", "", out))
+	source, err = os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Contains(t, string(source), "package coin\n")
+
+	assert.Error(t, run("", "", "", "", "", out))
+	assert.Error(t, run(filepath.Join(t.TempDir(), "missing.json"), "", "", "", "", out))
+	_, err = generate(&api.MoveModule{Name: "no_address"}, "x")
+	assert.Error(t, err)
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "RegisterName", goName("register_name"))
+	assert.Equal(t, "Entry", goName("Entry"))
+	assert.Equal(t, "X", goName("_"))
+}
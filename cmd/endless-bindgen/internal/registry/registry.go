@@ -0,0 +1,287 @@
+// Code generated by endless-bindgen from 111111111111111111111111111111GSy::registry. DO NOT EDIT.
+
+// Package registry has bindings for the 111111111111111111111111111111GSy::registry Move module
+//
+// Skipped struct Holder: generic and native structs aren't supported
+//
+// Skipped struct Indexed: field table: struct type 0x1::table::Table<u64,u64> isn't supported
+//
+// Skipped entry function take: struct type 0x1::table::Table<u64,u64> isn't supported
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/endless-labs/endless-go-sdk"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+)
+
+// ModuleAddress is the address the module is published at
+var ModuleAddress = endless.AccountAddress{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xca, 0xfe}
+
+// ModuleName is the name of the module
+const ModuleName = "registry"
+
+// Module identifies the module, for building payloads by hand
+var Module = endless.ModuleId{Address: ModuleAddress, Name: ModuleName}
+
+// Types of the structs in the module, for reading resources and filtering events
+const (
+	EntryType    = "111111111111111111111111111111GSy::registry::Entry"    // EntryType is the type of the Entry struct, e.g. for filtering events
+	KeyType      = "111111111111111111111111111111GSy::registry::Key"      // KeyType is the type of the Key struct, e.g. for filtering events
+	RegistryType = "111111111111111111111111111111GSy::registry::Registry" // RegistryType is the type of the Registry resource
+	HolderType   = "111111111111111111111111111111GSy::registry::Holder"   // HolderType is the type of the Holder resource
+	IndexedType  = "111111111111111111111111111111GSy::registry::Indexed"  // IndexedType is the type of the Indexed resource
+)
+
+// Entry is the 111111111111111111111111111111GSy::registry::Entry struct
+type Entry struct {
+	Owner     endless.AccountAddress `json:"owner"`      // Owner is address
+	Name      string                 `json:"name"`       // Name is 0x1::string::String
+	Key       Key                    `json:"key"`        // Key is 0xcafe::registry::Key
+	ExpiresAt *uint64                `json:"expires_at"` // ExpiresAt is 0x1::option::Option<u64>
+	Tags      [][]string             `json:"tags"`       // Tags is vector<vector<0x1::string::String>>
+}
+
+// MarshalBCS serializes the struct as stored on chain
+func (s *Entry) MarshalBCS(ser *bcs.Serializer) {
+	ser.Struct(&s.Owner)
+	ser.WriteString(s.Name)
+	ser.Struct(&s.Key)
+	if s.ExpiresAt == nil {
+		ser.Uleb128(0)
+	} else {
+		ser.Uleb128(1)
+		ser.U64(*s.ExpiresAt)
+	}
+	ser.Uleb128(uint32(len(s.Tags)))
+	for _, item0 := range s.Tags {
+		ser.Uleb128(uint32(len(item0)))
+		for _, item1 := range item0 {
+			ser.WriteString(item1)
+		}
+	}
+}
+
+// UnmarshalBCS deserializes the struct as stored on chain
+func (s *Entry) UnmarshalBCS(des *bcs.Deserializer) {
+	des.Struct(&s.Owner)
+	s.Name = des.ReadString()
+	des.Struct(&s.Key)
+	if length0 := des.Uleb128(); length0 > 1 {
+		des.SetError(fmt.Errorf("invalid option length %d", length0))
+	} else if length0 == 1 {
+		s.ExpiresAt = new(uint64)
+		*s.ExpiresAt = des.U64()
+	}
+	s.Tags = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, item0 *[]string) {
+		*item0 = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, item1 *string) {
+			*item1 = des.ReadString()
+		})
+	})
+}
+
+// Key is the 111111111111111111111111111111GSy::registry::Key struct
+type Key struct {
+	Id   big.Int `json:"id"`   // Id is u128
+	Hash []byte  `json:"hash"` // Hash is vector<u8>
+}
+
+// MarshalBCS serializes the struct as stored on chain
+func (s *Key) MarshalBCS(ser *bcs.Serializer) {
+	ser.U128(s.Id)
+	ser.WriteBytes(s.Hash)
+}
+
+// UnmarshalBCS deserializes the struct as stored on chain
+func (s *Key) UnmarshalBCS(des *bcs.Deserializer) {
+	s.Id = des.U128()
+	s.Hash = des.ReadBytes()
+}
+
+// Registry is the 111111111111111111111111111111GSy::registry::Registry struct
+type Registry struct {
+	Entries []Entry `json:"entries"` // Entries is vector<0xcafe::registry::Entry>
+	Count   uint64  `json:"count"`   // Count is u64
+}
+
+// MarshalBCS serializes the struct as stored on chain
+func (s *Registry) MarshalBCS(ser *bcs.Serializer) {
+	ser.Uleb128(uint32(len(s.Entries)))
+	for _, item0 := range s.Entries {
+		ser.Struct(&item0)
+	}
+	ser.U64(s.Count)
+}
+
+// UnmarshalBCS deserializes the struct as stored on chain
+func (s *Registry) UnmarshalBCS(des *bcs.Deserializer) {
+	s.Entries = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, item0 *Entry) {
+		des.Struct(item0)
+	})
+	s.Count = des.U64()
+}
+
+// Register builds a call to the entry function 111111111111111111111111111111GSy::registry::register
+func Register(arg0 string, arg1 []byte, arg2 big.Int, arg3 *endless.AccountAddress) (*endless.EntryFunction, error) {
+	args, err := serializeArgs(
+		func(ser *bcs.Serializer) {
+			ser.WriteString(arg0)
+		},
+		func(ser *bcs.Serializer) {
+			ser.WriteBytes(arg1)
+		},
+		func(ser *bcs.Serializer) {
+			ser.U128(arg2)
+		},
+		func(ser *bcs.Serializer) {
+			if arg3 == nil {
+				ser.Uleb128(0)
+			} else {
+				ser.Uleb128(1)
+				ser.Struct(arg3)
+			}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &endless.EntryFunction{
+		Module:   Module,
+		Function: "register",
+		ArgTypes: []endless.TypeTag{},
+		Args:     args,
+	}, nil
+}
+
+// SetTags builds a call to the entry function 111111111111111111111111111111GSy::registry::set_tags
+func SetTags(arg0 []string, arg1 [][]uint64, arg2 bool) (*endless.EntryFunction, error) {
+	args, err := serializeArgs(
+		func(ser *bcs.Serializer) {
+			ser.Uleb128(uint32(len(arg0)))
+			for _, item0 := range arg0 {
+				ser.WriteString(item0)
+			}
+		},
+		func(ser *bcs.Serializer) {
+			ser.Uleb128(uint32(len(arg1)))
+			for _, item0 := range arg1 {
+				ser.Uleb128(uint32(len(item0)))
+				for _, item1 := range item0 {
+					ser.U64(item1)
+				}
+			}
+		},
+		func(ser *bcs.Serializer) {
+			ser.Bool(arg2)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &endless.EntryFunction{
+		Module:   Module,
+		Function: "set_tags",
+		ArgTypes: []endless.TypeTag{},
+		Args:     args,
+	}, nil
+}
+
+// Deposit builds a call to the entry function 111111111111111111111111111111GSy::registry::deposit
+func Deposit(typeArg0 endless.TypeTag, arg0 endless.AccountAddress, arg1 uint64) (*endless.EntryFunction, error) {
+	args, err := serializeArgs(
+		func(ser *bcs.Serializer) {
+			ser.Struct(&arg0)
+		},
+		func(ser *bcs.Serializer) {
+			ser.U64(arg1)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &endless.EntryFunction{
+		Module:   Module,
+		Function: "deposit",
+		ArgTypes: []endless.TypeTag{typeArg0},
+		Args:     args,
+	}, nil
+}
+
+// Lookup calls the view function 111111111111111111111111111111GSy::registry::lookup
+func Lookup(client endless.ViewClient, arg0 endless.AccountAddress, ledgerVersion ...uint64) (r0 Entry, r1 big.Int, r2 *uint8, err error) {
+	args, err := serializeArgs(
+		func(ser *bcs.Serializer) {
+			ser.Struct(&arg0)
+		},
+	)
+	if err != nil {
+		return
+	}
+	values, err := client.View(&endless.ViewPayload{
+		Module:   Module,
+		Function: "lookup",
+		ArgTypes: []endless.TypeTag{},
+		Args:     args,
+	}, ledgerVersion...)
+	if err != nil {
+		return
+	}
+	err = decodeResults(values, &r0, &r1, &r2)
+	return
+}
+
+// EntryView calls the view function 111111111111111111111111111111GSy::registry::entry
+func EntryView(client endless.ViewClient, arg0 Key, ledgerVersion ...uint64) (r0 []Entry, err error) {
+	args, err := serializeArgs(
+		func(ser *bcs.Serializer) {
+			ser.Struct(&arg0)
+		},
+	)
+	if err != nil {
+		return
+	}
+	values, err := client.View(&endless.ViewPayload{
+		Module:   Module,
+		Function: "entry",
+		ArgTypes: []endless.TypeTag{},
+		Args:     args,
+	}, ledgerVersion...)
+	if err != nil {
+		return
+	}
+	err = decodeResults(values, &r0)
+	return
+}
+
+// serializeArgs serializes each argument on its own
+func serializeArgs(marshals ...func(ser *bcs.Serializer)) ([][]byte, error) {
+	args := make([][]byte, len(marshals))
+	for i, marshal := range marshals {
+		arg, err := bcs.SerializeSingle(marshal)
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %w", i, err)
+		}
+		args[i] = arg
+	}
+	return args, nil
+}
+
+// decodeResults decodes the JSON results of a view function into outs
+func decodeResults(values []any, outs ...any) error {
+	if len(values) != len(outs) {
+		return fmt.Errorf("expected %d results, got %d", len(outs), len(values))
+	}
+	for i, value := range values {
+		blob, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if err = endless.UnmarshalMoveJson(blob, outs[i]); err != nil {
+			return fmt.Errorf("result %d: %w", i, err)
+		}
+	}
+	return nil
+}
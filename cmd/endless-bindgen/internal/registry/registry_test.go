@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk"
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeViewClient answers every view call with results
+type fakeViewClient struct {
+	results  []any
+	payloads []*endless.ViewPayload
+}
+
+func (client *fakeViewClient) ModuleAbi(endless.AccountAddress, string, ...uint64) (*api.MoveModule, error) {
+	panic("bindings don't need the ABI")
+}
+
+func (client *fakeViewClient) View(payload *endless.ViewPayload, _ ...uint64) ([]any, error) {
+	client.payloads = append(client.payloads, payload)
+	return client.results, nil
+}
+
+func TestRegistry_BCS(t *testing.T) {
+	expires := uint64(99)
+	registry := &Registry{
+		Entries: []Entry{
+			{Owner: endless.AccountOne, Name: "one", Key: Key{Id: *big.NewInt(1), Hash: []byte{1}}, ExpiresAt: &expires, Tags: [][]string{{"a", "b"}, {}}},
+			{Owner: endless.AccountTwo, Name: "two", Key: Key{Id: *big.NewInt(2), Hash: []byte{}}, Tags: [][]string{}},
+		},
+		Count: 2,
+	}
+	blob, err := bcs.Serialize(registry)
+	assert.NoError(t, err)
+
+	decoded := &Registry{}
+	assert.NoError(t, bcs.Deserialize(decoded, blob))
+	assert.Equal(t, registry, decoded)
+
+	// An option with more than one value is rejected
+	entry, err := bcs.Serialize(&registry.Entries[1])
+	assert.NoError(t, err)
+	key, err := bcs.Serialize(&registry.Entries[1].Key)
+	assert.NoError(t, err)
+	entry[32+4+len(key)] = 2 // after the owner, name, and key
+	assert.Error(t, bcs.Deserialize(&Entry{}, entry))
+}
+
+func TestRegister(t *testing.T) {
+	entryFunction, err := Register("name", []byte{1, 2}, *big.NewInt(3), &endless.AccountTwo)
+	assert.NoError(t, err)
+	assert.Equal(t, Module, entryFunction.Module)
+	assert.Equal(t, "register", entryFunction.Function)
+	assert.Equal(t, [][]byte{
+		{4, 'n', 'a', 'm', 'e'},
+		{2, 1, 2},
+		append([]byte{3}, make([]byte, 15)...),
+		append([]byte{1}, endless.AccountTwo[:]...),
+	}, entryFunction.Args)
+
+	entryFunction, err = Register("", nil, big.Int{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0}, entryFunction.Args[3])
+
+	entryFunction, err = SetTags([]string{"a"}, [][]uint64{{1}}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{{1, 1, 'a'}, {1, 1, 1, 0, 0, 0, 0, 0, 0, 0}, {1}}, entryFunction.Args)
+
+	entryFunction, err = Deposit(endless.TypeTag{Value: &endless.U8Tag{}}, endless.AccountOne, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "u8", entryFunction.ArgTypes[0].String())
+}
+
+func TestLookup(t *testing.T) {
+	client := &fakeViewClient{results: []any{
+		map[string]any{"owner": "0x1", "name": "one", "key": map[string]any{"id": "5", "hash": "0x0102"}, "expires_at": map[string]any{"vec": []any{}}, "tags": []any{[]any{"a"}}},
+		"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+		map[string]any{"vec": []any{float64(7)}},
+	}}
+	entry, value, found, err := Lookup(client, endless.AccountTwo)
+	assert.NoError(t, err)
+	assert.Equal(t, Entry{Owner: endless.AccountOne, Name: "one", Key: Key{Id: *big.NewInt(5), Hash: []byte{1, 2}}, Tags: [][]string{{"a"}}}, entry)
+	assert.Equal(t, 256, value.BitLen())
+	assert.Equal(t, uint8(7), *found)
+	assert.Equal(t, "lookup", client.payloads[0].Function)
+	assert.Equal(t, [][]byte{endless.AccountTwo[:]}, client.payloads[0].Args)
+
+	client.results = client.results[:1]
+	_, _, _, err = Lookup(client, endless.AccountTwo)
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+// endless-bindgen generates a Go package with typed bindings for a Move module, from its ABI.  The package has a
+// constructor for each entry function, a wrapper for each view function, a struct with BCS serialization for each Move
+// struct, and the full type of each struct for reading resources and filtering events.
+//
+// The ABI is read from a JSON file, either as returned by the node's module endpoint or the ABI on its own:
+//
+//	endless-bindgen -abi registry.json -out registry/registry.go
+//
+// Or fetched from a node:
+//
+//	endless-bindgen -node https://rpc.endless.link/v1 -address 0x1 -module primary_fungible_store -out pfs/pfs.go
+//
+// It can be run with go generate:
+//
+//	//go:generate go run github.com/endless-labs/endless-go-sdk/cmd/endless-bindgen -abi registry.json -out registry.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/endless-labs/endless-go-sdk"
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+func main() {
+	abiPath := flag.String("abi", "", "JSON file with the module ABI")
+	node := flag.String("node", "", "URL of the node to fetch the module ABI from, instead of -abi")
+	address := flag.String("address", "", "address of the module, with -node")
+	module := flag.String("module", "", "name of the module, with -node")
+	packageName := flag.String("package", "", "name of the generated package, the module name by default")
+	out := flag.String("out", "", "file to write, stdout by default")
+	flag.Parse()
+
+	if err := run(*abiPath, *node, *address, *module, *packageName, *out); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "endless-bindgen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiPath, node, address, module, packageName, out string) error {
+	var abi *api.MoveModule
+	var err error
+	switch {
+	case abiPath != "" && node == "":
+		abi, err = readAbi(abiPath)
+	case abiPath == "" && node != "" && address != "" && module != "":
+		abi, err = fetchAbi(node, address, module)
+	default:
+		return errors.New("either -abi, or -node, -address, and -module are required")
+	}
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		packageName = abi.Name
+	}
+	source, err := generate(abi, packageName)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(out, source, 0o644)
+}
+
+// readAbi reads a module ABI, either as returned by the node e.g. {"bytecode": "0x...", "abi": {...}}, or on its own
+func readAbi(path string) (*api.MoveModule, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	bytecode := api.MoveBytecode{}
+	if err = json.Unmarshal(blob, &bytecode); err != nil {
+		return nil, fmt.Errorf("%s: bad module ABI JSON: %w", path, err)
+	}
+	if bytecode.Abi != nil {
+		return bytecode.Abi, nil
+	}
+	abi := &api.MoveModule{}
+	if err = json.Unmarshal(blob, abi); err != nil {
+		return nil, fmt.Errorf("%s: bad module ABI JSON: %w", path, err)
+	}
+	return abi, nil
+}
+
+func fetchAbi(node, address, module string) (*api.MoveModule, error) {
+	client, err := endless.NewNodeClient(node, 0)
+	if err != nil {
+		return nil, err
+	}
+	moduleAddress := endless.AccountAddress{}
+	if err = moduleAddress.ParseStringRelaxed(address); err != nil {
+		return nil, err
+	}
+	return client.ModuleAbi(moduleAddress, module)
+}
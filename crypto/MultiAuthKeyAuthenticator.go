@@ -47,19 +47,8 @@ func (ea *MultiAuthKeyAuthenticator) MarshalBCS(ser *bcs.Serializer) {
 	bcs.SerializeSequence(ea.Signatures, ser)
 }
 func (ea *MultiAuthKeyAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
-	length := des.Uleb128()
-	ea.PubKeys = make([]*AnyPublicKey, length)
-	for i := uint32(0); i < length; i++ {
-		ea.PubKeys[i] = &AnyPublicKey{}
-		des.Struct(ea.PubKeys[i])
-	}
-
-	length = des.Uleb128()
-	ea.Signatures = make([]*AnySignature, length)
-	for i := uint32(0); i < length; i++ {
-		ea.Signatures[i] = &AnySignature{}
-		des.Struct(ea.Signatures[i])
-	}
+	ea.PubKeys = deserializePointers[AnyPublicKey](des)
+	ea.Signatures = deserializePointers[AnySignature](des)
 }
 
 func fromAuthenticator(auth *AccountAuthenticator) (*AnyPublicKey, *AnySignature, error) {
@@ -120,7 +120,7 @@ func (ak *AuthenticationKey) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (ak *AuthenticationKey) UnmarshalBCS(des *bcs.Deserializer) {
-	length := des.Uleb128()
+	length := des.SequenceLength()
 	if length != AuthenticationKeyLength {
 		des.SetError(fmt.Errorf("authentication key has wrong length %d", length))
 		return
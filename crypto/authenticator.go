@@ -2,7 +2,6 @@ package crypto
 
 import (
 	"errors"
-	"fmt"
 	"github.com/endless-labs/endless-go-sdk/bcs"
 )
 
@@ -53,6 +52,14 @@ type AccountAuthenticator struct {
 	Auth    AccountAuthenticatorImpl // Auth is the actual authenticator
 }
 
+// accountAuthenticatorEnum has the variants of [AccountAuthenticator]
+var accountAuthenticatorEnum = bcs.NewEnum[AccountAuthenticatorImpl]("AccountAuthenticator").
+	Register(uint32(AccountAuthenticatorEd25519), func() AccountAuthenticatorImpl { return &Ed25519Authenticator{} }).
+	Register(uint32(AccountAuthenticatorMultiEd25519), func() AccountAuthenticatorImpl { return &MultiEd25519Authenticator{} }).
+	Register(uint32(AccountAuthenticatorSingleSender), func() AccountAuthenticatorImpl { return &SingleKeyAuthenticator{} }).
+	Register(uint32(AccountAuthenticatorMultiKey), func() AccountAuthenticatorImpl { return &MultiKeyAuthenticator{} }).
+	Register(uint32(AccountAuthenticatorMultiAuthKey), func() AccountAuthenticatorImpl { return &MultiAuthKeyAuthenticator{} })
+
 //region AccountAuthenticator AccountAuthenticatorImpl implementation
 
 // PubKey returns the public key of the authenticator
@@ -79,8 +86,7 @@ func (ea *AccountAuthenticator) Verify(data []byte) bool {
 // Implements:
 //   - [bcs.Marshaler]
 func (ea *AccountAuthenticator) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(ea.Variant))
-	ea.Auth.MarshalBCS(ser)
+	accountAuthenticatorEnum.Marshal(ser, uint32(ea.Variant), ea.Auth)
 }
 
 // UnmarshalBCS deserializes the [AccountAuthenticator] from the BCS format
@@ -88,28 +94,8 @@ func (ea *AccountAuthenticator) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (ea *AccountAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
-	kindNum := des.Uleb128()
-	if des.Error() != nil {
-		return
-	}
-
-	ea.Variant = AccountAuthenticatorType(kindNum)
-	switch ea.Variant {
-	case AccountAuthenticatorEd25519:
-		ea.Auth = &Ed25519Authenticator{}
-	case AccountAuthenticatorMultiEd25519:
-		ea.Auth = &MultiEd25519Authenticator{}
-	case AccountAuthenticatorSingleSender:
-		ea.Auth = &SingleKeyAuthenticator{}
-	case AccountAuthenticatorMultiKey:
-		ea.Auth = &MultiKeyAuthenticator{}
-	case AccountAuthenticatorMultiAuthKey:
-		ea.Auth = &MultiAuthKeyAuthenticator{}
-	default:
-		des.SetError(fmt.Errorf("unknown AccountAuthenticator kind: %d", kindNum))
-		return
-	}
-	ea.Auth.UnmarshalBCS(des)
+	variant, auth := accountAuthenticatorEnum.Unmarshal(des)
+	ea.Variant, ea.Auth = AccountAuthenticatorType(variant), auth
 }
 
 func (ea *AccountAuthenticator) FromKeyAndSignature(key PublicKey, sig Signature) error {
@@ -136,12 +136,7 @@ func (key *MultiKey) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (key *MultiKey) UnmarshalBCS(des *bcs.Deserializer) {
-	length := des.Uleb128()
-	key.PubKeys = make([]*AnyPublicKey, length)
-	for i := uint32(0); i < length; i++ {
-		key.PubKeys[i] = &AnyPublicKey{}
-		des.Struct(key.PubKeys[i])
-	}
+	key.PubKeys = deserializePointers[AnyPublicKey](des)
 
 	key.SignaturesRequired = des.U8()
 }
@@ -261,13 +256,7 @@ func (e *MultiKeySignature) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (e *MultiKeySignature) UnmarshalBCS(des *bcs.Deserializer) {
-	length := des.Uleb128()
-	e.Signatures = make([]*AnySignature, length)
-
-	for i := uint32(0); i < length; i++ {
-		e.Signatures[i] = &AnySignature{}
-		des.Struct(e.Signatures[i])
-	}
+	e.Signatures = deserializePointers[AnySignature](des)
 
 	e.Bitmap.UnmarshalBCS(des)
 }
@@ -427,8 +416,8 @@ func (bm *MultiKeyBitmap) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (bm *MultiKeyBitmap) UnmarshalBCS(des *bcs.Deserializer) {
-	length := des.Uleb128()
-	if length > uint32(MaxMultiKeyBitmapBytes) {
+	length := des.SequenceLength()
+	if length > int(MaxMultiKeyBitmapBytes) {
 		des.SetError(fmt.Errorf("MultiKeyBitmap must be %d bytes or less, got %d", MaxMultiKeyBitmapBytes, length))
 		return
 	}
@@ -438,3 +427,15 @@ func (bm *MultiKeyBitmap) UnmarshalBCS(des *bcs.Deserializer) {
 
 //endregion
 //endregion
+
+// deserializePointers deserializes a sequence of pointers to T, growing it as items arrive, so the length is checked
+// against the deserializer's limits
+func deserializePointers[T any, PT interface {
+	*T
+	bcs.Unmarshaler
+}](des *bcs.Deserializer) []PT {
+	return bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, out *PT) {
+		*out = PT(new(T))
+		des.Struct(*out)
+	})
+}
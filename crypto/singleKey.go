@@ -140,6 +140,11 @@ type AnyPublicKey struct {
 	PubKey  VerifyingKey        // PubKey is the actual public key
 }
 
+// anyPublicKeyEnum has the variants of [AnyPublicKey]
+var anyPublicKeyEnum = bcs.NewEnum[VerifyingKey]("AnyPublicKey").
+	Register(uint32(AnyPublicKeyVariantEd25519), func() VerifyingKey { return &Ed25519PublicKey{} }).
+	Register(uint32(AnyPublicKeyVariantSecp256k1), func() VerifyingKey { return &Secp256k1PublicKey{} })
+
 // ToAnyPublicKey converts a [VerifyingKey] to an [AnyPublicKey]
 func ToAnyPublicKey(key VerifyingKey) (*AnyPublicKey, error) {
 	out := &AnyPublicKey{}
@@ -249,8 +254,7 @@ func (key *AnyPublicKey) FromHex(hexStr string) (err error) {
 // Implements:
 //   - [bcs.Marshaler]
 func (key *AnyPublicKey) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(key.Variant))
-	ser.Struct(key.PubKey)
+	anyPublicKeyEnum.Marshal(ser, uint32(key.Variant), key.PubKey)
 }
 
 // UnmarshalBCS deserializes the [AnyPublicKey] from bytes
@@ -258,17 +262,8 @@ func (key *AnyPublicKey) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (key *AnyPublicKey) UnmarshalBCS(des *bcs.Deserializer) {
-	key.Variant = AnyPublicKeyVariant(des.Uleb128())
-	switch key.Variant {
-	case AnyPublicKeyVariantEd25519:
-		key.PubKey = &Ed25519PublicKey{}
-	case AnyPublicKeyVariantSecp256k1:
-		key.PubKey = &Secp256k1PublicKey{}
-	default:
-		des.SetError(fmt.Errorf("unknown public key variant: %d", key.Variant))
-		return
-	}
-	des.Struct(key.PubKey)
+	variant, pubKey := anyPublicKeyEnum.Unmarshal(des)
+	key.Variant, key.PubKey = AnyPublicKeyVariant(variant), pubKey
 }
 
 //endregion
@@ -297,6 +292,11 @@ type AnySignature struct {
 	Signature Signature
 }
 
+// anySignatureEnum has the variants of [AnySignature]
+var anySignatureEnum = bcs.NewEnum[Signature]("AnySignature").
+	Register(uint32(AnySignatureVariantEd25519), func() Signature { return &Ed25519Signature{} }).
+	Register(uint32(AnySignatureVariantSecp256k1), func() Signature { return &Secp256k1Signature{} })
+
 // region AnySignature CryptoMaterial implementation
 
 // Bytes returns the raw bytes of the [AnySignature]
@@ -345,8 +345,7 @@ func (e *AnySignature) FromHex(hexStr string) (err error) {
 // Implements:
 //   - [bcs.Marshaler]
 func (e *AnySignature) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(e.Variant))
-	ser.Struct(e.Signature)
+	anySignatureEnum.Marshal(ser, uint32(e.Variant), e.Signature)
 }
 
 // UnmarshalBCS deserializes the [AnySignature] from bytes
@@ -354,17 +353,8 @@ func (e *AnySignature) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (e *AnySignature) UnmarshalBCS(des *bcs.Deserializer) {
-	e.Variant = AnySignatureVariant(des.Uleb128())
-	switch e.Variant {
-	case AnySignatureVariantEd25519:
-		e.Signature = &Ed25519Signature{}
-	case AnySignatureVariantSecp256k1:
-		e.Signature = &Secp256k1Signature{}
-	default:
-		des.SetError(fmt.Errorf("unknown signature variant: %d", e.Variant))
-		return
-	}
-	des.Struct(e.Signature)
+	variant, signature := anySignatureEnum.Unmarshal(des)
+	e.Variant, e.Signature = AnySignatureVariant(variant), signature
 }
 
 //endregion
@@ -0,0 +1,388 @@
+package endless
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStaleLedgerVersion is returned when every endpoint is behind a ledger version already observed by the client, so
+// serving the read would go backwards in time.
+var ErrStaleLedgerVersion = errors.New("all endpoints are behind the last observed ledger version")
+
+// EndpointStrategy decides which endpoint a multi-endpoint [NodeClient] sends reads to first
+type EndpointStrategy uint8
+
+const (
+	// EndpointStrategyRoundRobin rotates reads between the healthy endpoints
+	EndpointStrategyRoundRobin EndpointStrategy = iota
+	// EndpointStrategyFreshest sends reads to the healthy endpoint with the highest known ledger version
+	EndpointStrategyFreshest
+)
+
+// MaxLedgerLag is an option to [NewNodeClientWithEndpoints], endpoints further behind the freshest endpoint than this
+// number of versions are considered unhealthy.  Defaults to 1000.
+type MaxLedgerLag uint64
+
+// FailoverCooldown is an option to [NewNodeClientWithEndpoints], how long an endpoint that failed a request is avoided
+// before it is tried again.  Defaults to 10 seconds.
+type FailoverCooldown time.Duration
+
+// EndpointStatus is a snapshot of what the client knows about one endpoint
+type EndpointStatus struct {
+	Url           string    // Url is the base URL of the endpoint
+	Healthy       bool      // Healthy is false if the endpoint has recently failed, or is lagging too far behind
+	LedgerVersion uint64    // LedgerVersion is the latest ledger version seen from the endpoint
+	LastError     error     // LastError is the most recent failure of the endpoint, nil if the last request succeeded
+	LastChecked   time.Time // LastChecked is the last time the endpoint answered or failed a request
+}
+
+// NewNodeClientWithEndpoints creates a client that balances requests across several full nodes, and fails over
+// between them on errors.  The first URL is the primary.  If client is nil, a default http.Client is used.
+//
+// Reads never go backwards in ledger version: once a ledger version has been seen from any endpoint, endpoints behind
+// it are skipped.
+//
+// Accepts options:
+//   - [EndpointStrategy]
+//   - [MaxLedgerLag]
+//   - [FailoverCooldown]
+//
+// Use [NodeClient.CheckEndpoints] or [NodeClient.StartHealthChecks] to probe the endpoints.
+func NewNodeClientWithEndpoints(rpcUrls []string, chainId uint8, client *http.Client, options ...any) (*NodeClient, error) {
+	if len(rpcUrls) == 0 {
+		return nil, errors.New("at least one RPC url is required")
+	}
+	pool := &endpointPool{
+		strategy: EndpointStrategyRoundRobin,
+		maxLag:   1000,
+		cooldown: 10 * time.Second,
+	}
+	for i, option := range options {
+		switch value := option.(type) {
+		case EndpointStrategy:
+			pool.strategy = value
+		case MaxLedgerLag:
+			pool.maxLag = uint64(value)
+		case FailoverCooldown:
+			pool.cooldown = time.Duration(value)
+		default:
+			return nil, fmt.Errorf("NewNodeClientWithEndpoints arg [%d] unknown option type %T", i+4, option)
+		}
+	}
+	for _, rpcUrl := range rpcUrls {
+		baseUrl, err := url.Parse(rpcUrl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RPC url '%s': %w", rpcUrl, err)
+		}
+		pool.endpoints = append(pool.endpoints, &endpoint{baseUrl: baseUrl, healthy: true})
+	}
+
+	if client == nil {
+		var err error
+		client, err = newDefaultHttpClient()
+		if err != nil {
+			return nil, err
+		}
+	}
+	rc, err := NewNodeClientWithHttpClient(rpcUrls[0], chainId, client)
+	if err != nil {
+		return nil, err
+	}
+	rc.endpoints = pool
+	return rc, nil
+}
+
+// Endpoints returns the status of each endpoint, nil if the client only has a single endpoint
+func (rc *NodeClient) Endpoints() []EndpointStatus {
+	if rc.endpoints == nil {
+		return nil
+	}
+	return rc.endpoints.statuses()
+}
+
+// CheckEndpoints probes every endpoint concurrently with a health check and [NodeClient.Info], updating their health
+// and ledger versions.  It returns the updated statuses.
+func (rc *NodeClient) CheckEndpoints() []EndpointStatus {
+	if rc.endpoints == nil {
+		return nil
+	}
+	var wg sync.WaitGroup
+	for _, ep := range rc.endpoints.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rc.probe(ep)
+		}()
+	}
+	wg.Wait()
+	rc.endpoints.applyLag()
+	return rc.endpoints.statuses()
+}
+
+// StartHealthChecks probes the endpoints every interval in the background, until stop is called or the client's
+// context is cancelled.
+//
+//	stop := client.StartHealthChecks(5 * time.Second)
+//	defer stop()
+func (rc *NodeClient) StartHealthChecks(interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(rc.Context())
+	view := rc.WithContext(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			view.CheckEndpoints()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return cancel
+}
+
+// probe checks a single endpoint directly, bypassing failover
+func (rc *NodeClient) probe(ep *endpoint) {
+	base := ep.baseUrl.String()
+	_, err := rc.sendTo(ep.baseUrl.JoinPath("-/healthy").String(), &nodeRequest{method: http.MethodGet, kind: requestKindRead, operation: "GET /-/healthy"})
+	if err != nil {
+		ep.markFailed(err, rc.endpoints.cooldown)
+		return
+	}
+	response, err := rc.sendTo(base, &nodeRequest{method: http.MethodGet, kind: requestKindRead, operation: "GET /"})
+	if err != nil {
+		ep.markFailed(err, rc.endpoints.cooldown)
+		return
+	}
+	version, ok := ledgerVersionFromHeader(response.header)
+	if !ok {
+		// Fall back to the body, if the node didn't send the header
+		info := NodeInfo{}
+		if jsonErr := json.Unmarshal(response.body, &info); jsonErr == nil {
+			version = info.LedgerVersion()
+		}
+	}
+	ep.markHealthy(version)
+}
+
+// endpoint is a single full node in an [endpointPool]
+type endpoint struct {
+	baseUrl *url.URL
+
+	mu            sync.Mutex
+	healthy       bool      // healthy is false if the last request failed
+	lagging       bool      // lagging is true if the last probe found the endpoint too far behind
+	ledgerVersion uint64    // ledgerVersion is the latest ledger version seen
+	lastErr       error     // lastErr is the last failure, nil if the last request succeeded
+	lastChecked   time.Time // lastChecked is the time of the last request
+	avoidUntil    time.Time // avoidUntil is when the endpoint can be preferred again after failing or falling behind
+}
+
+func (ep *endpoint) markHealthy(version uint64) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.healthy = true
+	ep.lastErr = nil
+	ep.lastChecked = time.Now()
+	ep.avoidUntil = time.Time{}
+	ep.ledgerVersion = max(ep.ledgerVersion, version)
+}
+
+func (ep *endpoint) markFailed(err error, cooldown time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.healthy = false
+	ep.lastErr = err
+	ep.lastChecked = time.Now()
+	ep.avoidUntil = ep.lastChecked.Add(cooldown)
+}
+
+// markBehind records a ledger version that is behind what the client has already seen, the endpoint is still
+// healthy but is avoided until it has had time to catch up
+func (ep *endpoint) markBehind(version uint64, cooldown time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.ledgerVersion = version
+	ep.lastChecked = time.Now()
+	ep.avoidUntil = ep.lastChecked.Add(cooldown)
+}
+
+// available is true if the endpoint can be used first, rather than as a last resort
+func (ep *endpoint) available(now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return !ep.lagging && !now.Before(ep.avoidUntil)
+}
+
+func (ep *endpoint) status() EndpointStatus {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return EndpointStatus{
+		Url:           ep.baseUrl.String(),
+		Healthy:       ep.healthy && !ep.lagging,
+		LedgerVersion: ep.ledgerVersion,
+		LastError:     ep.lastErr,
+		LastChecked:   ep.lastChecked,
+	}
+}
+
+// endpointPool selects endpoints for a [NodeClient], and keeps track of the highest ledger version seen
+type endpointPool struct {
+	endpoints []*endpoint
+	strategy  EndpointStrategy
+	maxLag    uint64
+	cooldown  time.Duration
+
+	next      atomic.Uint64 // next is the round-robin counter
+	highWater atomic.Uint64 // highWater is the highest ledger version returned to the caller
+}
+
+func (pool *endpointPool) statuses() []EndpointStatus {
+	out := make([]EndpointStatus, len(pool.endpoints))
+	for i, ep := range pool.endpoints {
+		out[i] = ep.status()
+	}
+	return out
+}
+
+// applyLag marks endpoints that are too far behind the freshest endpoint as lagging
+func (pool *endpointPool) applyLag() {
+	freshest := uint64(0)
+	for _, ep := range pool.endpoints {
+		freshest = max(freshest, ep.status().LedgerVersion)
+	}
+	for _, ep := range pool.endpoints {
+		ep.mu.Lock()
+		ep.lagging = ep.ledgerVersion+pool.maxLag < freshest
+		ep.mu.Unlock()
+	}
+}
+
+// raiseHighWater records a ledger version seen by the caller
+func (pool *endpointPool) raiseHighWater(version uint64) {
+	for {
+		current := pool.highWater.Load()
+		if version <= current || pool.highWater.CompareAndSwap(current, version) {
+			return
+		}
+	}
+}
+
+// candidates orders the endpoints to try for a request, available endpoints first according to the strategy, and
+// the rest after as a last resort
+func (pool *endpointPool) candidates() []*endpoint {
+	now := time.Now()
+	preferred := make([]*endpoint, 0, len(pool.endpoints))
+	fallback := make([]*endpoint, 0)
+	for _, ep := range pool.endpoints {
+		if ep.available(now) {
+			preferred = append(preferred, ep)
+		} else {
+			fallback = append(fallback, ep)
+		}
+	}
+
+	switch pool.strategy {
+	case EndpointStrategyFreshest:
+		slices.SortStableFunc(preferred, func(a, b *endpoint) int {
+			versionA, versionB := a.status().LedgerVersion, b.status().LedgerVersion
+			switch {
+			case versionA > versionB:
+				return -1
+			case versionA < versionB:
+				return 1
+			default:
+				return 0
+			}
+		})
+	default:
+		if len(preferred) > 1 {
+			offset := int(pool.next.Add(1) % uint64(len(preferred)))
+			preferred = slices.Concat(preferred[offset:], preferred[:offset])
+		}
+	}
+	return append(preferred, fallback...)
+}
+
+// send tries the request against each candidate endpoint in turn, until one gives an answer
+func (pool *endpointPool) send(rc *NodeClient, request *nodeRequest) (*nodeResponse, error) {
+	primary := rc.baseUrl.String()
+	var lastErr error
+	for _, ep := range pool.candidates() {
+		requestUrl := request.url
+		if rest, ok := strings.CutPrefix(requestUrl, primary); ok {
+			requestUrl = ep.baseUrl.String() + rest
+		}
+
+		response, err := rc.sendTo(requestUrl, request)
+		if err != nil && rc.Context().Err() != nil {
+			// The caller gave up, it's not the endpoint's fault
+			return nil, err
+		}
+
+		// Errors from the node still carry the ledger version it answered at
+		var header http.Header
+		var httpErr *HttpError
+		if response != nil {
+			header = response.header
+		} else if errors.As(err, &httpErr) {
+			header = httpErr.Header
+		}
+		version, hasVersion := ledgerVersionFromHeader(header)
+
+		if err != nil && shouldFailover(err) {
+			ep.markFailed(err, pool.cooldown)
+			lastErr = err
+			if request.kind == requestKindWrite {
+				// The request may have been processed, so it's not safe to send it elsewhere
+				return nil, err
+			}
+			continue
+		}
+		if request.kind == requestKindRead && hasVersion && version < pool.highWater.Load() {
+			ep.markBehind(version, pool.cooldown)
+			lastErr = fmt.Errorf("%w: %s is at %d, already seen %d", ErrStaleLedgerVersion, ep.baseUrl, version, pool.highWater.Load())
+			continue
+		}
+
+		ep.markHealthy(version)
+		if hasVersion {
+			pool.raiseHighWater(version)
+		}
+		return response, err
+	}
+	return nil, lastErr
+}
+
+// shouldFailover is true for errors that say something about the endpoint, rather than the request
+func shouldFailover(err error) bool {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	return true
+}
+
+// ledgerVersionFromHeader reads the ledger version the node answered at
+func ledgerVersionFromHeader(header http.Header) (uint64, bool) {
+	value := header.Get(HeaderLedgerVersion)
+	if value == "" {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
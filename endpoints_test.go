@@ -0,0 +1,136 @@
+package endless
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEndpoint starts a test node that reports the given ledger version on every response
+func newTestEndpoint(t *testing.T, version *atomic.Uint64, calls *atomic.Int32, status int) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set(HeaderLedgerVersion, strconv.FormatUint(version.Load(), 10))
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"chain_id":4,"ledger_version":"` + strconv.FormatUint(version.Load(), 10) + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/v1"
+}
+
+func TestEndpoints_RoundRobin(t *testing.T) {
+	var version atomic.Uint64
+	version.Store(10)
+	var callsA, callsB atomic.Int32
+	client, err := NewNodeClientWithEndpoints([]string{
+		newTestEndpoint(t, &version, &callsA, http.StatusOK),
+		newTestEndpoint(t, &version, &callsB, http.StatusOK),
+	}, 4, nil)
+	assert.NoError(t, err)
+
+	for range 4 {
+		_, err = client.Info()
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), callsA.Load())
+	assert.Equal(t, int32(2), callsB.Load())
+}
+
+func TestEndpoints_Failover(t *testing.T) {
+	var version atomic.Uint64
+	version.Store(10)
+	var callsDown, callsUp atomic.Int32
+	client, err := NewNodeClientWithEndpoints([]string{
+		newTestEndpoint(t, &version, &callsDown, http.StatusServiceUnavailable),
+		newTestEndpoint(t, &version, &callsUp, http.StatusOK),
+	}, 4, nil)
+	assert.NoError(t, err)
+
+	for range 3 {
+		info, err := client.Info()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), info.LedgerVersion())
+	}
+	// The failed endpoint is skipped while cooling down
+	assert.Equal(t, int32(1), callsDown.Load())
+	assert.Equal(t, int32(3), callsUp.Load())
+
+	statuses := client.Endpoints()
+	assert.False(t, statuses[0].Healthy)
+	assert.Error(t, statuses[0].LastError)
+	assert.True(t, statuses[1].Healthy)
+}
+
+func TestEndpoints_NeverReadsBackwards(t *testing.T) {
+	var fresh, stale atomic.Uint64
+	fresh.Store(100)
+	stale.Store(50)
+	var callsFresh, callsStale atomic.Int32
+	client, err := NewNodeClientWithEndpoints([]string{
+		newTestEndpoint(t, &fresh, &callsFresh, http.StatusOK),
+		newTestEndpoint(t, &stale, &callsStale, http.StatusOK),
+	}, 4, nil)
+	assert.NoError(t, err)
+
+	// Once the fresh endpoint has been read, the stale one is never used again
+	seen := uint64(0)
+	for range 6 {
+		info, err := client.Info()
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, info.LedgerVersion(), seen)
+		seen = info.LedgerVersion()
+	}
+	assert.Equal(t, uint64(100), seen)
+
+	// With every endpoint behind, the read fails instead of going backwards
+	client, err = NewNodeClientWithEndpoints([]string{
+		newTestEndpoint(t, &stale, &callsStale, http.StatusOK),
+	}, 4, nil)
+	assert.NoError(t, err)
+	client.endpoints.raiseHighWater(100)
+	_, err = client.Info()
+	assert.ErrorIs(t, err, ErrStaleLedgerVersion)
+}
+
+func TestEndpoints_CheckEndpoints(t *testing.T) {
+	var fresh, stale atomic.Uint64
+	fresh.Store(5000)
+	stale.Store(10)
+	var callsFresh, callsStale atomic.Int32
+	client, err := NewNodeClientWithEndpoints([]string{
+		newTestEndpoint(t, &fresh, &callsFresh, http.StatusOK),
+		newTestEndpoint(t, &stale, &callsStale, http.StatusOK),
+	}, 4, nil, EndpointStrategyFreshest, MaxLedgerLag(100))
+	assert.NoError(t, err)
+
+	statuses := client.CheckEndpoints()
+	assert.Equal(t, uint64(5000), statuses[0].LedgerVersion)
+	assert.Equal(t, uint64(10), statuses[1].LedgerVersion)
+	assert.True(t, statuses[0].Healthy)
+	assert.False(t, statuses[1].Healthy)
+
+	callsStale.Store(0)
+	_, err = client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), callsStale.Load())
+}
+
+func TestNewClient_EndpointOptions(t *testing.T) {
+	config := NetworkConfig{Name: "local", ChainId: 4, NodeUrl: "http://127.0.0.1:8080/v1"}
+
+	// Endpoint options do nothing for a single node, so they're a mistake
+	_, err := NewClient(config, MaxLedgerLag(5))
+	assert.ErrorContains(t, err, "needs NetworkConfig.NodeUrls")
+
+	config.NodeUrls = []string{"http://127.0.0.1:8081/v1"}
+	_, err = NewClient(config, MaxLedgerLag(5))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,209 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// Errors returned by the node, matched with [errors.Is] against the error of any client call
+//
+//	_, err := client.SubmitTransaction(signedTxn)
+//	if errors.Is(err, endless.ErrSequenceNumberTooOld) {
+//		// refresh the sequence number and rebuild the transaction
+//	}
+var (
+	ErrAccountNotFound       = errors.New("account not found")
+	ErrResourceNotFound      = errors.New("resource not found")
+	ErrModuleNotFound        = errors.New("module not found")
+	ErrTableItemNotFound     = errors.New("table item not found")
+	ErrTransactionNotFound   = errors.New("transaction not found")
+	ErrVersionNotFound       = errors.New("ledger version not found")
+	ErrSequenceNumberTooOld  = errors.New("sequence number too old")
+	ErrSequenceNumberTooNew  = errors.New("sequence number too new")
+	ErrMempoolFull           = errors.New("mempool is full")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrInsufficientBalance   = errors.New("insufficient balance for transaction fee")
+	ErrTransactionRejected   = errors.New("transaction rejected by the VM")
+	ErrTransactionNotApplied = errors.New("transaction not applied")
+)
+
+// ErrTransactionExpired is the error waiting for a transaction that expired without being committed.  It can never be
+// committed, so it's safe to build a new transaction with the same sequence number.
+var ErrTransactionExpired = errors.New("transaction expired")
+
+// apiErrorCodes maps the node's error_code to the matching error
+var apiErrorCodes = map[string]error{
+	"account_not_found":       ErrAccountNotFound,
+	"resource_not_found":      ErrResourceNotFound,
+	"module_not_found":        ErrModuleNotFound,
+	"table_item_not_found":    ErrTableItemNotFound,
+	"transaction_not_found":   ErrTransactionNotFound,
+	"version_not_found":       ErrVersionNotFound,
+	"sequence_number_too_old": ErrSequenceNumberTooOld,
+	"mempool_is_full":         ErrMempoolFull,
+	"invalid_signature":       ErrInvalidSignature,
+	"vm_error":                ErrTransactionRejected,
+}
+
+// vmErrorCodes maps the node's vm_error_code, the VM's StatusCode, to the matching error
+var vmErrorCodes = map[uint64]error{
+	1: ErrInvalidSignature,     // INVALID_SIGNATURE
+	3: ErrSequenceNumberTooOld, // SEQUENCE_NUMBER_TOO_OLD
+	4: ErrSequenceNumberTooNew, // SEQUENCE_NUMBER_TOO_NEW
+	5: ErrInsufficientBalance,  // INSUFFICIENT_BALANCE_FOR_TRANSACTION_FEE
+	7: ErrAccountNotFound,      // SENDING_ACCOUNT_DOES_NOT_EXIST
+}
+
+// apiErrors returns the errors matching a node's error body
+func apiErrors(apiErr *api.Error) []error {
+	out := make([]error, 0, 2)
+	if err, ok := apiErrorCodes[apiErr.ErrorCode]; ok {
+		out = append(out, err)
+	}
+	if err, ok := vmErrorCodes[apiErr.VmErrorCode]; ok && apiErr.VmErrorCode != 0 {
+		out = append(out, err)
+	}
+	return out
+}
+
+// AbortCategory is the category of a Move abort code, as defined by the std::error module
+type AbortCategory uint8
+
+const (
+	AbortCategoryInvalidArgument   AbortCategory = 0x1
+	AbortCategoryOutOfRange        AbortCategory = 0x2
+	AbortCategoryInvalidState      AbortCategory = 0x3
+	AbortCategoryUnauthenticated   AbortCategory = 0x4
+	AbortCategoryPermissionDenied  AbortCategory = 0x5
+	AbortCategoryNotFound          AbortCategory = 0x6
+	AbortCategoryAborted           AbortCategory = 0x7
+	AbortCategoryAlreadyExists     AbortCategory = 0x8
+	AbortCategoryResourceExhausted AbortCategory = 0x9
+	AbortCategoryCancelled         AbortCategory = 0xA
+	AbortCategoryInternal          AbortCategory = 0xB
+	AbortCategoryNotImplemented    AbortCategory = 0xC
+	AbortCategoryUnavailable       AbortCategory = 0xD
+)
+
+var abortCategoryNames = map[AbortCategory]string{
+	AbortCategoryInvalidArgument:   "INVALID_ARGUMENT",
+	AbortCategoryOutOfRange:        "OUT_OF_RANGE",
+	AbortCategoryInvalidState:      "INVALID_STATE",
+	AbortCategoryUnauthenticated:   "UNAUTHENTICATED",
+	AbortCategoryPermissionDenied:  "PERMISSION_DENIED",
+	AbortCategoryNotFound:          "NOT_FOUND",
+	AbortCategoryAborted:           "ABORTED",
+	AbortCategoryAlreadyExists:     "ALREADY_EXISTS",
+	AbortCategoryResourceExhausted: "RESOURCE_EXHAUSTED",
+	AbortCategoryCancelled:         "CANCELLED",
+	AbortCategoryInternal:          "INTERNAL",
+	AbortCategoryNotImplemented:    "NOT_IMPLEMENTED",
+	AbortCategoryUnavailable:       "UNAVAILABLE",
+}
+
+// String returns the std::error name of the category e.g. "NOT_FOUND"
+func (category AbortCategory) String() string {
+	if name, ok := abortCategoryNames[category]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(category))
+}
+
+// MoveAbortError is a transaction that failed because a Move function aborted
+//
+//	abort := &endless.MoveAbortError{}
+//	if errors.As(err, &abort) && abort.Category == endless.AbortCategoryNotFound {
+//		// handle the missing on-chain state
+//	}
+type MoveAbortError struct {
+	VmStatus    string        // VmStatus is the full status reported by the node
+	Location    string        // Location is the module that aborted e.g. "0x1::coin", empty if the node didn't say
+	Code        uint64        // Code is the full abort code
+	Category    AbortCategory // Category is the upper bits of Code, if it follows the std::error convention
+	Reason      uint16        // Reason is the lower 16 bits of Code, the module specific error
+	ReasonName  string        // ReasonName is the name of the error constant e.g. "EINSUFFICIENT_BALANCE", if known
+	Description string        // Description is the doc comment of the error constant, if known
+}
+
+// Error returns the VM status of the abort
+func (e *MoveAbortError) Error() string {
+	return e.VmStatus
+}
+
+// Unwrap allows matching with [errors.Is] against [ErrTransactionNotApplied]
+func (e *MoveAbortError) Unwrap() error {
+	return ErrTransactionNotApplied
+}
+
+// VmStatusError is a transaction that failed for a reason other than a Move abort, such as running out of gas
+type VmStatusError struct {
+	VmStatus string // VmStatus is the full status reported by the node
+}
+
+// Error returns the VM status
+func (e *VmStatusError) Error() string {
+	return e.VmStatus
+}
+
+// Unwrap allows matching with [errors.Is] against [ErrTransactionNotApplied]
+func (e *VmStatusError) Unwrap() error {
+	return ErrTransactionNotApplied
+}
+
+// VmStatusSuccess is the VM status of a successfully executed transaction
+const VmStatusSuccess = "Executed successfully"
+
+// moveAbortRegex matches the node's abort statuses, which are one of
+//
+//	Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins to complete transaction
+//	Move abort in 0x1::coin: 0x10006
+//	Move abort: code 65542
+var moveAbortRegex = regexp.MustCompile(`^Move abort(?: in ([^:\s]+(?:::[^:\s]+)*))?:\s*(?:code\s+)?(?:(\w+)\((0x[0-9a-fA-F]+|\d+)\)|(0x[0-9a-fA-F]+|\d+))(?::\s*(.*))?$`)
+
+// ParseMoveAbort decodes a Move abort VM status, returning false if the status is not an abort
+//
+//	abort, ok := ParseMoveAbort(txn.VmStatus)
+func ParseMoveAbort(vmStatus string) (*MoveAbortError, bool) {
+	match := moveAbortRegex.FindStringSubmatch(strings.TrimSpace(vmStatus))
+	if match == nil {
+		return nil, false
+	}
+	codeStr := match[3]
+	if codeStr == "" {
+		codeStr = match[4]
+	}
+	code, err := strconv.ParseUint(codeStr, 0, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &MoveAbortError{
+		VmStatus:    vmStatus,
+		Location:    match[1],
+		Code:        code,
+		Category:    AbortCategory(code >> 16),
+		Reason:      uint16(code),
+		ReasonName:  match[2],
+		Description: match[5],
+	}, true
+}
+
+// VmStatusToError converts the VM status of a committed transaction to an error, nil if it succeeded.  Aborts are
+// a [*MoveAbortError], and other failures are a [*VmStatusError].
+//
+//	if err := endless.VmStatusToError(txn.VmStatus); err != nil {
+//		return err
+//	}
+func VmStatusToError(vmStatus string) error {
+	if vmStatus == VmStatusSuccess {
+		return nil
+	}
+	if abort, ok := ParseMoveAbort(vmStatus); ok {
+		return abort
+	}
+	return &VmStatusError{VmStatus: vmStatus}
+}
@@ -0,0 +1,96 @@
+package endless
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpError_Typed(t *testing.T) {
+	body := ""
+	status := http.StatusNotFound
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	body = `{"message":"Account not found by Address(0x1) and Ledger version(5)","error_code":"account_not_found","vm_error_code":null}`
+	_, err := client.Account(AccountOne)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.NotErrorIs(t, err, ErrResourceNotFound)
+	var apiErr *api.Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "account_not_found", apiErr.ErrorCode)
+	var httpErr *HttpError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+
+	body = `{"message":"Resource not found","error_code":"resource_not_found"}`
+	_, err = client.AccountResource(AccountOne, "0x1::account::Account")
+	assert.ErrorIs(t, err, ErrResourceNotFound)
+
+	status = http.StatusBadRequest
+	body = `{"message":"Invalid transaction: Type: Validation Code: SEQUENCE_NUMBER_TOO_OLD","error_code":"vm_error","vm_error_code":3}`
+	_, err = client.SubmitTransaction(testSignedTransactions(t, 7, 1)[0])
+	assert.ErrorIs(t, err, ErrSequenceNumberTooOld)
+	assert.ErrorIs(t, err, ErrTransactionRejected)
+
+	status = http.StatusInsufficientStorage
+	body = `{"message":"Mempool is full","error_code":"mempool_is_full"}`
+	_, err = client.SubmitTransaction(testSignedTransactions(t, 7, 1)[0])
+	assert.ErrorIs(t, err, ErrMempoolFull)
+
+	// Bodies that aren't from the node are left alone
+	status = http.StatusBadGateway
+	body = `<html>bad gateway</html>`
+	_, err = client.Info()
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Nil(t, httpErr.ApiError)
+	assert.False(t, errors.As(err, &apiErr))
+}
+
+func TestParseMoveAbort(t *testing.T) {
+	abort, ok := ParseMoveAbort("Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins to complete transaction")
+	assert.True(t, ok)
+	assert.Equal(t, "0x1::coin", abort.Location)
+	assert.Equal(t, uint64(0x10006), abort.Code)
+	assert.Equal(t, AbortCategoryInvalidArgument, abort.Category)
+	assert.Equal(t, "INVALID_ARGUMENT", abort.Category.String())
+	assert.Equal(t, uint16(6), abort.Reason)
+	assert.Equal(t, "EINSUFFICIENT_BALANCE", abort.ReasonName)
+	assert.Equal(t, "Not enough coins to complete transaction", abort.Description)
+
+	abort, ok = ParseMoveAbort("Move abort in 0x1::account: 0x60001")
+	assert.True(t, ok)
+	assert.Equal(t, "0x1::account", abort.Location)
+	assert.Equal(t, AbortCategoryNotFound, abort.Category)
+	assert.Equal(t, uint16(1), abort.Reason)
+	assert.Empty(t, abort.ReasonName)
+
+	abort, ok = ParseMoveAbort("Move abort: code 65542")
+	assert.True(t, ok)
+	assert.Empty(t, abort.Location)
+	assert.Equal(t, uint64(65542), abort.Code)
+
+	_, ok = ParseMoveAbort("Out of gas")
+	assert.False(t, ok)
+}
+
+func TestVmStatusToError(t *testing.T) {
+	assert.NoError(t, VmStatusToError(VmStatusSuccess))
+
+	err := VmStatusToError("Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins")
+	abort := &MoveAbortError{}
+	assert.True(t, errors.As(err, &abort))
+	assert.Equal(t, "EINSUFFICIENT_BALANCE", abort.ReasonName)
+	assert.ErrorIs(t, err, ErrTransactionNotApplied)
+
+	err = VmStatusToError("Out of gas")
+	vmErr := &VmStatusError{}
+	assert.True(t, errors.As(err, &vmErr))
+	assert.ErrorIs(t, err, ErrTransactionNotApplied)
+	assert.Equal(t, "Out of gas", err.Error())
+}
@@ -0,0 +1,330 @@
+package endless
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// FollowedEvent is an event delivered by an [EventFollower], along with where it was emitted
+type FollowedEvent struct {
+	Version         uint64     // Version of the transaction that emitted the event
+	TransactionHash string     // TransactionHash of the transaction that emitted the event
+	Index           uint64     // Index of the event within the transaction's events
+	Event           *api.Event // Event itself
+}
+
+// EventFilter selects which events an [EventFollower] delivers.  Each field that is set must match, and an empty
+// filter matches every event.
+//
+//	filter := EventFilter{Types: []string{"0x1::fungible_asset::Deposit"}}
+type EventFilter struct {
+	// Types are the event types to match.  A type without generics e.g. "0x1::coin::DepositEvent" also matches every
+	// instantiation of it, such as "0x1::coin::DepositEvent<0x1::endless_coin::EndlessCoin>".
+	Types []string
+
+	// Accounts are the accounts the event is about: the account whose event handle emitted it, or for module events,
+	// which have no handle, the address in one of the AccountFields of the event's data
+	Accounts []AccountAddress
+
+	// AccountFields are the fields of a module event's data matched against Accounts, "account", "owner", and
+	// "store" by default e.g. the store of a 0x1::fungible_asset::Deposit
+	AccountFields []string
+
+	// Modules are the modules defining the event type e.g. "0x1::coin"
+	Modules []string
+
+	// Match optionally applies any other condition, after the other fields have matched
+	Match func(event *FollowedEvent) bool
+}
+
+// Matches checks if the event is selected by the filter
+func (filter *EventFilter) Matches(event *FollowedEvent) bool {
+	eventType := event.Event.Type
+	if len(filter.Types) > 0 && !slices.ContainsFunc(filter.Types, func(filterType string) bool {
+		if eventType == filterType {
+			return true
+		}
+		baseType, _, hasGenerics := strings.Cut(eventType, "<")
+		return hasGenerics && !strings.Contains(filterType, "<") && baseType == filterType
+	}) {
+		return false
+	}
+	if len(filter.Accounts) > 0 && !filter.matchesAccount(event.Event) {
+		return false
+	}
+	if len(filter.Modules) > 0 && !slices.ContainsFunc(filter.Modules, func(module string) bool {
+		return strings.HasPrefix(eventType, module+"::")
+	}) {
+		return false
+	}
+	return filter.Match == nil || filter.Match(event)
+}
+
+// defaultEventAccountFields are the fields of module events holding the account they're about, when not given
+var defaultEventAccountFields = []string{"account", "owner", "store"}
+
+// matchesAccount checks the account of the event's handle, or for a module event, the addresses in its account fields
+func (filter *EventFilter) matchesAccount(event *api.Event) bool {
+	if guid := event.Guid; guid != nil && guid.AccountAddress != nil && *guid.AccountAddress != AccountZero {
+		return slices.Contains(filter.Accounts, *guid.AccountAddress)
+	}
+	fields := filter.AccountFields
+	if len(fields) == 0 {
+		fields = defaultEventAccountFields
+	}
+	for _, field := range fields {
+		value, ok := event.Data[field].(string)
+		if !ok {
+			continue
+		}
+		address := AccountAddress{}
+		if err := address.ParseStringRelaxed(value); err == nil && slices.Contains(filter.Accounts, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checkpoint is the position of an [EventFollower], it has delivered every matching event before EventIndex in the
+// transaction at Version
+type Checkpoint struct {
+	Version    uint64 `json:"version"`     // Version of the next transaction to read
+	EventIndex uint64 `json:"event_index"` // EventIndex of the next event to read within the transaction
+}
+
+// CheckpointStore persists the [Checkpoint] of an [EventFollower], so it can resume after a restart
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or false if none has been saved
+	Load() (checkpoint Checkpoint, ok bool, err error)
+
+	// Save replaces the saved checkpoint
+	Save(checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory, for followers that don't need to survive a restart
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+// Load returns the checkpoint last saved
+func (store *MemoryCheckpointStore) Load() (Checkpoint, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.checkpoint == nil {
+		return Checkpoint{}, false, nil
+	}
+	return *store.checkpoint, true, nil
+}
+
+// Save keeps the checkpoint
+func (store *MemoryCheckpointStore) Save(checkpoint Checkpoint) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.checkpoint = &checkpoint
+	return nil
+}
+
+// FileCheckpointStore keeps the checkpoint as JSON in a file.  Each save replaces the file atomically, so a crash
+// leaves either the old or the new checkpoint.
+type FileCheckpointStore struct {
+	Path string // Path of the checkpoint file, its directory must exist
+}
+
+// NewFileCheckpointStore creates a store keeping the checkpoint at path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{Path: path}
+}
+
+// Load reads the checkpoint file, returning false if it doesn't exist
+func (store *FileCheckpointStore) Load() (checkpoint Checkpoint, ok bool, err error) {
+	blob, err := os.ReadFile(store.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, false, nil
+	} else if err != nil {
+		return checkpoint, false, err
+	}
+	if err = json.Unmarshal(blob, &checkpoint); err != nil {
+		return checkpoint, false, fmt.Errorf("bad checkpoint file %s: %w", store.Path, err)
+	}
+	return checkpoint, true, nil
+}
+
+// Save writes the checkpoint to a temporary file, and renames it over the checkpoint file
+func (store *FileCheckpointStore) Save(checkpoint Checkpoint) error {
+	blob, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(store.Path), filepath.Base(store.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Only left behind if the rename didn't happen
+		_ = os.Remove(file.Name())
+	}()
+	if _, err = file.Write(blob); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), store.Path)
+}
+
+// EventFollower tails the ledger from a version, delivering the events selected by its [EventFilter] in order.  It
+// saves a [Checkpoint] after every delivered event and every page of transactions, so after a restart it resumes
+// exactly after the last event that was delivered.
+//
+//	follower, err := NewEventFollower(client, EventFilter{Types: []string{"0x1::fungible_asset::Deposit"}}, NewFileCheckpointStore("deposits.json"))
+//	err = follower.Run(ctx, func(event FollowedEvent) error {
+//		fmt.Println(event.Version, event.Event.Data)
+//		return nil
+//	})
+type EventFollower struct {
+	client     *NodeClient
+	filter     EventFilter
+	store      CheckpointStore
+	start      uint64
+	pageSize   uint64
+	pollPeriod time.Duration
+}
+
+// NewEventFollower creates a follower reading the ledger with client.  A nil store keeps the checkpoint in memory.
+//
+// Accepts options:
+//   - [StartAt] the version to start from, if the store has no checkpoint, 0 by default
+//   - [PageSize] the number of transactions to read per request, 100 by default
+//   - [PollPeriod] how long to wait for new transactions after reaching the latest, 1 second by default
+func NewEventFollower(client *NodeClient, filter EventFilter, store CheckpointStore, options ...any) (*EventFollower, error) {
+	follower := &EventFollower{
+		client:     client,
+		filter:     filter,
+		store:      store,
+		pageSize:   defaultIterPageSize,
+		pollPeriod: time.Second,
+	}
+	if follower.store == nil {
+		follower.store = &MemoryCheckpointStore{}
+	}
+	for i, arg := range options {
+		switch value := arg.(type) {
+		case StartAt:
+			follower.start = uint64(value)
+		case PageSize:
+			if value == 0 {
+				return nil, errors.New("PageSize must be greater than 0")
+			}
+			follower.pageSize = uint64(value)
+		case PollPeriod:
+			follower.pollPeriod = time.Duration(value)
+		default:
+			return nil, fmt.Errorf("NewEventFollower arg %d bad type %T", i+1, arg)
+		}
+	}
+	return follower, nil
+}
+
+// Run follows the ledger, calling handle for each selected event, until ctx is cancelled or handle returns an error.
+// An event whose handle returned an error is not checkpointed, so it is delivered again on the next run.
+//
+// Transient node errors are retried according to the client's [RetryPolicy], any other error stops the follower.
+func (follower *EventFollower) Run(ctx context.Context, handle func(event FollowedEvent) error) error {
+	checkpoint, ok, err := follower.store.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if !ok {
+		checkpoint = Checkpoint{Version: follower.start}
+	}
+
+	rc := follower.client.WithContext(ctx)
+	for {
+		start, limit := checkpoint.Version, follower.pageSize
+		txns, err := rc.transactionsInner(&start, &limit)
+		if err != nil && !errors.Is(err, ErrVersionNotFound) {
+			return err
+		}
+		if len(txns) == 0 {
+			// Caught up, wait for more
+			if err := rc.sleep(follower.pollPeriod); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, txn := range txns {
+			version := txn.Version()
+			for i, event := range txn.Events() {
+				index := uint64(i)
+				if version == checkpoint.Version && index < checkpoint.EventIndex {
+					// Already delivered before a restart
+					continue
+				}
+				followed := FollowedEvent{
+					Version:         version,
+					TransactionHash: txn.Hash(),
+					Index:           index,
+					Event:           event,
+				}
+				if !follower.filter.Matches(&followed) {
+					continue
+				}
+				if err := handle(followed); err != nil {
+					return err
+				}
+				checkpoint = Checkpoint{Version: version, EventIndex: index + 1}
+				if err := follower.store.Save(checkpoint); err != nil {
+					return fmt.Errorf("failed to save checkpoint: %w", err)
+				}
+			}
+		}
+		checkpoint = Checkpoint{Version: txns[len(txns)-1].Version() + 1}
+		if err := follower.store.Save(checkpoint); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	}
+}
+
+// Events runs the follower in the background, delivering events on the returned channel.  An event is checkpointed
+// once it has been received from the channel.  Both channels are closed when the follower stops, and the reason it
+// stopped is sent on the error channel.
+//
+//	events, errs := follower.Events(ctx)
+//	for event := range events {
+//		fmt.Println(event.Version, event.Event.Type)
+//	}
+//	err := <-errs
+func (follower *EventFollower) Events(ctx context.Context) (<-chan FollowedEvent, <-chan error) {
+	events := make(chan FollowedEvent)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(events)
+		errs <- follower.Run(ctx, func(event FollowedEvent) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case events <- event:
+				return nil
+			}
+		})
+	}()
+	return events, errs
+}
@@ -0,0 +1,135 @@
+package endless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEventLedger serves a growing ledger, where every transaction emits a deposit and a withdraw event
+func newTestEventLedger(t *testing.T, numTxns *atomic.Uint64) *NodeClient {
+	t.Helper()
+	return newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+		limit, _ := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+		latest := numTxns.Load()
+		if start >= latest {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Ledger version not found","error_code":"version_not_found"}`))
+			return
+		}
+		txns := make([]string, 0)
+		for version := start; version < min(start+limit, latest); version++ {
+			events := fmt.Sprintf(`[{"guid":{"creation_number":"2","account_address":"0x1"},"sequence_number":"%d","type":"0x1::coin::DepositEvent<0x1::endless_coin::EndlessCoin>","data":{}},`+
+				`{"guid":{"creation_number":"0","account_address":"0x0"},"sequence_number":"0","type":"0x1::fungible_asset::Withdraw","data":{}}]`, version)
+			txns = append(txns, strings.Replace(testUserTransactionJson(version, version, true, VmStatusSuccess), `"events":[]`, `"events":`+events, 1))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(txns, ",") + "]"))
+	}))
+}
+
+func TestEventFollower_ResumesFromCheckpoint(t *testing.T) {
+	var numTxns atomic.Uint64
+	numTxns.Store(10)
+	client := newTestEventLedger(t, &numTxns)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	filter := EventFilter{Types: []string{"0x1::coin::DepositEvent"}}
+
+	stop := errors.New("stop")
+	follow := func(count int) []FollowedEvent {
+		follower, err := NewEventFollower(client, filter, store, StartAt(3), PageSize(4), PollPeriod(time.Millisecond))
+		assert.NoError(t, err)
+		events := make([]FollowedEvent, 0)
+		err = follower.Run(context.Background(), func(event FollowedEvent) error {
+			events = append(events, event)
+			if len(events) == count {
+				return stop
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, stop)
+		return events
+	}
+
+	// Stop partway, the rejected event is not checkpointed
+	events := follow(3)
+	assert.Equal(t, []uint64{3, 4, 5}, []uint64{events[0].Version, events[1].Version, events[2].Version})
+	assert.Equal(t, uint64(0), events[0].Index)
+	checkpoint, ok, err := store.Load()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Checkpoint{Version: 4, EventIndex: 1}, checkpoint)
+
+	// Resume, including new transactions while waiting at the latest
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		numTxns.Store(12)
+	}()
+	events = follow(7)
+	versions := make([]uint64, 0)
+	for _, event := range events {
+		versions = append(versions, event.Version)
+	}
+	assert.Equal(t, []uint64{5, 6, 7, 8, 9, 10, 11}, versions)
+}
+
+func TestEventFollower_Channel(t *testing.T) {
+	var numTxns atomic.Uint64
+	numTxns.Store(5)
+	client := newTestEventLedger(t, &numTxns)
+	follower, err := NewEventFollower(client, EventFilter{Modules: []string{"0x1::fungible_asset"}}, nil, PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := follower.Events(ctx)
+	for i := range 5 {
+		event := <-events
+		assert.Equal(t, uint64(i), event.Version)
+		assert.Equal(t, uint64(1), event.Index)
+		assert.Equal(t, "0x1::fungible_asset::Withdraw", event.Event.Type)
+	}
+	cancel()
+	for range events {
+	}
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	other := AccountAddress{}
+	other[31] = 2
+	handleEvent := &FollowedEvent{Event: &api.Event{
+		Type: "0x1::coin::DepositEvent<0x1::endless_coin::EndlessCoin>",
+		Guid: &api.GUID{AccountAddress: &AccountOne},
+	}}
+	moduleEvent := &FollowedEvent{Event: &api.Event{
+		Type: "0x1::fungible_asset::Deposit",
+		Guid: &api.GUID{AccountAddress: &AccountZero},
+		Data: map[string]any{"store": "0x2", "amount": "100", "to": "0x1"},
+	}}
+
+	assert.True(t, (&EventFilter{}).Matches(handleEvent))
+	assert.True(t, (&EventFilter{Types: []string{"0x1::coin::DepositEvent"}}).Matches(handleEvent))
+	assert.False(t, (&EventFilter{Types: []string{"0x1::coin::DepositEvent<0x1::other::Coin>"}}).Matches(handleEvent))
+	assert.False(t, (&EventFilter{Types: []string{"0x1::coin::Deposit"}}).Matches(handleEvent))
+	assert.True(t, (&EventFilter{Accounts: []AccountAddress{other, AccountOne}}).Matches(handleEvent))
+	assert.False(t, (&EventFilter{Accounts: []AccountAddress{other}}).Matches(handleEvent))
+	assert.False(t, (&EventFilter{Accounts: []AccountAddress{AccountZero}}).Matches(moduleEvent))
+	// Module events match on the accounts in their data
+	assert.True(t, (&EventFilter{Accounts: []AccountAddress{other}}).Matches(moduleEvent))
+	assert.False(t, (&EventFilter{Accounts: []AccountAddress{AccountOne}}).Matches(moduleEvent))
+	assert.True(t, (&EventFilter{Accounts: []AccountAddress{AccountOne}, AccountFields: []string{"to"}}).Matches(moduleEvent))
+	assert.False(t, (&EventFilter{Accounts: []AccountAddress{other}, AccountFields: []string{"amount"}}).Matches(moduleEvent))
+	assert.True(t, (&EventFilter{Modules: []string{"0x1::fungible_asset"}}).Matches(moduleEvent))
+	assert.False(t, (&EventFilter{Modules: []string{"0x1::fungible"}}).Matches(moduleEvent))
+	assert.False(t, (&EventFilter{Match: func(event *FollowedEvent) bool { return false }}).Matches(moduleEvent))
+}
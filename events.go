@@ -0,0 +1,88 @@
+package endless
+
+import (
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// EventsByHandle fetches the events of an event handle, which is a field of a resource on the account
+//
+//	events, err := client.EventsByHandle(address, "0x1::account::Account", "coin_register_events", nil, nil)
+//
+// Arguments:
+//   - handleStruct is the resource holding the handle e.g. "0x1::account::Account"
+//   - fieldName is the field of the resource that is the handle e.g. "coin_register_events"
+//   - start is the sequence number of the first event. Nil for the oldest events.
+//   - limit is the number of events to return. 'about a hundred' by default.
+func (rc *NodeClient) EventsByHandle(address AccountAddress, handleStruct string, fieldName string, start *uint64, limit *uint64) (data []*api.Event, err error) {
+	au := rc.baseUrl.JoinPath("accounts", address.String(), "events", handleStruct, fieldName)
+	data, err = rc.getEvents(au, start, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get events by handle api err: %w", err)
+	}
+	return data, nil
+}
+
+// EventsByCreationNumber fetches the events of an event handle, by the creation number of its [api.GUID]
+//
+//	events, err := client.EventsByCreationNumber(address, 0, nil, nil)
+//
+// Arguments:
+//   - creationNumber is the creation number of the event handle on the account
+//   - start is the sequence number of the first event. Nil for the oldest events.
+//   - limit is the number of events to return. 'about a hundred' by default.
+func (rc *NodeClient) EventsByCreationNumber(address AccountAddress, creationNumber uint64, start *uint64, limit *uint64) (data []*api.Event, err error) {
+	au := rc.baseUrl.JoinPath("accounts", address.String(), "events", strconv.FormatUint(creationNumber, 10))
+	data, err = rc.getEvents(au, start, limit)
+	if err != nil {
+		return nil, fmt.Errorf("get events by creation number api err: %w", err)
+	}
+	return data, nil
+}
+
+// EventsByHandleIter iterates over the events of an event handle, a page at a time, see [NodeClient.EventsByHandle].
+// Iteration stops at the first error, and forward iteration stops at the latest event.
+//
+// Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].  Iterating backward requires [StartAt].
+//
+//	for event, err := range client.EventsByHandleIter(address, "0x1::account::Account", "coin_register_events") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(event.SequenceNumber, event.Version)
+//	}
+func (rc *NodeClient) EventsByHandleIter(address AccountAddress, handleStruct string, fieldName string, options ...any) iter.Seq2[*api.Event, error] {
+	return indexedIter(rc, nil, func(rc *NodeClient, start uint64, limit uint64) ([]*api.Event, error) {
+		return rc.EventsByHandle(address, handleStruct, fieldName, &start, &limit)
+	}, options...)
+}
+
+// EventsByCreationNumberIter iterates over the events of an event handle, a page at a time, see
+// [NodeClient.EventsByCreationNumber].  Iteration stops at the first error, and forward iteration stops at the latest
+// event.
+//
+// Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].  Iterating backward requires [StartAt].
+func (rc *NodeClient) EventsByCreationNumberIter(address AccountAddress, creationNumber uint64, options ...any) iter.Seq2[*api.Event, error] {
+	return indexedIter(rc, nil, func(rc *NodeClient, start uint64, limit uint64) ([]*api.Event, error) {
+		return rc.EventsByCreationNumber(address, creationNumber, &start, &limit)
+	}, options...)
+}
+
+// getEvents fetches a single page of events
+func (rc *NodeClient) getEvents(au *url.URL, start *uint64, limit *uint64) ([]*api.Event, error) {
+	params := url.Values{}
+	if start != nil {
+		params.Set("start", strconv.FormatUint(*start, 10))
+	}
+	if limit != nil {
+		params.Set("limit", strconv.FormatUint(*limit, 10))
+	}
+	if len(params) != 0 {
+		au.RawQuery = params.Encode()
+	}
+	return Get[[]*api.Event](rc, au.String())
+}
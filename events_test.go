@@ -0,0 +1,74 @@
+package endless
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestEventStream serves numEvents deposit events under both the handle and creation number routes
+func newTestEventStream(t *testing.T, numEvents uint64, paths *[]string) *NodeClient {
+	t.Helper()
+	return newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		start, _ := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+		limit := uint64(25)
+		if r.URL.Query().Has("limit") {
+			limit, _ = strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+		}
+		events := make([]string, 0)
+		for sequenceNumber := start; sequenceNumber < min(start+limit, numEvents); sequenceNumber++ {
+			events = append(events, fmt.Sprintf(`{"version":"%d","guid":{"creation_number":"2","account_address":"0x1"},"sequence_number":"%d","type":"0x1::coin::DepositEvent","data":{"amount":"%d"}}`, 1000+sequenceNumber, sequenceNumber, sequenceNumber*10))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(events, ",") + "]"))
+	}))
+}
+
+func TestNodeClient_EventsByHandle(t *testing.T) {
+	paths := make([]string, 0)
+	client := newTestEventStream(t, 10, &paths)
+
+	start, limit := uint64(3), uint64(4)
+	events, err := client.EventsByHandle(AccountOne, "0x1::coin::CoinStore<0x1::endless_coin::EndlessCoin>", "deposit_events", &start, &limit)
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, uint64(3), events[0].SequenceNumber)
+	assert.Equal(t, uint64(1003), events[0].Version)
+	assert.Equal(t, uint64(2), events[0].Guid.CreationNumber)
+	assert.Equal(t, "30", events[0].Data["amount"])
+	assert.True(t, strings.HasSuffix(paths[0], "/events/0x1::coin::CoinStore<0x1::endless_coin::EndlessCoin>/deposit_events"))
+
+	events, err = client.EventsByCreationNumber(AccountOne, 2, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, events, 10)
+	assert.True(t, strings.HasSuffix(paths[1], "/events/2"))
+}
+
+func TestNodeClient_EventsIter(t *testing.T) {
+	paths := make([]string, 0)
+	client := newTestEventStream(t, 23, &paths)
+
+	expected := uint64(0)
+	for event, err := range client.EventsByCreationNumberIter(AccountOne, 2, PageSize(5)) {
+		assert.NoError(t, err)
+		assert.Equal(t, expected, event.SequenceNumber)
+		expected++
+	}
+	assert.Equal(t, uint64(23), expected)
+
+	sequenceNumbers := make([]uint64, 0)
+	for event, err := range client.EventsByHandleIter(AccountOne, "0x1::account::Account", "coin_register_events", DirectionBackward, StartAt(6), PageSize(4)) {
+		assert.NoError(t, err)
+		sequenceNumbers = append(sequenceNumbers, event.SequenceNumber)
+	}
+	assert.Equal(t, []uint64{6, 5, 4, 3, 2, 1, 0}, sequenceNumbers)
+
+	// Backward needs a place to start
+	for _, err := range client.EventsByHandleIter(AccountOne, "0x1::account::Account", "coin_register_events", DirectionBackward) {
+		assert.Error(t, err)
+	}
+}
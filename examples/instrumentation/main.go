@@ -0,0 +1,116 @@
+// instrumentation is an example of how to collect metrics from the client, by adapting the Instrumentation interface
+// to your metrics system.  Here, counters are kept in memory and printed at the end.
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk"
+)
+
+// operationStats are the counters kept per operation, with the endpoints that served it.  Retries aren't reported with
+// an endpoint, so the counters aren't split by endpoint.
+type operationStats struct {
+	endpoints     map[string]bool
+	requests      int
+	errors        int
+	retries       int
+	responseBytes int
+	totalDuration time.Duration
+	ledgerVersion uint64
+}
+
+// statsInstrumentation is an adapter, which would forward to Prometheus, OpenTelemetry, etc. in a real application
+type statsInstrumentation struct {
+	endless.NoopInstrumentation
+	mu    sync.Mutex
+	stats map[string]*operationStats
+}
+
+func (s *statsInstrumentation) get(key string) *operationStats {
+	stats, ok := s.stats[key]
+	if !ok {
+		stats = &operationStats{endpoints: make(map[string]bool)}
+		s.stats[key] = stats
+	}
+	return stats
+}
+
+// statsSpan records a single request when it ends
+type statsSpan struct {
+	parent    *statsInstrumentation
+	operation string
+	endpoint  string
+}
+
+func (span *statsSpan) End(result endless.SpanResult) {
+	span.parent.mu.Lock()
+	defer span.parent.mu.Unlock()
+	stats := span.parent.get(span.operation)
+	stats.endpoints[span.endpoint] = true
+	stats.requests++
+	if result.Err != nil {
+		stats.errors++
+	}
+	stats.responseBytes += result.ResponseBytes
+	stats.totalDuration += result.Duration
+	stats.ledgerVersion = max(stats.ledgerVersion, result.LedgerVersion)
+}
+
+func (s *statsInstrumentation) StartSpan(ctx context.Context, operation string, endpoint string) (context.Context, endless.Span) {
+	return ctx, &statsSpan{parent: s, operation: operation, endpoint: endpoint}
+}
+
+func (s *statsInstrumentation) Retry(_ context.Context, operation string, _ int, _ time.Duration, _ error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(operation).retries++
+}
+
+func (s *statsInstrumentation) print() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.stats))
+	for key := range s.stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		stats := s.stats[key]
+		endpoints := make([]string, 0, len(stats.endpoints))
+		for endpoint := range stats.endpoints {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		fmt.Printf("%s %v: %d requests, %d errors, %d retries, %d bytes, %s total, ledger version %d\n",
+			key, endpoints, stats.requests, stats.errors, stats.retries, stats.responseBytes, stats.totalDuration, stats.ledgerVersion)
+	}
+}
+
+func example(networkConfig endless.NetworkConfig) {
+	instrumentation := &statsInstrumentation{stats: make(map[string]*operationStats)}
+	client, err := endless.NewClient(networkConfig, endless.DefaultRetryPolicy(), instrumentation)
+	if err != nil {
+		panic("Failed to create client " + err.Error())
+	}
+
+	if _, err = client.Info(); err != nil {
+		panic("Failed to retrieve node info " + err.Error())
+	}
+	if _, err = client.AccountEDSBalance(endless.AccountOne); err != nil {
+		panic("Failed to retrieve AccountOne EDS balance " + err.Error())
+	}
+	if _, err = client.Account(endless.AccountOne); err != nil {
+		panic("Failed to retrieve AccountOne " + err.Error())
+	}
+
+	instrumentation.print()
+}
+
+func main() {
+	example(endless.TestnetConfig)
+}
@@ -0,0 +1,73 @@
+// transaction_engine shows how to send many transactions from a pool of accounts, such as for payouts or load tests.
+package main
+
+import (
+	"fmt"
+
+	"github.com/endless-labs/endless-go-sdk"
+)
+
+// example This example shows you how to spread transactions across several senders
+//
+// Each sender has its own sequence numbers, so more senders allow more transactions in flight at once
+func example(networkConfig endless.NetworkConfig, numSenders int, numTransactions uint64) {
+	client, err := endless.NewClient(networkConfig)
+	if err != nil {
+		panic("Failed to create client:" + err.Error())
+	}
+
+	// Create and fund the senders
+	senders := make([]endless.TransactionSigner, numSenders)
+	for i := range senders {
+		sender, err := endless.NewEd25519Account()
+		if err != nil {
+			panic("Failed to create sender:" + err.Error())
+		}
+		err = client.Faucet(*sender, endless.SequenceNumber(0)) // Use the sequence number to skip fetching it
+		if err != nil {
+			panic("Failed to fund sender:" + err.Error())
+		}
+		senders[i] = sender
+	}
+
+	engine, err := client.NewTransactionEngine(senders, endless.EngineWorkers(16), endless.MaxInFlight(20))
+	if err != nil {
+		panic("Failed to create engine:" + err.Error())
+	}
+
+	entryFunction, err := endless.CoinTransferPayload(nil, endless.AccountOne, 100)
+	if err != nil {
+		panic("Failed to serialize arguments:" + err.Error())
+	}
+	payloads := make(chan endless.TransactionBuildPayload, 50)
+	results := make(chan endless.TransactionEngineResult, 50)
+	go engine.Run(payloads, results)
+
+	// Send the payloads
+	go func() {
+		for i := uint64(0); i < numTransactions; i++ {
+			payloads <- endless.TransactionBuildPayload{
+				Id:    i,
+				Type:  endless.TransactionSubmissionTypeSingle,
+				Inner: endless.TransactionPayload{Payload: entryFunction},
+			}
+		}
+		close(payloads)
+	}()
+
+	// Wait for all transactions to be committed
+	for result := range results {
+		if result.Result.Status != endless.TransactionStatusCommitted {
+			panic(fmt.Sprintf("Failed to commit transaction %d: %s %v", result.Id, result.Result.Status, result.Result.Err))
+		}
+	}
+
+	stats := engine.Stats()
+	println("Committed:", stats.Committed, "in", stats.Elapsed.Milliseconds(), "ms")
+	println("Throughput:", int(stats.Throughput*60), "transactions per minute")
+	println("Latency mean:", stats.LatencyMean.Milliseconds(), "ms, max:", stats.LatencyMax.Milliseconds(), "ms")
+}
+
+func main() {
+	example(endless.TestnetConfig, 4, 100)
+}
@@ -0,0 +1,95 @@
+package endless
+
+import (
+	"math/big"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+)
+
+// Resource types of the framework, for use with [AccountResourceAs]
+const (
+	ResourceTypeAccount               = "0x1::account::Account"              // ResourceTypeAccount is decoded by [AccountData]
+	ResourceTypeFungibleStore         = "0x1::fungible_asset::FungibleStore" // ResourceTypeFungibleStore is decoded by [FungibleStore]
+	ResourceTypeFungibleAssetMetadata = "0x1::fungible_asset::Metadata"      // ResourceTypeFungibleAssetMetadata is decoded by [FungibleAssetMetadata]
+)
+
+// AccountData is the 0x1::account::Account resource, held by every account
+//
+//	account, err := AccountResourceAs[AccountData](client, address, ResourceTypeAccount)
+type AccountData struct {
+	AuthenticationKey     [][]byte `json:"authentication_key"`      // AuthenticationKey is every key that can sign for the account
+	SequenceNumber        uint64   `json:"sequence_number"`         // SequenceNumber of the next transaction
+	GuidCreationNum       uint64   `json:"guid_creation_num"`       // GuidCreationNum is the creation number of the next GUID
+	NumSignaturesRequired uint64   `json:"num_signatures_required"` // NumSignaturesRequired of the AuthenticationKey to sign
+}
+
+// MarshalBCS serializes the resource as stored on chain
+func (ad *AccountData) MarshalBCS(ser *bcs.Serializer) {
+	bcs.SerializeSequenceWithFunction(ad.AuthenticationKey, ser, (*bcs.Serializer).WriteBytes)
+	ser.U64(ad.SequenceNumber)
+	ser.U64(ad.GuidCreationNum)
+	ser.U64(ad.NumSignaturesRequired)
+}
+
+// UnmarshalBCS deserializes the resource as stored on chain
+func (ad *AccountData) UnmarshalBCS(des *bcs.Deserializer) {
+	ad.AuthenticationKey = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, out *[]byte) {
+		*out = des.ReadBytes()
+	})
+	ad.SequenceNumber = des.U64()
+	ad.GuidCreationNum = des.U64()
+	ad.NumSignaturesRequired = des.U64()
+}
+
+// FungibleStore is the 0x1::fungible_asset::FungibleStore resource, held by the object storing an owner's balance of a
+// fungible asset
+//
+//	store, err := AccountResourceAs[FungibleStore](client, storeAddress, ResourceTypeFungibleStore)
+type FungibleStore struct {
+	Metadata AccountAddress `json:"metadata"` // Metadata is the address of the asset's [FungibleAssetMetadata] object
+	Balance  big.Int        `json:"balance"`  // Balance of the asset in the store
+	Frozen   bool           `json:"frozen"`   // Frozen stores can't be deposited to or withdrawn from by the owner
+}
+
+// MarshalBCS serializes the resource as stored on chain
+func (fs *FungibleStore) MarshalBCS(ser *bcs.Serializer) {
+	ser.Struct(&fs.Metadata)
+	ser.U128(fs.Balance)
+	ser.Bool(fs.Frozen)
+}
+
+// UnmarshalBCS deserializes the resource as stored on chain
+func (fs *FungibleStore) UnmarshalBCS(des *bcs.Deserializer) {
+	des.Struct(&fs.Metadata)
+	fs.Balance = des.U128()
+	fs.Frozen = des.Bool()
+}
+
+// FungibleAssetMetadata is the 0x1::fungible_asset::Metadata resource, held by the object defining a fungible asset
+//
+//	metadata, err := AccountResourceAs[FungibleAssetMetadata](client, metadataAddress, ResourceTypeFungibleAssetMetadata)
+type FungibleAssetMetadata struct {
+	Name       string `json:"name"`        // Name of the asset e.g. "Endless Coin"
+	Symbol     string `json:"symbol"`      // Symbol of the asset e.g. "EDS"
+	Decimals   uint8  `json:"decimals"`    // Decimals of the asset's balances
+	IconUri    string `json:"icon_uri"`    // IconUri of an image for the asset
+	ProjectUri string `json:"project_uri"` // ProjectUri of the asset's website
+}
+
+// MarshalBCS serializes the resource as stored on chain
+func (fam *FungibleAssetMetadata) MarshalBCS(ser *bcs.Serializer) {
+	ser.WriteString(fam.Name)
+	ser.WriteString(fam.Symbol)
+	ser.U8(fam.Decimals)
+	ser.WriteString(fam.IconUri)
+	ser.WriteString(fam.ProjectUri)
+}
+
+// UnmarshalBCS deserializes the resource as stored on chain
+func (fam *FungibleAssetMetadata) UnmarshalBCS(des *bcs.Deserializer) {
+	fam.Name = des.ReadString()
+	fam.Symbol = des.ReadString()
+	fam.Decimals = des.U8()
+	fam.IconUri = des.ReadString()
+	fam.ProjectUri = des.ReadString()
+}
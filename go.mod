@@ -1,6 +1,6 @@
 module github.com/endless-labs/endless-go-sdk
 
-go 1.22
+go 1.23
 
 require (
 	github.com/btcsuite/btcd/btcutil v1.1.6
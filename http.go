@@ -1,16 +1,22 @@
 package endless
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+
+	"github.com/endless-labs/endless-go-sdk/api"
 )
 
 // HttpErrSummaryLength is the maximum length of the body to include in the error message
 const HttpErrSummaryLength = 1000
 
 // HttpError is an error type that represents an error from a http request
+//
+// If the node described the error, it is parsed into ApiError, and the error matches the corresponding sentinel e.g.
+// [ErrAccountNotFound] with [errors.Is].  The [*api.Error] can also be extracted with [errors.As].
 type HttpError struct {
 	Status     string      // HTTP status e.g. "200 OK"
 	StatusCode int         // HTTP status code e.g. 200
@@ -18,6 +24,7 @@ type HttpError struct {
 	Method     string      // HTTP method e.g. "GET"
 	RequestUrl url.URL     // URL of the request
 	Body       []byte      // Body of the response
+	ApiError   *api.Error  // ApiError is the error body from the node, nil if it wasn't one
 }
 
 // NewHttpError creates a new HttpError from a http.Response
@@ -31,7 +38,29 @@ func NewHttpError(response *http.Response) *HttpError {
 		Body:       body,
 		Method:     response.Request.Method,
 		RequestUrl: *response.Request.URL,
+		ApiError:   parseApiError(body),
+	}
+}
+
+// parseApiError parses the body of an error response, nil if it isn't an error from the node
+func parseApiError(body []byte) *api.Error {
+	apiErr := &api.Error{}
+	if json.Unmarshal(body, apiErr) != nil || (apiErr.ErrorCode == "" && apiErr.Message == "") {
+		return nil
+	}
+	return apiErr
+}
+
+// Unwrap returns the node's [*api.Error], and the sentinel errors it corresponds to
+//
+//	if errors.Is(err, endless.ErrAccountNotFound) {
+//		// the account hasn't been created yet
+//	}
+func (he *HttpError) Unwrap() []error {
+	if he.ApiError == nil {
+		return nil
 	}
+	return append([]error{he.ApiError}, apiErrors(he.ApiError)...)
 }
 
 // Error returns a string representation of the HttpError
@@ -13,6 +13,7 @@ import (
 // IndexerClient is a GraphQL client specifically for requesting for data from the EndlessCoin indexer
 type IndexerClient struct {
 	inner *graphql.Client
+	ctx   context.Context // Context applied to every query, nil means [context.Background]
 }
 
 // NewIndexerClient creates a new client specifically for requesting data from the indexer
@@ -20,13 +21,31 @@ func NewIndexerClient(httpClient *http.Client, url string) *IndexerClient {
 	// Reuse the HTTP client in the node client
 	client := graphql.NewClient(url, httpClient)
 	return &IndexerClient{
-		client,
+		inner: client,
 	}
 }
 
+// WithContext returns a view of the client that applies ctx to every query it makes
+func (ic *IndexerClient) WithContext(ctx context.Context) *IndexerClient {
+	if ctx == nil {
+		panic("nil context")
+	}
+	view := *ic
+	view.ctx = ctx
+	return &view
+}
+
+// Context returns the context applied to queries, defaulting to [context.Background]
+func (ic *IndexerClient) Context() context.Context {
+	if ic.ctx == nil {
+		return context.Background()
+	}
+	return ic.ctx
+}
+
 // Query is a generic function for making any GraphQL query against the indexer
 func (ic *IndexerClient) Query(query any, variables map[string]any, options ...graphql.Option) error {
-	return ic.inner.Query(context.Background(), query, variables, options...)
+	return ic.inner.Query(ic.Context(), query, variables, options...)
 }
 
 type CoinBalance struct {
@@ -104,7 +123,11 @@ func (ic *IndexerClient) WaitOnIndexer(processorName string, requestedVersion ui
 		}
 
 		// Sleep and try again later
-		time.Sleep(sleepTime)
+		select {
+		case <-ic.Context().Done():
+			return ic.Context().Err()
+		case <-time.After(sleepTime):
+		}
 	}
 	return nil
 }
@@ -0,0 +1,153 @@
+package endless
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// Instrumentation receives spans and measurements from a [NodeClient], to be forwarded to a metrics or tracing
+// system.  Set it with [NodeClient.SetInstrumentation] or by passing it to [NewClient].  Implementations must be safe
+// for concurrent use, and should return quickly, as they are called inline with every request.
+//
+// Embed [NoopInstrumentation] to only implement some of the methods.
+type Instrumentation interface {
+	// StartSpan is called before every HTTP request to a node, including each retry and failover attempt.  The
+	// returned context is used for the request, so it can carry a trace to interceptors and the transport.
+	StartSpan(ctx context.Context, operation string, endpoint string) (context.Context, Span)
+
+	// Retry is called when a failed request is about to be retried, after the given delay
+	Retry(ctx context.Context, operation string, attempt int, delay time.Duration, err error)
+
+	// TransactionWaitDone is called when waiting for a transaction finishes, whether it committed or not
+	TransactionWaitDone(ctx context.Context, wait TransactionWait)
+}
+
+// Span is a single HTTP request started by [Instrumentation.StartSpan]
+type Span interface {
+	// End is called once, when the response has been read or the request failed
+	End(result SpanResult)
+}
+
+// SpanResult is the outcome of a single HTTP request to a node
+type SpanResult struct {
+	StatusCode    int           // StatusCode is the HTTP status, 0 if no response was received
+	RequestBytes  int           // RequestBytes is the size of the request body
+	ResponseBytes int           // ResponseBytes is the size of the response body
+	Duration      time.Duration // Duration is the time from sending the request to reading the whole response
+	LedgerVersion uint64        // LedgerVersion is the ledger version the node reported, 0 if it didn't
+	Err           error         // Err is the error the request failed with, if any
+}
+
+// TransactionWait is the outcome of waiting for a single transaction
+type TransactionWait struct {
+	Hash      string        // Hash of the transaction
+	Committed bool          // Committed is true if the transaction was found committed on chain
+	Success   bool          // Success is true if the transaction committed and was executed successfully
+	Version   uint64        // Version is the ledger version of the committed transaction
+	Latency   time.Duration // Latency is the time from starting to wait until the transaction was seen committed
+	Attempts  int           // Attempts is the number of times the node was polled for the transaction
+	Err       error         // Err is why waiting stopped, if the transaction was not committed
+}
+
+// committed records the transaction as committed, waiting since start
+func (wait *TransactionWait) committed(txn *api.UserTransaction, start time.Time) {
+	wait.Committed = true
+	wait.Success = txn.Success
+	wait.Version = txn.Version
+	wait.Latency = time.Since(start)
+}
+
+// NoopInstrumentation is an [Instrumentation] that does nothing, it is used when none is set
+type NoopInstrumentation struct{}
+
+// StartSpan returns ctx and a span that does nothing
+func (NoopInstrumentation) StartSpan(ctx context.Context, _ string, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Retry does nothing
+func (NoopInstrumentation) Retry(context.Context, string, int, time.Duration, error) {}
+
+// TransactionWaitDone does nothing
+func (NoopInstrumentation) TransactionWaitDone(context.Context, TransactionWait) {}
+
+type noopSpan struct{}
+
+func (noopSpan) End(SpanResult) {}
+
+// SetInstrumentation sets where spans and measurements are reported, nil disables instrumentation
+//
+//	client.SetInstrumentation(myMetricsAdapter)
+func (rc *NodeClient) SetInstrumentation(instrumentation Instrumentation) {
+	rc.instrumentation = instrumentation
+}
+
+// instrument returns the instrumentation, never nil
+func (rc *NodeClient) instrument() Instrumentation {
+	if rc.instrumentation == nil {
+		return NoopInstrumentation{}
+	}
+	return rc.instrumentation
+}
+
+// routeSegments are the fixed path segments of the node API, any other segment is a parameter
+var routeSegments = map[string]bool{
+	"-":                  true,
+	"healthy":            true,
+	"accounts":           true,
+	"resource":           true,
+	"resources":          true,
+	"module":             true,
+	"modules":            true,
+	"transactions":       true,
+	"by_hash":            true,
+	"by_version":         true,
+	"wait_by_hash":       true,
+	"batch":              true,
+	"simulate":           true,
+	"blocks":             true,
+	"by_height":          true,
+	"events":             true,
+	"tables":             true,
+	"item":               true,
+	"raw_item":           true,
+	"view":               true,
+	"estimate_gas_price": true,
+}
+
+// operationName names a request by its route, with parameters replaced, so it can be used as a low cardinality label
+//
+//	operationName("GET", "https://node/v1", "https://node/v1/accounts/0x1/resources?limit=5") == "GET /accounts/{}/resources"
+func operationName(method string, baseUrl *url.URL, requestUrl string) string {
+	path := requestUrl
+	if rest, ok := strings.CutPrefix(requestUrl, baseUrl.String()); ok {
+		path = rest
+	} else if parsed, err := url.Parse(requestUrl); err == nil {
+		path = parsed.Path
+	}
+	path, _, _ = strings.Cut(path, "?")
+
+	builder := strings.Builder{}
+	builder.WriteString(method)
+	builder.WriteString(" ")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		builder.WriteString("/")
+		if routeSegments[segment] {
+			builder.WriteString(segment)
+		} else {
+			builder.WriteString("{}")
+		}
+	}
+	if len(segments) == 1 && segments[0] == "" {
+		builder.WriteString("/")
+	}
+	return builder.String()
+}
@@ -0,0 +1,133 @@
+package endless
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedSpan struct {
+	operation string
+	endpoint  string
+	result    SpanResult
+}
+
+// recordingInstrumentation keeps everything reported to it
+type recordingInstrumentation struct {
+	mu      sync.Mutex
+	spans   []recordedSpan
+	retries []string
+	waits   []TransactionWait
+}
+
+type recordingSpan struct {
+	parent *recordingInstrumentation
+	span   recordedSpan
+}
+
+func (span *recordingSpan) End(result SpanResult) {
+	span.parent.mu.Lock()
+	defer span.parent.mu.Unlock()
+	span.span.result = result
+	span.parent.spans = append(span.parent.spans, span.span)
+}
+
+func (r *recordingInstrumentation) StartSpan(ctx context.Context, operation string, endpoint string) (context.Context, Span) {
+	return ctx, &recordingSpan{parent: r, span: recordedSpan{operation: operation, endpoint: endpoint}}
+}
+
+func (r *recordingInstrumentation) Retry(_ context.Context, operation string, _ int, _ time.Duration, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries = append(r.retries, operation)
+}
+
+func (r *recordingInstrumentation) TransactionWaitDone(_ context.Context, wait TransactionWait) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.waits = append(r.waits, wait)
+}
+
+func TestInstrumentation_Requests(t *testing.T) {
+	var calls atomic.Int32
+	server := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderLedgerVersion, "42")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sequence_number":"1","authentication_key":["0x1"]}`))
+	})
+	client := newTestNodeClient(t, server)
+	client.SetRetryPolicy(testRetryPolicy())
+	recorder := &recordingInstrumentation{}
+	client.SetInstrumentation(recorder)
+
+	_, err := client.Account(AccountOne, 5)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"GET /accounts/{}"}, recorder.retries)
+	assert.Len(t, recorder.spans, 2)
+	failed, succeeded := recorder.spans[0], recorder.spans[1]
+	assert.Equal(t, "GET /accounts/{}", failed.operation)
+	assert.Equal(t, http.StatusServiceUnavailable, failed.result.StatusCode)
+	assert.Error(t, failed.result.Err)
+	assert.Equal(t, "GET /accounts/{}", succeeded.operation)
+	assert.True(t, strings.HasPrefix(succeeded.endpoint, "http://127.0.0.1:"))
+	assert.Equal(t, http.StatusOK, succeeded.result.StatusCode)
+	assert.NoError(t, succeeded.result.Err)
+	assert.Equal(t, uint64(42), succeeded.result.LedgerVersion)
+	assert.Greater(t, succeeded.result.ResponseBytes, 0)
+	assert.Greater(t, succeeded.result.Duration, time.Duration(0))
+}
+
+func TestInstrumentation_TransactionWait(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			_, _ = w.Write([]byte(`{"type":"pending_transaction","hash":"0x1234","sender":"0x1","sequence_number":"0","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"1"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"type":"user_transaction","version":"77","hash":"0x1234","success":true,"vm_status":"Executed successfully","sender":"0x1","sequence_number":"0","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"1","gas_used":"1","timestamp":"1","changes":[],"events":[]}`))
+	}))
+	recorder := &recordingInstrumentation{}
+	client.SetInstrumentation(recorder)
+
+	_, err := client.PollForTransaction("0x1234", PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Len(t, recorder.waits, 1)
+	wait := recorder.waits[0]
+	assert.True(t, wait.Committed)
+	assert.True(t, wait.Success)
+	assert.Equal(t, uint64(77), wait.Version)
+	assert.Equal(t, 3, wait.Attempts)
+	assert.NoError(t, wait.Err)
+
+	// Timeouts are reported for every transaction not yet committed
+	calls.Store(-100)
+	_, err = client.PollForTransactions([]string{"0x1", "0x2"}, PollPeriod(time.Millisecond), PollTimeout(10*time.Millisecond))
+	assert.Error(t, err)
+	assert.Len(t, recorder.waits, 3)
+	for _, wait := range recorder.waits[1:] {
+		assert.False(t, wait.Committed)
+		assert.Error(t, wait.Err)
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	baseUrl, _ := url.Parse("https://node.example/v1")
+	assert.Equal(t, "GET /", operationName("GET", baseUrl, "https://node.example/v1"))
+	assert.Equal(t, "GET /accounts/{}/resources", operationName("GET", baseUrl, "https://node.example/v1/accounts/0x1/resources?limit=5"))
+	assert.Equal(t, "GET /accounts/{}/resource/{}", operationName("GET", baseUrl, "https://node.example/v1/accounts/0x1/resource/0x1::account::Account"))
+	assert.Equal(t, "GET /transactions/by_hash/{}", operationName("GET", baseUrl, "https://node.example/v1/transactions/by_hash/abcd"))
+	assert.Equal(t, "POST /transactions/batch", operationName("POST", baseUrl, "https://node.example/v1/transactions/batch"))
+	// Other endpoints don't share the prefix, so the full path is used
+	assert.Equal(t, "GET /{}/-/healthy", operationName("GET", baseUrl, "https://other.example/v1/-/healthy"))
+}
@@ -0,0 +1,134 @@
+package endless
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RoundTripFunc sends a single HTTP request, it is the next step of an [Interceptor] chain
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps every HTTP request a [NodeClient] sends, including each retry and each failover attempt.  It can
+// change the request before calling next, inspect or replace the response after, or return without calling next at
+// all.  Interceptors run after the preset headers have been applied, in the order they were added.  Returning neither
+// a response nor an error fails the request.
+//
+//	client.Use(func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+//		req.Header.Set("X-Request-Id", uuid.NewString())
+//		return next(req)
+//	})
+type Interceptor func(req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+// DefaultRedactedHeaders are the headers hidden by [LoggingInterceptor] when no headers are given
+var DefaultRedactedHeaders = []string{"Authorization", "X-Api-Key", "Cookie", "Set-Cookie"}
+
+// Redacted is the value that replaces redacted header values
+const Redacted = "REDACTED"
+
+// Use adds interceptors to the end of the chain for all future requests
+//
+//	client.Use(APIKeyInterceptor("abcde"), LoggingInterceptor(slog.Default()))
+func (rc *NodeClient) Use(interceptors ...Interceptor) {
+	// Copy, so clients from WithContext don't share the backing array
+	rc.interceptors = slices.Concat(rc.interceptors, interceptors)
+}
+
+// roundTrip sends the request through the interceptor chain
+func (rc *NodeClient) roundTrip(req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(rc.client.Do)
+	for i := len(rc.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := rc.interceptors[i], next
+		next = func(req *http.Request) (*http.Response, error) {
+			response, err := interceptor(req, inner)
+			if err == nil && response == nil {
+				// Caught here, before the interceptors around it or the client use the missing response
+				return nil, fmt.Errorf("interceptor %d returned neither a response nor an error", i)
+			}
+			if response != nil {
+				// A response made up by the interceptor may leave these out
+				if response.Body == nil {
+					response.Body = http.NoBody
+				}
+				if response.Request == nil {
+					response.Request = req
+				}
+			}
+			return response, err
+		}
+	}
+	return next(req)
+}
+
+// APIKeyInterceptor authenticates every request with a static API key as a bearer token
+func APIKeyInterceptor(apiKey string) Interceptor {
+	return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		return next(req)
+	}
+}
+
+// RotatingAPIKeyInterceptor authenticates every request with a bearer token fetched from provider, so keys can be
+// rotated without recreating the client.  The request's context is passed to the provider.
+func RotatingAPIKeyInterceptor(provider func(ctx context.Context) (string, error)) Interceptor {
+	return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		apiKey, err := provider(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get API key: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+		return next(req)
+	}
+}
+
+// LoggingInterceptor logs every request and its latency to logger.  Requests that succeed are logged at debug level,
+// and failures at warn level.  The request headers are logged at debug level with the redacted headers hidden, which
+// are [DefaultRedactedHeaders] if none are given.
+func LoggingInterceptor(logger *slog.Logger, redactedHeaders ...string) Interceptor {
+	if len(redactedHeaders) == 0 {
+		redactedHeaders = DefaultRedactedHeaders
+	}
+	return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		ctx := req.Context()
+		logger.DebugContext(ctx, "node request",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"headers", RedactHeaders(req.Header, redactedHeaders...),
+		)
+		start := time.Now()
+		response, err := next(req)
+		latency := time.Since(start)
+		if err != nil {
+			logger.WarnContext(ctx, "node request failed", "method", req.Method, "url", req.URL.String(), "latency", latency, "err", err)
+			return nil, err
+		}
+		level := slog.LevelDebug
+		if response.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+		logger.Log(ctx, level, "node response",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"status", response.StatusCode,
+			"latency", latency,
+		)
+		return response, nil
+	}
+}
+
+// RedactHeaders returns a copy of header, with the values of the given headers replaced by [Redacted]
+func RedactHeaders(header http.Header, names ...string) http.Header {
+	redacted := header.Clone()
+	if redacted == nil {
+		return http.Header{}
+	}
+	for _, name := range names {
+		if _, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			redacted.Set(name, Redacted)
+		}
+	}
+	return redacted
+}
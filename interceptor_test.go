@@ -0,0 +1,131 @@
+package endless
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptor_Order(t *testing.T) {
+	var received http.Header
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"chain_id":4}`))
+	}))
+	client.SetHeader("X-Preset", "preset")
+
+	order := make([]string, 0)
+	record := func(name string) Interceptor {
+		return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+			order = append(order, name+" before")
+			// Preset headers are visible, and can be changed
+			req.Header.Set("X-Preset", req.Header.Get("X-Preset")+","+name)
+			response, err := next(req)
+			order = append(order, name+" after")
+			return response, err
+		}
+	}
+	client.Use(record("first"), record("second"))
+
+	_, err := client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, order)
+	assert.Equal(t, "preset,first,second", received.Get("X-Preset"))
+}
+
+func TestInterceptor_ShortCircuit(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	injected := errors.New("injected fault")
+	client.Use(func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return nil, injected
+	})
+
+	_, err := client.Info()
+	assert.ErrorIs(t, err, injected)
+	_, err = Post[map[string]any](client, client.baseUrl.String(), "application/json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, injected)
+}
+
+func TestInterceptor_NoResponse(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	client.Use(LoggingInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil))), func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return nil, nil
+	})
+
+	// A missing response is an error, not a panic
+	_, err := client.Info()
+	assert.ErrorContains(t, err, "interceptor 1 returned neither a response nor an error")
+
+	// Nor is a response without a body
+	client.interceptors = nil
+	client.Use(func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+	})
+	_, err = client.Info()
+	httpErr := &HttpError{}
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+}
+
+func TestInterceptor_APIKey(t *testing.T) {
+	var authorization string
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"chain_id":4}`))
+	}))
+
+	keys := []string{"one", "two"}
+	client.Use(RotatingAPIKeyInterceptor(func(ctx context.Context) (string, error) {
+		key := keys[0]
+		keys = keys[1:]
+		return key, nil
+	}))
+	_, err := client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer one", authorization)
+	_, err = client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer two", authorization)
+}
+
+func TestInterceptor_Logging(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"chain_id":4}`))
+	}))
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.Use(APIKeyInterceptor("secret-key"), LoggingInterceptor(logger))
+
+	_, err := client.Info()
+	assert.NoError(t, err)
+	output := buffer.String()
+	assert.Contains(t, output, "node response")
+	assert.Contains(t, output, "status=200")
+	assert.Contains(t, output, "latency=")
+	assert.Contains(t, output, Redacted)
+	assert.NotContains(t, output, "secret-key")
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abcde")
+	header.Set("Accept", "application/json")
+
+	redacted := RedactHeaders(header, "authorization", "X-Api-Key")
+	assert.Equal(t, Redacted, redacted.Get("Authorization"))
+	assert.Equal(t, "application/json", redacted.Get("Accept"))
+	assert.Empty(t, redacted.Values("X-Api-Key"))
+	// The original is untouched
+	assert.Equal(t, "Bearer abcde", header.Get("Authorization"))
+}
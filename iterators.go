@@ -0,0 +1,293 @@
+package endless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// PageSize is an option to the iterators, the number of items requested per page, 100 by default
+type PageSize uint64
+
+// StartAt is an option to the transaction iterators, the first version or sequence number to return.  By default,
+// forward iteration starts at the beginning, and backward iteration starts at the latest.
+type StartAt uint64
+
+// Prefetch is an option to the transaction iterators, the number of pages to fetch concurrently ahead of the one
+// being read, 0 by default
+type Prefetch int
+
+// Direction is an option to the transaction iterators, the order to return transactions in
+type Direction uint8
+
+const (
+	DirectionForward  Direction = iota // DirectionForward returns the oldest transactions first, and is the default
+	DirectionBackward                  // DirectionBackward returns the newest transactions first
+)
+
+const defaultIterPageSize = 100
+
+// iterOptions are the parsed options of an iterator
+type iterOptions struct {
+	pageSize  uint64
+	start     uint64
+	hasStart  bool
+	prefetch  int
+	direction Direction
+}
+
+func getIterOptions(options ...any) (opts iterOptions, err error) {
+	opts.pageSize = defaultIterPageSize
+	for i, arg := range options {
+		switch value := arg.(type) {
+		case PageSize:
+			if value == 0 {
+				return opts, errors.New("PageSize must be greater than 0")
+			}
+			opts.pageSize = uint64(value)
+		case StartAt:
+			opts.start = uint64(value)
+			opts.hasStart = true
+		case Prefetch:
+			opts.prefetch = max(int(value), 0)
+		case Direction:
+			opts.direction = value
+		default:
+			return opts, fmt.Errorf("iterator arg %d bad type %T", i+1, arg)
+		}
+	}
+	return opts, nil
+}
+
+// TransactionsIter iterates over the ledger's transactions, fetching a page at a time.  Iteration stops at the first
+// error, which is yielded with a nil transaction.  Forward iteration stops at the latest transaction.
+//
+// Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+//
+//	for txn, err := range client.TransactionsIter(endless.StartAt(1000), endless.Prefetch(4)) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(txn.Version())
+//	}
+func (rc *NodeClient) TransactionsIter(options ...any) iter.Seq2[*api.CommittedTransaction, error] {
+	latest := func(rc *NodeClient) (uint64, bool, error) {
+		info, err := rc.Info()
+		if err != nil {
+			return 0, false, err
+		}
+		return info.LedgerVersion(), true, nil
+	}
+	return indexedIter(rc, latest, func(rc *NodeClient, start uint64, limit uint64) ([]*api.CommittedTransaction, error) {
+		txns, err := rc.transactionsInner(&start, &limit)
+		if errors.Is(err, ErrVersionNotFound) {
+			// Past the latest version
+			return nil, nil
+		}
+		return txns, err
+	}, options...)
+}
+
+// AccountTransactionsIter iterates over the transactions sent by an account, by sequence number.  Iteration stops at
+// the first error, which is yielded with a nil transaction.
+//
+// Accepts options [PageSize], [StartAt], [Prefetch], and [Direction].
+//
+//	for txn, err := range client.AccountTransactionsIter(address, endless.DirectionBackward) {
+//		...
+//	}
+func (rc *NodeClient) AccountTransactionsIter(account AccountAddress, options ...any) iter.Seq2[*api.CommittedTransaction, error] {
+	latest := func(rc *NodeClient) (uint64, bool, error) {
+		info, err := rc.Account(account)
+		if err != nil {
+			return 0, false, err
+		}
+		sequenceNumber, err := info.SequenceNumber()
+		if err != nil || sequenceNumber == 0 {
+			return 0, false, err
+		}
+		return sequenceNumber - 1, true, nil
+	}
+	return indexedIter(rc, latest, func(rc *NodeClient, start uint64, limit uint64) ([]*api.CommittedTransaction, error) {
+		return rc.accountTransactionsInner(account, &start, &limit)
+	}, options...)
+}
+
+// AccountResourcesIter iterates over an account's resources, following the node's pagination cursor.  All pages are
+// read at the ledger version of the first page.  Iteration stops at the first error.
+//
+// Accepts option [PageSize].  Resources have no order, so [Direction], [StartAt] and [Prefetch] are not supported.
+func (rc *NodeClient) AccountResourcesIter(address AccountAddress, options ...any) iter.Seq2[AccountResourceInfo, error] {
+	return cursorIter[AccountResourceInfo](rc, rc.baseUrl.JoinPath("accounts", address.String(), "resources"), "get resources api err", options...)
+}
+
+// AccountModulesIter iterates over the modules published by an account, following the node's pagination cursor.
+// All pages are read at the ledger version of the first page.  Iteration stops at the first error.
+//
+// Accepts option [PageSize].  Modules have no order, so [Direction], [StartAt] and [Prefetch] are not supported.
+func (rc *NodeClient) AccountModulesIter(address AccountAddress, options ...any) iter.Seq2[*api.MoveBytecode, error] {
+	return cursorIter[*api.MoveBytecode](rc, rc.baseUrl.JoinPath("accounts", address.String(), "modules"), "get modules api err", options...)
+}
+
+// indexedIter iterates over pages addressed by position, such as a version or sequence number.
+//
+// latest gives the position of the newest item, for iterating backwards, or false if there are none.  If it is nil,
+// iterating backwards requires [StartAt].
+func indexedIter[T any](
+	rc *NodeClient,
+	latest func(rc *NodeClient) (uint64, bool, error),
+	fetchPage func(rc *NodeClient, start uint64, limit uint64) ([]T, error),
+	options ...any,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		opts, err := getIterOptions(options...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		// Abandon any prefetched pages when iteration stops
+		ctx, cancel := context.WithCancel(rc.Context())
+		defer cancel()
+		view := rc.WithContext(ctx)
+
+		backward := opts.direction == DirectionBackward
+		next := opts.start
+		if backward && !opts.hasStart {
+			if latest == nil {
+				yield(zero, errors.New("iterating backward requires the StartAt option"))
+				return
+			}
+			var ok bool
+			next, ok, err = latest(view)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !ok {
+				return
+			}
+		}
+
+		// nextPage plans the next page, returning false when there are no more
+		done := false
+		nextPage := func() (start uint64, limit uint64, ok bool) {
+			if done {
+				return 0, 0, false
+			}
+			if !backward {
+				start, limit = next, opts.pageSize
+				next += opts.pageSize
+				return start, limit, true
+			}
+			end := next
+			if end+1 <= opts.pageSize {
+				start = 0
+				done = true
+			} else {
+				start = end + 1 - opts.pageSize
+				next = start - 1
+			}
+			return start, end - start + 1, true
+		}
+
+		type page struct {
+			items []T
+			limit uint64
+			err   error
+		}
+		inflight := make([]chan page, 0, opts.prefetch+1)
+		launch := func() {
+			start, limit, ok := nextPage()
+			if !ok {
+				return
+			}
+			result := make(chan page, 1)
+			go func() {
+				items, err := fetchPage(view, start, limit)
+				result <- page{items: items, limit: limit, err: err}
+			}()
+			inflight = append(inflight, result)
+		}
+		for range opts.prefetch + 1 {
+			launch()
+		}
+
+		for len(inflight) > 0 {
+			current := <-inflight[0]
+			inflight = inflight[1:]
+			if current.err != nil {
+				yield(zero, current.err)
+				return
+			}
+			if backward {
+				slices.Reverse(current.items)
+			}
+			for _, item := range current.items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !backward && uint64(len(current.items)) < current.limit {
+				// Reached the latest
+				return
+			}
+			launch()
+		}
+	}
+}
+
+// cursorIter iterates over pages linked by the node's [HeaderCursor]
+func cursorIter[T any](rc *NodeClient, listUrl *url.URL, errPrefix string, options ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		opts, err := getIterOptions(options...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if opts.hasStart || opts.prefetch > 0 || opts.direction != DirectionForward {
+			yield(zero, errors.New("cursor based iterators only support the PageSize option"))
+			return
+		}
+
+		pageUrl := *listUrl
+		params := url.Values{}
+		params.Set("limit", strconv.FormatUint(opts.pageSize, 10))
+		for {
+			pageUrl.RawQuery = params.Encode()
+			var items []T
+			var header http.Header
+			items, header, err = getWithHeader[[]T](rc, pageUrl.String())
+			if err != nil {
+				yield(zero, fmt.Errorf("%s: %w", errPrefix, err))
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			cursor := header.Get(HeaderCursor)
+			if cursor == "" {
+				return
+			}
+			params.Set("start", cursor)
+			// Pin later pages to the first page's version, so the listing is consistent
+			if params.Get("ledger_version") == "" {
+				if version, ok := ledgerVersionFromHeader(header); ok {
+					params.Set("ledger_version", strconv.FormatUint(version, 10))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package endless
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testUserTransactionJson(version uint64, sequenceNumber uint64, success bool, vmStatus string) string {
+	return fmt.Sprintf(`{"type":"user_transaction","version":"%d","hash":"0x1234","success":%t,"vm_status":%q,"sender":"0x1","sequence_number":"%d","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"1","gas_used":"1","timestamp":"1","changes":[],"events":[]}`, version, success, vmStatus, sequenceNumber)
+}
+
+// newTestLedger serves a ledger with numTxns transactions, and counts the pages requested
+func newTestLedger(t *testing.T, numTxns uint64, pages *atomic.Int32) *NodeClient {
+	t.Helper()
+	return newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/v1"):
+			_, _ = fmt.Fprintf(w, `{"chain_id":4,"ledger_version":"%d"}`, numTxns-1)
+		case strings.HasSuffix(r.URL.Path, "/transactions"):
+			pages.Add(1)
+			limit, _ := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+			// Without a start, the node returns the latest page, capped at 100
+			start := numTxns - min(limit, 100, numTxns)
+			if r.URL.Query().Has("start") {
+				start, _ = strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+			}
+			if start >= numTxns {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Ledger version not found","error_code":"version_not_found"}`))
+				return
+			}
+			txns := make([]string, 0)
+			for version := start; version < min(start+limit, numTxns); version++ {
+				txns = append(txns, testUserTransactionJson(version, version, true, VmStatusSuccess))
+			}
+			_, _ = w.Write([]byte("[" + strings.Join(txns, ",") + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestTransactionsIter_Forward(t *testing.T) {
+	var pages atomic.Int32
+	client := newTestLedger(t, 250, &pages)
+
+	expected := uint64(20)
+	for txn, err := range client.TransactionsIter(StartAt(20), PageSize(50), Prefetch(3)) {
+		assert.NoError(t, err)
+		assert.Equal(t, expected, txn.Version())
+		expected++
+	}
+	assert.Equal(t, uint64(250), expected)
+
+	// Exact multiples of the page size end on the node's not found
+	expected = 0
+	for txn, err := range client.TransactionsIter(PageSize(50)) {
+		assert.NoError(t, err)
+		assert.Equal(t, expected, txn.Version())
+		expected++
+	}
+	assert.Equal(t, uint64(250), expected)
+}
+
+func TestTransactionsIter_Backward(t *testing.T) {
+	var pages atomic.Int32
+	client := newTestLedger(t, 250, &pages)
+
+	expected := uint64(249)
+	count := 0
+	for txn, err := range client.TransactionsIter(DirectionBackward, PageSize(40), Prefetch(2)) {
+		assert.NoError(t, err)
+		assert.Equal(t, expected, txn.Version())
+		expected--
+		count++
+	}
+	assert.Equal(t, 250, count)
+
+	versions := make([]uint64, 0)
+	for txn, err := range client.TransactionsIter(DirectionBackward, StartAt(5), PageSize(4)) {
+		assert.NoError(t, err)
+		versions = append(versions, txn.Version())
+	}
+	assert.Equal(t, []uint64{5, 4, 3, 2, 1, 0}, versions)
+}
+
+func TestTransactionsIter_Break(t *testing.T) {
+	var pages atomic.Int32
+	client := newTestLedger(t, 10_000, &pages)
+
+	count := 0
+	for _, err := range client.TransactionsIter(PageSize(10)) {
+		assert.NoError(t, err)
+		count++
+		if count == 15 {
+			break
+		}
+	}
+	assert.Equal(t, 15, count)
+	assert.Equal(t, int32(2), pages.Load())
+}
+
+func TestTransactionsIter_Error(t *testing.T) {
+	var pages atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if pages.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("[" + testUserTransactionJson(0, 0, true, VmStatusSuccess) + "," + testUserTransactionJson(1, 1, true, VmStatusSuccess) + "]"))
+	}))
+
+	count := 0
+	var lastErr error
+	for txn, err := range client.TransactionsIter(PageSize(2)) {
+		if err != nil {
+			assert.Nil(t, txn)
+			lastErr = err
+			continue
+		}
+		count++
+	}
+	assert.Equal(t, 2, count)
+	assert.Error(t, lastErr)
+
+	_, err := func() (any, error) {
+		for _, err := range client.TransactionsIter(PageSize(0)) {
+			return nil, err
+		}
+		return nil, nil
+	}()
+	assert.Error(t, err)
+}
+
+func TestAccountResourcesIter(t *testing.T) {
+	var ledgerVersions []string
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		ledgerVersions = append(ledgerVersions, r.URL.Query().Get("ledger_version"))
+		w.Header().Set(HeaderLedgerVersion, strconv.Itoa(100+len(ledgerVersions)))
+		switch r.URL.Query().Get("start") {
+		case "":
+			w.Header().Set(HeaderCursor, "cursor1")
+			_, _ = w.Write([]byte(`[{"type":"0x1::a::A","data":{}},{"type":"0x1::b::B","data":{}}]`))
+		case "cursor1":
+			_, _ = w.Write([]byte(`[{"type":"0x1::c::C","data":{}}]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+
+	types := make([]string, 0)
+	for resource, err := range client.AccountResourcesIter(AccountOne, PageSize(2)) {
+		assert.NoError(t, err)
+		types = append(types, resource.Type)
+	}
+	assert.Equal(t, []string{"0x1::a::A", "0x1::b::B", "0x1::c::C"}, types)
+	// The second page is read at the same version as the first
+	assert.Equal(t, []string{"", "101"}, ledgerVersions)
+
+	for _, err := range client.AccountResourcesIter(AccountOne, DirectionBackward) {
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,280 @@
+package endless
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/endless-labs/endless-go-sdk/internal/util"
+)
+
+var (
+	accountAddressType  = reflect.TypeFor[AccountAddress]()
+	bigIntType          = reflect.TypeFor[big.Int]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// UnmarshalMoveJson decodes the JSON form of a Move value, as returned by the node, into out.  It handles the ways
+// Move values are encoded in JSON, which [json.Unmarshal] doesn't:
+//   - u64, u128, and u256 are decimal strings, and can be decoded into any integer type or [big.Int]
+//   - vector<u8> is a hex string, and can be decoded into []byte or a byte array
+//   - 0x1::object::Object is {"inner": address}, and can be decoded into an [AccountAddress]
+//   - 0x1::option::Option is {"vec": []} or {"vec": [value]}, and can be decoded into a pointer, nil for none
+//
+// Struct fields are matched by their json tag, or case-insensitively by name.  Types implementing [json.Unmarshaler]
+// decode themselves.
+func UnmarshalMoveJson(data []byte, out any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return decodeMoveJson(value, out)
+}
+
+// decodeMoveJson decodes an already parsed JSON value into out, which must be a non-nil pointer
+func decodeMoveJson(value any, out any) error {
+	target := reflect.ValueOf(out)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("cannot decode Move JSON into %T, must be a non-nil pointer", out)
+	}
+	return decodeMoveJsonValue(value, target.Elem(), "")
+}
+
+func decodeMoveJsonValue(value any, target reflect.Value, path string) error {
+	badValue := func() error {
+		return fmt.Errorf("cannot decode Move JSON %T into %s at %q", value, target.Type(), path)
+	}
+
+	switch target.Type() {
+	case accountAddressType:
+		if object, ok := value.(map[string]any); ok && len(object) == 1 {
+			// Object<T> wraps its address
+			value = object["inner"]
+		}
+		str, ok := value.(string)
+		if !ok {
+			return badValue()
+		}
+		address, err := ConvertToAddress(str)
+		if err != nil {
+			return fmt.Errorf("bad address at %q: %w", path, err)
+		}
+		target.Set(reflect.ValueOf(*address))
+		return nil
+	case bigIntType:
+		num, ok := new(big.Int).SetString(numberString(value), 10)
+		if !ok {
+			return badValue()
+		}
+		target.Set(reflect.ValueOf(num).Elem())
+		return nil
+	}
+	if target.CanAddr() && target.Addr().Type().Implements(jsonUnmarshalerType) {
+		blob, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return target.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(blob)
+	}
+
+	switch target.Kind() {
+	case reflect.Pointer:
+		if value == nil {
+			target.SetZero()
+			return nil
+		}
+		if object, ok := value.(map[string]any); ok && isMoveOption(object, target.Type().Elem()) {
+			vec := object["vec"].([]any)
+			switch len(vec) {
+			case 0:
+				target.SetZero()
+				return nil
+			case 1:
+				value = vec[0]
+			default:
+				return fmt.Errorf("bad option at %q, has %d values", path, len(vec))
+			}
+		}
+		elem := reflect.New(target.Type().Elem())
+		if err := decodeMoveJsonValue(value, elem.Elem(), path); err != nil {
+			return err
+		}
+		target.Set(elem)
+	case reflect.Interface:
+		if value == nil {
+			target.SetZero()
+		} else if target.NumMethod() == 0 {
+			target.Set(reflect.ValueOf(value))
+		} else {
+			return badValue()
+		}
+	case reflect.Bool:
+		switch value := value.(type) {
+		case bool:
+			target.SetBool(value)
+		case string:
+			b, err := ConvertToBool(value)
+			if err != nil {
+				return fmt.Errorf("bad bool at %q: %w", path, err)
+			}
+			target.SetBool(b)
+		default:
+			return badValue()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		num, err := strconv.ParseUint(numberString(value), 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("bad integer at %q: %w", path, err)
+		}
+		target.SetUint(num)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		num, err := strconv.ParseInt(numberString(value), 10, target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("bad integer at %q: %w", path, err)
+		}
+		target.SetInt(num)
+	case reflect.Float32, reflect.Float64:
+		num, err := strconv.ParseFloat(numberString(value), target.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("bad number at %q: %w", path, err)
+		}
+		target.SetFloat(num)
+	case reflect.String:
+		str := numberString(value)
+		if str == "" && value != "" {
+			return badValue()
+		}
+		target.SetString(str)
+	case reflect.Slice:
+		if str, ok := value.(string); ok && target.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := util.ParseHex(str)
+			if err != nil {
+				return fmt.Errorf("bad bytes at %q: %w", path, err)
+			}
+			target.SetBytes(b)
+			return nil
+		}
+		items, ok := value.([]any)
+		if !ok {
+			if value == nil {
+				target.SetZero()
+				return nil
+			}
+			return badValue()
+		}
+		slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeMoveJsonValue(item, slice.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+	case reflect.Array:
+		if str, ok := value.(string); ok && target.Type().Elem().Kind() == reflect.Uint8 {
+			b, err := util.ParseHex(str)
+			if err != nil {
+				return fmt.Errorf("bad bytes at %q: %w", path, err)
+			}
+			if len(b) != target.Len() {
+				return fmt.Errorf("bad bytes at %q, expected %d bytes got %d", path, target.Len(), len(b))
+			}
+			reflect.Copy(target, reflect.ValueOf(b))
+			return nil
+		}
+		items, ok := value.([]any)
+		if !ok || len(items) != target.Len() {
+			return badValue()
+		}
+		for i, item := range items {
+			if err := decodeMoveJsonValue(item, target.Index(i), path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return badValue()
+		}
+		m := reflect.MakeMapWithSize(target.Type(), len(object))
+		for key, item := range object {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeMoveJsonValue(item, elem, path+"."+key); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		}
+		target.Set(m)
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return badValue()
+		}
+		for key, item := range object {
+			field, ok := structField(target, key)
+			if !ok {
+				// Like encoding/json, unknown fields are ignored
+				continue
+			}
+			if err := decodeMoveJsonValue(item, field, path+"."+key); err != nil {
+				return err
+			}
+		}
+	default:
+		return badValue()
+	}
+	return nil
+}
+
+// numberString returns the digits of a JSON number or string, or "" for anything else
+func numberString(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// isMoveOption checks if the object is a Move option, rather than a struct with a field named vec
+func isMoveOption(object map[string]any, elemType reflect.Type) bool {
+	if _, ok := object["vec"].([]any); !ok || len(object) != 1 {
+		return false
+	}
+	if elemType.Kind() != reflect.Struct {
+		return true
+	}
+	_, hasVec := structField(reflect.New(elemType).Elem(), "vec")
+	return !hasVec
+}
+
+// structField finds the exported field for a JSON key, by json tag or by name ignoring case and underscores
+func structField(target reflect.Value, key string) (reflect.Value, bool) {
+	structType := target.Type()
+	var byName reflect.Value
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-":
+			continue
+		case name == key:
+			return target.Field(i), true
+		case name == "" && strings.EqualFold(field.Name, strings.ReplaceAll(key, "_", "")) && !byName.IsValid():
+			byName = target.Field(i)
+		}
+	}
+	return byName, byName.IsValid()
+}
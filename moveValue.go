@@ -0,0 +1,273 @@
+package endless
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/endless-labs/endless-go-sdk/internal/util"
+)
+
+// maxMoveValueDepth is the deepest nesting of vectors and structs decoded, as in the Move VM
+const maxMoveValueDepth = 128
+
+// MoveValue is a Move value decoded from BCS by [DecodeMoveValue], as a tree of values with their types.  The Go type
+// of Value depends on the Move type:
+//   - bool, u8, u16, u32, and u64 are bool, uint8, uint16, uint32, and uint64
+//   - u128 and u256 are *big.Int
+//   - address and signer are [AccountAddress]
+//   - vector<u8> is []byte, and other vectors are []*MoveValue
+//   - 0x1::string::String is string
+//   - 0x1::option::Option is nil for none, or the *MoveValue for some
+//   - other structs, including 0x1::object::Object, are []MoveField in the order of the struct's fields
+//
+// It's rendered to JSON the same way as the node's REST API, so it can be decoded like JSON from the API with
+// [MoveValue.Decode].
+type MoveValue struct {
+	Type  TypeTag // Type of the value, with any generics substituted
+	Value any     // Value is the Go value for the Move type
+}
+
+// MoveField is a field of a Move struct value
+type MoveField struct {
+	Name  string     // Name of the field
+	Value *MoveValue // Value of the field
+}
+
+// DecodeMoveValue decodes BCS of the given type, such as the data of a resource from [NodeClient.AccountResourcesBCS].
+// The layouts of structs are looked up in layouts, which can be a [NodeClient] to fetch them, or an [AbiCache] or
+// [AbiStructLayouts] to work offline.  Layouts aren't needed for 0x1::string::String, 0x1::option::Option, and
+// 0x1::object::Object.
+//
+// This function will error if there are remaining bytes.
+//
+//	value, err := DecodeMoveValue(TypeTag{Value: &record.Tag}, record.Data, client)
+//	blob, err := json.Marshal(value)
+func DecodeMoveValue(typeTag TypeTag, data []byte, layouts StructLayouts) (*MoveValue, error) {
+	des := bcs.NewDeserializer(data)
+	value := decodeMoveValue(des, typeTag, layouts, 0)
+	if des.Error() != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", typeTag.String(), des.Error())
+	}
+	if des.Remaining() > 0 {
+		return nil, fmt.Errorf("failed to decode %s: remaining %d byte(s)", typeTag.String(), des.Remaining())
+	}
+	return value, nil
+}
+
+func decodeMoveValue(des *bcs.Deserializer, typeTag TypeTag, layouts StructLayouts, depth int) *MoveValue {
+	if depth > maxMoveValueDepth {
+		des.SetError(errors.New("value is nested too deeply"))
+		return nil
+	}
+	value := &MoveValue{Type: typeTag}
+	switch inner := typeTag.Value.(type) {
+	case *BoolTag:
+		value.Value = des.Bool()
+	case *U8Tag:
+		value.Value = des.U8()
+	case *U16Tag:
+		value.Value = des.U16()
+	case *U32Tag:
+		value.Value = des.U32()
+	case *U64Tag:
+		value.Value = des.U64()
+	case *U128Tag:
+		num := des.U128()
+		value.Value = &num
+	case *U256Tag:
+		num := des.U256()
+		value.Value = &num
+	case *AddressTag, *SignerTag:
+		address := AccountAddress{}
+		des.Struct(&address)
+		value.Value = address
+	case *ReferenceTag:
+		return decodeMoveValue(des, inner.TypeParam, layouts, depth)
+	case *VectorTag:
+		if _, ok := inner.TypeParam.Value.(*U8Tag); ok {
+			value.Value = des.ReadBytes()
+			break
+		}
+		length := des.SequenceLength()
+		if des.Error() != nil {
+			return nil
+		}
+		// Every Move value takes at least a byte, so don't allocate for a bad length
+		if length > des.Remaining() {
+			des.SetError(fmt.Errorf("not enough bytes remaining to decode %d items of %s", length, typeTag.String()))
+			return nil
+		}
+		items := make([]*MoveValue, length)
+		for i := range items {
+			items[i] = decodeMoveValue(des, inner.TypeParam, layouts, depth+1)
+			if des.Error() != nil {
+				return nil
+			}
+		}
+		value.Value = items
+	case *StructTag:
+		value.Value = decodeMoveStruct(des, inner, layouts, depth)
+	default:
+		des.SetError(fmt.Errorf("cannot decode type %s", typeTag.String()))
+	}
+	if des.Error() != nil {
+		return nil
+	}
+	return value
+}
+
+func decodeMoveStruct(des *bcs.Deserializer, structTag *StructTag, layouts StructLayouts, depth int) any {
+	switch {
+	case isFrameworkType(structTag, "string", "String"):
+		return des.ReadString()
+	case isFrameworkType(structTag, "option", "Option") && len(structTag.TypeParams) == 1:
+		switch length := des.Uleb128(); length {
+		case 0:
+			return nil
+		case 1:
+			return decodeMoveValue(des, structTag.TypeParams[0], layouts, depth+1)
+		default:
+			des.SetError(fmt.Errorf("expected 0 or 1 element as an option, got %d", length))
+			return nil
+		}
+	case isFrameworkType(structTag, "object", "Object"):
+		inner := decodeMoveValue(des, TypeTag{Value: &AddressTag{}}, layouts, depth+1)
+		return []MoveField{{Name: "inner", Value: inner}}
+	}
+
+	if layouts == nil {
+		des.SetError(fmt.Errorf("no layout for struct %s", structTag.String()))
+		return nil
+	}
+	layout, err := layouts.StructLayout(structTag)
+	if err != nil {
+		des.SetError(err)
+		return nil
+	}
+	fields := make([]MoveField, len(layout.Fields))
+	for i, field := range layout.Fields {
+		fieldType, err := ParseTypeTag(field.Type)
+		if err == nil {
+			// Field types refer to the struct's type parameters
+			*fieldType, err = substituteGenerics(*fieldType, structTag.TypeParams)
+		}
+		if err != nil {
+			des.SetError(fmt.Errorf("field %s of struct %s: %w", field.Name, structTag.String(), err))
+			return nil
+		}
+		fields[i] = MoveField{Name: field.Name, Value: decodeMoveValue(des, *fieldType, layouts, depth+1)}
+		if des.Error() != nil {
+			return nil
+		}
+	}
+	return fields
+}
+
+func isFrameworkType(structTag *StructTag, module string, name string) bool {
+	return structTag.Address == AccountOne && structTag.Module == module && structTag.Name == name
+}
+
+// Field returns the value of a struct's field, or nil if it's not a struct or has no such field
+func (mv *MoveValue) Field(name string) *MoveValue {
+	fields, _ := mv.Value.([]MoveField)
+	for _, field := range fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+// Decode decodes the value into out, in the same way as [UnmarshalMoveJson] decodes JSON from the REST API
+//
+//	store := FungibleStore{}
+//	err := value.Decode(&store)
+func (mv *MoveValue) Decode(out any) error {
+	blob, err := json.Marshal(mv)
+	if err != nil {
+		return err
+	}
+	return UnmarshalMoveJson(blob, out)
+}
+
+// MarshalJSON renders the value as the REST API does, with u64, u128, and u256 as strings, vector<u8> as hex, options
+// as {"vec": [...]}, and structs as objects with their fields in order
+func (mv *MoveValue) MarshalJSON() ([]byte, error) {
+	out := &bytes.Buffer{}
+	if err := mv.writeJson(out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (mv *MoveValue) writeJson(out *bytes.Buffer) error {
+	switch value := mv.Value.(type) {
+	case bool:
+		out.WriteString(strconv.FormatBool(value))
+	case uint8, uint16, uint32:
+		out.WriteString(fmt.Sprintf("%d", value))
+	case uint64:
+		out.WriteString(`"` + strconv.FormatUint(value, 10) + `"`)
+	case *big.Int:
+		out.WriteString(`"` + value.String() + `"`)
+	case AccountAddress:
+		if value.IsSpecial() {
+			out.WriteString(`"` + value.String() + `"`)
+		} else {
+			out.WriteString(`"` + value.StringLong() + `"`)
+		}
+	case []byte:
+		out.WriteString(`"` + util.BytesToHex(value) + `"`)
+	case string:
+		blob, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		out.Write(blob)
+	case []*MoveValue:
+		out.WriteByte('[')
+		for i, item := range value {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := item.writeJson(out); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+	case *MoveValue:
+		out.WriteString(`{"vec":[`)
+		if err := value.writeJson(out); err != nil {
+			return err
+		}
+		out.WriteString(`]}`)
+	case nil:
+		// Only options are nil
+		out.WriteString(`{"vec":[]}`)
+	case []MoveField:
+		out.WriteByte('{')
+		for i, field := range value {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			name, err := json.Marshal(field.Name)
+			if err != nil {
+				return err
+			}
+			out.Write(name)
+			out.WriteByte(':')
+			if err = field.Value.writeJson(out); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+	default:
+		return fmt.Errorf("cannot render %T of %s as JSON", value, mv.Type.String())
+	}
+	return nil
+}
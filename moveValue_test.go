@@ -0,0 +1,149 @@
+package endless
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccountModuleJson = `{"address":"0x1","name":"account","friends":[],"exposed_functions":[],"structs":[
+	{"name":"Account","is_native":false,"abilities":["key"],"generic_type_params":[],"fields":[
+		{"name":"authentication_key","type":"vector<vector<u8>>"},
+		{"name":"sequence_number","type":"u64"},
+		{"name":"guid_creation_num","type":"u64"},
+		{"name":"num_signatures_required","type":"u64"}
+	]}
+]}`
+
+const testHolderModuleJson = `{"address":"0xcafe","name":"holder","friends":[],"exposed_functions":[],"structs":[
+	{"name":"Holder","is_native":false,"abilities":["key"],"generic_type_params":[{"constraints":[]}],"fields":[
+		{"name":"value","type":"T0"},
+		{"name":"items","type":"vector<T0>"},
+		{"name":"name","type":"0x1::string::String"},
+		{"name":"store","type":"0x1::object::Object<0x1::fungible_asset::FungibleStore>"},
+		{"name":"memo","type":"0x1::option::Option<0x1::string::String>"},
+		{"name":"none","type":"0x1::option::Option<u8>"},
+		{"name":"amount","type":"u128"},
+		{"name":"small","type":"u16"},
+		{"name":"flag","type":"bool"}
+	]}
+]}`
+
+func TestDecodeMoveValue_Resource(t *testing.T) {
+	blob, err := decodeB64("AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABB2FjY291bnQHQWNjb3VudAA6ASAoJOVv\nbuFF0+C4aKaA1KaWubBIsTAA65TF9lLMugWD50wAAAAAAAAAAAAAAAAAAAABAAAAAAAAAA==")
+	assert.NoError(t, err)
+	resources := bcs.DeserializeSequence[AccountResourceRecord](bcs.NewDeserializer(blob))
+
+	accountAbi, _ := testStructModule(t, testAccountModuleJson)
+	value, err := resources[0].Decode(AbiStructLayouts{accountAbi})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(76), value.Field("sequence_number").Value)
+
+	rendered, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"authentication_key": ["0x2824e56f6ee145d3e0b868a680d4a696b9b048b13000eb94c5f652ccba0583e7"],
+		"sequence_number": "76",
+		"guid_creation_num": "0",
+		"num_signatures_required": "1"
+	}`, string(rendered))
+
+	// Decoding matches the hand-written BCS
+	account := AccountData{}
+	assert.NoError(t, value.Decode(&account))
+	expected := AccountData{}
+	assert.NoError(t, bcs.Deserialize(&expected, resources[0].Data))
+	assert.Equal(t, expected, account)
+
+	// Offline from the ABI cache
+	cache, err := NewAbiCache()
+	assert.NoError(t, err)
+	_, err = resources[0].Decode(cache)
+	assert.ErrorContains(t, err, "no cached ABI")
+	assert.NoError(t, cache.Put(accountAbi))
+	_, err = resources[0].Decode(cache)
+	assert.NoError(t, err)
+
+	// Loaded ABIs still decode once fetched ones would have expired
+	now := time.Now()
+	loaded, err := NewAbiCache()
+	assert.NoError(t, err)
+	loaded.now = func() time.Time { return now }
+	assert.NoError(t, loaded.Load(strings.NewReader(testAccountModuleJson)))
+	now = now.Add(defaultAbiCacheTTL + time.Minute)
+	_, err = DecodeMoveValue(TypeTag{Value: &resources[0].Tag}, resources[0].Data, loaded)
+	assert.NoError(t, err)
+
+	// Errors
+	_, err = resources[0].Decode(nil)
+	assert.ErrorContains(t, err, "no layout")
+	_, err = DecodeMoveValue(TypeTag{Value: &resources[0].Tag}, append(resources[0].Data, 0), cache)
+	assert.ErrorContains(t, err, "remaining 1 byte")
+	_, err = DecodeMoveValue(TypeTag{Value: &VectorTag{TypeParam: TypeTag{Value: &U64Tag{}}}}, []byte{0xff, 0x01}, nil)
+	assert.ErrorContains(t, err, "not enough bytes")
+}
+
+func TestDecodeMoveValue_Generic(t *testing.T) {
+	type key struct {
+		Id   big.Int
+		Name string
+	}
+	type holder struct {
+		Value  key
+		Items  []key
+		Name   string
+		Store  AccountAddress
+		Memo   *string `bcs:"optional"`
+		None   *uint8  `bcs:"optional"`
+		Amount big.Int
+		Small  uint16
+		Flag   bool
+	}
+	memo := "memo"
+	store := AccountAddress{}
+	store[0] = 0xab
+	data, err := bcs.Marshal(&holder{
+		Value:  key{Id: *big.NewInt(1), Name: "a"},
+		Items:  []key{{Id: *big.NewInt(2), Name: "b"}},
+		Name:   "holder",
+		Store:  store,
+		Memo:   &memo,
+		Amount: *big.NewInt(1000),
+		Small:  7,
+		Flag:   true,
+	})
+	assert.NoError(t, err)
+
+	holderAbi, _ := testStructModule(t, testHolderModuleJson)
+	keysAbi, _ := testStructModule(t, testStructModuleJson)
+	holderType, err := ParseTypeTag("0xcafe::holder::Holder<0xcafe::keys::Key>")
+	assert.NoError(t, err)
+	value, err := DecodeMoveValue(*holderType, data, AbiStructLayouts{holderAbi, keysAbi})
+	assert.NoError(t, err)
+
+	rendered, err := json.Marshal(value)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"value":{"id":"1","name":"a"},"items":[{"id":"2","name":"b"}],"name":"holder",`+
+		`"store":{"inner":"`+store.StringLong()+`"},"memo":{"vec":["memo"]},"none":{"vec":[]},"amount":"1000","small":7,"flag":true}`, string(rendered))
+
+	// Generics are substituted in the types
+	keyType, err := ParseTypeTag("0xcafe::keys::Key")
+	assert.NoError(t, err)
+	assert.Equal(t, keyType.String(), value.Field("value").Type.String())
+	assert.Equal(t, "a", value.Field("value").Field("name").Value)
+	assert.Equal(t, big.NewInt(1000), value.Field("amount").Value)
+	assert.Nil(t, value.Field("none").Value)
+	assert.Nil(t, value.Field("missing"))
+
+	// Missing layouts
+	_, err = DecodeMoveValue(*holderType, data, AbiStructLayouts{holderAbi})
+	assert.Error(t, err)
+	_, err = DecodeMoveValue(*holderType, data, AbiStructLayouts{&api.MoveModule{}})
+	assert.Error(t, err)
+}
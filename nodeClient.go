@@ -2,6 +2,7 @@ package endless
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,7 +12,6 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"sort"
 	"strconv"
 	"time"
 
@@ -43,22 +43,38 @@ type NodeClient struct {
 	baseUrl *url.URL          // Base URL of the node
 	chainId uint8             // Chain ID of the network
 	headers map[string]string // Headers to be added to every transaction
+	ctx     context.Context   // Context applied to every request, nil means [context.Background]
+
+	retryPolicy *RetryPolicy  // Policy for retrying failed requests, nil means no retries
+	endpoints   *endpointPool // Endpoints to balance requests across, nil means only baseUrl is used
+
+	pageFetcher     *PageFetcher    // How ranges of transactions are fetched, nil means [DefaultPageFetcher]
+	interceptors    []Interceptor   // Interceptors wrapping every request, in order
+	instrumentation Instrumentation // Where spans and measurements are reported, nil means nowhere
+	abiCache        *AbiCache       // Module ABIs used by the ABI based builders, nil means no caching
 }
 
 // NewNodeClient creates a new client for interacting with an EndlessCoin nodE API
 func NewNodeClient(rpcUrl string, chainId uint8) (*NodeClient, error) {
+	defaultClient, err := newDefaultHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewNodeClientWithHttpClient(rpcUrl, chainId, defaultClient)
+}
+
+// newDefaultHttpClient creates the http.Client used when one isn't provided
+func newDefaultHttpClient() (*http.Client, error) {
 	// Set cookie jar so cookie stickiness applies to connections
 	// TODO Add appropriate suffix list
 	jar, err := cookiejar.New(nil)
 	if err != nil {
 		return nil, err
 	}
-	defaultClient := &http.Client{
+	return &http.Client{
 		Jar:     jar,
 		Timeout: 60 * time.Second,
-	}
-
-	return NewNodeClientWithHttpClient(rpcUrl, chainId, defaultClient)
+	}, nil
 }
 
 // NewNodeClientWithHttpClient creates a new client for interacting with an EndlessCoin nodE API with a custom http.Client
@@ -67,14 +83,57 @@ func NewNodeClientWithHttpClient(rpcUrl string, chainId uint8, client *http.Clie
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse RPC url '%s': %w", rpcUrl, err)
 	}
+	abiCache, err := NewAbiCache()
+	if err != nil {
+		return nil, err
+	}
 	return &NodeClient{
-		client:  client,
-		baseUrl: baseUrl,
-		chainId: chainId,
-		headers: make(map[string]string),
+		client:   client,
+		baseUrl:  baseUrl,
+		chainId:  chainId,
+		headers:  make(map[string]string),
+		abiCache: abiCache,
 	}, nil
 }
 
+// WithContext returns a view of the client that applies ctx to every request it makes.
+//
+// The returned client shares the underlying http.Client and headers with the original, so it is cheap to create one
+// per inbound request.  Cancelling ctx aborts in-flight requests, polling loops, and concurrent fetches.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	info, err := client.WithContext(ctx).Account(address)
+func (rc *NodeClient) WithContext(ctx context.Context) *NodeClient {
+	if ctx == nil {
+		panic("nil context")
+	}
+	view := *rc
+	view.ctx = ctx
+	return &view
+}
+
+// Context returns the context applied to requests, defaulting to [context.Background]
+func (rc *NodeClient) Context() context.Context {
+	if rc.ctx == nil {
+		return context.Background()
+	}
+	return rc.ctx
+}
+
+// sleep waits for the given duration, returning early with the context's error if it is cancelled
+func (rc *NodeClient) sleep(duration time.Duration) error {
+	ctx := rc.Context()
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // SetTimeout adjusts the HTTP client timeout
 //
 //	client.SetTimeout(5 * time.Millisecond)
@@ -96,6 +155,13 @@ func (rc *NodeClient) RemoveHeader(key string) {
 	delete(rc.headers, key)
 }
 
+// SetRetryPolicy sets the policy for retrying failed requests, nil disables retries
+//
+//	client.SetRetryPolicy(DefaultRetryPolicy())
+func (rc *NodeClient) SetRetryPolicy(policy *RetryPolicy) {
+	rc.retryPolicy = policy
+}
+
 // Info gets general information about the blockchain
 func (rc *NodeClient) Info() (info NodeInfo, err error) {
 	info, err = Get[NodeInfo](rc, rc.baseUrl.String())
@@ -148,6 +214,24 @@ func (rc *NodeClient) AccountResource(address AccountAddress, resourceType strin
 	return data, nil
 }
 
+// AccountResourceBCS fetches a single resource of an account as the raw BCS of its Move struct
+// Optionally, a ledgerVersion can be given to get the account state at a specific ledger version
+// For decoding into a Go struct, See [AccountResourceAs]
+func (rc *NodeClient) AccountResourceBCS(address AccountAddress, resourceType string, ledgerVersion ...uint64) (data []byte, err error) {
+	au := rc.baseUrl.JoinPath("accounts", address.String(), "resource", resourceType)
+	if len(ledgerVersion) > 0 {
+		params := url.Values{}
+		params.Set("ledger_version", strconv.FormatUint(ledgerVersion[0], 10))
+		au.RawQuery = params.Encode()
+	}
+
+	data, err = rc.GetBCS(au.String())
+	if err != nil {
+		return nil, fmt.Errorf("get resource api err: %w", err)
+	}
+	return data, nil
+}
+
 // AccountResources fetches resources for an account into a JSON-like map[string]any in AccountResourceInfo.Data
 // Optionally, a ledgerVersion can be given to get the account state at a specific ledger version
 // For fetching raw Move structs as BCS, See #AccountResourcesBCS
@@ -202,14 +286,15 @@ func (rc *NodeClient) AccountModule(address AccountAddress, moduleName string, l
 }
 
 // EntryFunctionWithArgs generates an EntryFunction from on-chain Module ABI, and converts simple inputs to BCS encoded ones.
+// The ABI is cached, see [NodeClient.SetAbiCache].  Struct arguments can be maps or Go structs, see
+// [ConvertArgWithLayouts], with layouts from the ABIs of their modules.
 func (rc *NodeClient) EntryFunctionWithArgs(moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any) (*EntryFunction, error) {
-	// TODO: This should be cached / we should be able to take in an ABI
-	module, err := rc.AccountModule(moduleAddress, moduleName)
+	abi, err := rc.ModuleAbi(moduleAddress, moduleName)
 	if err != nil {
 		return nil, err
 	}
 
-	return EntryFunctionFromAbi(module.Abi, moduleAddress, moduleName, functionName, typeArgs, args)
+	return EntryFunctionFromAbi(abi, moduleAddress, moduleName, functionName, typeArgs, args, rc)
 }
 
 // TransactionByHash gets info on a transaction
@@ -218,10 +303,8 @@ func (rc *NodeClient) EntryFunctionWithArgs(moduleAddress AccountAddress, module
 //
 //	data, err := c.TransactionByHash("0xabcd")
 //	if err != nil {
-//		if httpErr, ok := err.(endless.HttpError) {
-//			if httpErr.StatusCode == 404 {
-//				// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
-//			}
+//		if errors.Is(err, endless.ErrTransactionNotFound) {
+//			// if we're sure this has been submitted, assume it is still pending elsewhere in the mempool
 //		}
 //	} else {
 //		if data["type"] == "pending_transaction" {
@@ -250,6 +333,9 @@ func (rc *NodeClient) TransactionByVersion(version uint64) (data *api.CommittedT
 
 // TransactionsByVersions gets info on some transaction by version numbers
 // The transaction will have been committed.  The response will not be of the type [[]api.PendingTransaction].
+//
+// Many versions are split into requests of [PageFetcher.PageSize] versions, fetched concurrently as configured by
+// [NodeClient.SetPageFetcher].  The transactions are returned in the order of the versions.
 func (rc *NodeClient) TransactionsByVersions(version []uint64, prune bool) (data []*api.CommittedTransaction, err error) {
 	restUrl := rc.baseUrl.JoinPath("transactions/by_version")
 	params := url.Values{}
@@ -258,9 +344,13 @@ func (rc *NodeClient) TransactionsByVersions(version []uint64, prune bool) (data
 	}
 	restUrl.RawQuery = params.Encode()
 
-	jsonBytes, _ := json.Marshal(version)
-	bodyReader := bytes.NewReader(jsonBytes)
-	data, err = PostAccept[[]*api.CommittedTransaction](rc, restUrl.String(), ContentAcceptType, ContentTypeApplicationJson, bodyReader)
+	data, err = FetchPages(rc.Context(), rc.fetcher(), 0, uint64(len(version)), func(ctx context.Context, start uint64, limit uint64) ([]*api.CommittedTransaction, error) {
+		jsonBytes, err := json.Marshal(version[start : start+limit])
+		if err != nil {
+			return nil, err
+		}
+		return post[[]*api.CommittedTransaction](rc.WithContext(ctx), restUrl.String(), ContentAcceptType, ContentTypeApplicationJson, bytes.NewReader(jsonBytes), requestKindRead)
+	})
 	if err != nil {
 		return data, fmt.Errorf("get transaction api err: %w", err)
 	}
@@ -333,115 +423,17 @@ func (rc *NodeClient) getBlockCommon(restUrl *url.URL, withTransactions bool) (b
 	return
 }
 
-// WaitForTransaction does a long-GET for one transaction and wait for it to complete.
-// Initially poll at 10 Hz for up to 1 second if node replies with 404 (wait for txn to propagate).
-//
-// Optional arguments:
-//   - PollPeriod: time.Duration, how often to poll for the transaction. Default 100ms.
-//   - PollTimeout: time.Duration, how long to wait for the transaction. Default 10s.
-func (rc *NodeClient) WaitForTransaction(txnHash string, options ...any) (data *api.UserTransaction, err error) {
-	return rc.PollForTransaction(txnHash, options...)
-}
-
-// PollPeriod is an option to PollForTransactions
-type PollPeriod time.Duration
-
-// PollTimeout is an option to PollForTransactions
-type PollTimeout time.Duration
-
-func getTransactionPollOptions(defaultPeriod, defaultTimeout time.Duration, options ...any) (period time.Duration, timeout time.Duration, err error) {
-	period = defaultPeriod
-	timeout = defaultTimeout
-	for i, arg := range options {
-		switch value := arg.(type) {
-		case PollPeriod:
-			period = time.Duration(value)
-		case PollTimeout:
-			timeout = time.Duration(value)
-		default:
-			err = fmt.Errorf("PollForTransactions arg %d bad type %T", i+1, arg)
-			return
-		}
-	}
-	return
-}
-
-// PollForTransaction waits up to 10 seconds for a transaction to be done, polling at 10Hz
-// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values.
-// Not just a degenerate case of PollForTransactions, it may return additional information for the single transaction polled.
-func (rc *NodeClient) PollForTransaction(hash string, options ...any) (*api.UserTransaction, error) {
-	period, timeout, err := getTransactionPollOptions(100*time.Millisecond, 10*time.Second, options...)
-	if err != nil {
-		return nil, err
-	}
-	start := time.Now()
-	deadline := start.Add(timeout)
-	for {
-		if time.Now().After(deadline) {
-			return nil, errors.New("PollForTransaction timeout")
-		}
-		time.Sleep(period)
-		txn, err := rc.TransactionByHash(hash)
-		if err == nil {
-			if txn.Type == api.TransactionVariantPending {
-				// not done yet!
-			} else if txn.Type == api.TransactionVariantUser {
-				// done!
-				slog.Debug("txn done", "hash", hash)
-				return txn.UserTransaction()
-			}
-		}
-	}
-}
-
-// PollForTransactions waits up to 10 seconds for transactions to be done, polling at 10Hz
-// Accepts options PollPeriod and PollTimeout which should wrap time.Duration values.
-func (rc *NodeClient) PollForTransactions(txnHashes []string, options ...any) error {
-	period, timeout, err := getTransactionPollOptions(100*time.Millisecond, 10*time.Second, options...)
-	if err != nil {
-		return err
-	}
-	hashSet := make(map[string]bool, len(txnHashes))
-	for _, hash := range txnHashes {
-		hashSet[hash] = true
-	}
-	start := time.Now()
-	deadline := start.Add(timeout)
-	for len(hashSet) > 0 {
-		if time.Now().After(deadline) {
-			return errors.New("PollForTransactions timeout")
-		}
-		time.Sleep(period)
-		for _, hash := range txnHashes {
-			if !hashSet[hash] {
-				// already done
-				continue
-			}
-			txn, err := rc.TransactionByHash(hash)
-			if err == nil {
-				if txn.Type == api.TransactionVariantPending {
-					// not done yet!
-				} else if txn.Type == api.TransactionVariantUser {
-					// done!
-					delete(hashSet, hash)
-					slog.Debug("txn done", "hash", hash)
-				}
-			}
-		}
-	}
-	return nil
-}
-
 // Transactions Get recent transactions.
 //
 // Arguments:
 //   - start is a version number. Nil for most recent transactions.
 //   - limit is a number of transactions to return. 'about a hundred' by default.
+//
+// Large ranges are fetched concurrently as configured by [NodeClient.SetPageFetcher].
 func (rc *NodeClient) Transactions(start *uint64, limit *uint64) (data []*api.CommittedTransaction, err error) {
-	return rc.handleTransactions(start, limit, func(txns *[]*api.CommittedTransaction) uint64 {
-		txn := (*txns)[len(*txns)-1]
+	return rc.handleTransactions(start, limit, func(txn *api.CommittedTransaction) uint64 {
 		return txn.Version()
-	}, func(start *uint64, limit *uint64) ([]*api.CommittedTransaction, error) {
+	}, func(rc *NodeClient, start *uint64, limit *uint64) ([]*api.CommittedTransaction, error) {
 		return rc.transactionsInner(start, limit)
 	})
 }
@@ -449,116 +441,68 @@ func (rc *NodeClient) Transactions(start *uint64, limit *uint64) (data []*api.Co
 // AccountTransactions Get recent transactions for an account
 //
 // Arguments:
-//   - start is a version number. Nil for most recent transactions.
+//   - start is a sequence number. Nil for most recent transactions.
 //   - limit is a number of transactions to return. 'about a hundred' by default.
+//
+// Large ranges are fetched concurrently as configured by [NodeClient.SetPageFetcher].
 func (rc *NodeClient) AccountTransactions(account AccountAddress, start *uint64, limit *uint64) (data []*api.CommittedTransaction, err error) {
-	return rc.handleTransactions(start, limit, func(txns *[]*api.CommittedTransaction) uint64 {
+	return rc.handleTransactions(start, limit, func(txn *api.CommittedTransaction) uint64 {
 		// It will always be a UserTransaction, no other type will come from the API
-		userTxn, _ := ((*txns)[0]).UserTransaction()
-		return userTxn.SequenceNumber - 1
-	}, func(start *uint64, limit *uint64) ([]*api.CommittedTransaction, error) {
+		userTxn, _ := txn.UserTransaction()
+		return userTxn.SequenceNumber
+	}, func(rc *NodeClient, start *uint64, limit *uint64) ([]*api.CommittedTransaction, error) {
 		return rc.accountTransactionsInner(account, start, limit)
 	})
 }
 
 // handleTransactions is a helper function for fetching transactions
 //
-// It will fetch the transactions from the node in a single request if possible, otherwise it will fetch them concurrently.
+// It will fetch the transactions from the node in a single request if possible, otherwise it will fetch them
+// concurrently.  position gives the version or sequence number of a transaction, which the pages are indexed by.
 func (rc *NodeClient) handleTransactions(
 	start *uint64,
 	limit *uint64,
-	getNext func(txns *[]*api.CommittedTransaction) uint64,
-	getTxns func(start *uint64, limit *uint64) ([]*api.CommittedTransaction, error),
+	position func(txn *api.CommittedTransaction) uint64,
+	getTxns func(rc *NodeClient, start *uint64, limit *uint64) ([]*api.CommittedTransaction, error),
 ) (data []*api.CommittedTransaction, err error) {
 	// Can only pull everything in parallel if a start and a limit is handled
 	if start != nil && limit != nil {
 		return rc.transactionsConcurrent(*start, *limit, getTxns)
 	} else if limit != nil {
-		// If we don't know the start, we can only pull one page first, then handle the rest
-		// Note that, this actually pulls the last page first, then goes backwards
+		// If we don't know the start, we can only pull the latest page first, then pull the older ones before it
 		actualLimit := *limit
-		txns, err := getTxns(nil, limit)
+		txns, err := getTxns(rc, nil, limit)
 		if err != nil {
 			return nil, err
 		}
 
 		// If we have enough transactions, return otherwise, pull the rest
 		numTxns := uint64(len(txns))
-		if numTxns >= actualLimit {
+		if numTxns >= actualLimit || numTxns == 0 {
 			return txns, nil
-		} else {
-			newStart := getNext(&txns)
-			newLength := actualLimit - numTxns
-			extra, err := rc.transactionsConcurrent(newStart, newLength, getTxns)
-			if err != nil {
-				return nil, err
-			}
-
-			return append(extra, txns...), nil
 		}
+		first := position(txns[0])
+		newLength := min(actualLimit-numTxns, first)
+		extra, err := rc.transactionsConcurrent(first-newLength, newLength, getTxns)
+		if err != nil {
+			return nil, err
+		}
+		return append(extra, txns...), nil
 	} else {
 		// If we know the start, just pull one page
-		return getTxns(start, nil)
+		return getTxns(rc, start, nil)
 	}
 }
 
-// transactionsConcurrent fetches the transactions from the node concurrently
-//
-// It will fetch the transactions concurrently if the limit is greater than the page size, otherwise it will fetch them in a single request.
+// transactionsConcurrent fetches the transactions from the node with the client's [PageFetcher]
 func (rc *NodeClient) transactionsConcurrent(
 	start uint64,
 	limit uint64,
-	getTxns func(start *uint64, limit *uint64) ([]*api.CommittedTransaction, error),
+	getTxns func(rc *NodeClient, start *uint64, limit *uint64) ([]*api.CommittedTransaction, error),
 ) (data []*api.CommittedTransaction, err error) {
-	const transactionsPageSize = 100
-	// If we know both, we can fetch all concurrently
-	type Pair struct {
-		start uint64 // inclusive
-		end   uint64 // exclusive
-	}
-
-	// If the limit is  greater than the page size, we need to fetch concurrently, otherwise not
-	if limit > transactionsPageSize {
-		numChannels := limit / transactionsPageSize
-		if limit%transactionsPageSize > 0 {
-			numChannels++
-		}
-
-		// Concurrently fetch all the transactions by the page size
-		channels := make([]chan ConcResponse[[]*api.CommittedTransaction], numChannels)
-		for i := uint64(0); i*transactionsPageSize < limit; i += 1 {
-			channels[i] = make(chan ConcResponse[[]*api.CommittedTransaction], 1)
-			st := start + i*100 // TODO: allow page size to be configured
-			li := min(transactionsPageSize, limit-i*transactionsPageSize)
-			go fetch(func() ([]*api.CommittedTransaction, error) {
-				return rc.transactionsConcurrent(st, li, getTxns)
-			}, channels[i])
-		}
-
-		// Collect all the responses
-		responses := make([]*api.CommittedTransaction, 0)
-		for i, ch := range channels {
-			response := <-ch
-			if response.Err != nil {
-				return nil, err
-			}
-			responses = append(responses, response.Result...)
-			close(channels[i])
-		}
-
-		// Sort to keep ordering
-		sort.Slice(responses, func(i, j int) bool {
-			return responses[i].Version() < responses[j].Version()
-		})
-		return responses, nil
-	} else {
-		response, err := getTxns(&start, &limit)
-		if err != nil {
-			return nil, err
-		} else {
-			return response, nil
-		}
-	}
+	return FetchPages(rc.Context(), rc.fetcher(), start, limit, func(ctx context.Context, start uint64, limit uint64) ([]*api.CommittedTransaction, error) {
+		return getTxns(rc.WithContext(ctx), &start, &limit)
+	})
 }
 
 // transactionsInner fetches the transactions from the node in a single request
@@ -608,11 +552,14 @@ func (rc *NodeClient) SubmitTransaction(signedTxn *SignedTransaction) (data *api
 	if err != nil {
 		return
 	}
-	bodyReader := bytes.NewReader(sblob)
 	au := rc.baseUrl.JoinPath("transactions")
 
-	data, err = Post[*api.SubmitTransactionResponse](rc, au.String(), ContentTypeEndlessSignedTxnBcs, bodyReader)
+	data, resent, err := submit[*api.SubmitTransactionResponse](rc, au.String(), sblob)
 	if err != nil {
+		if resent && isAlreadyInMempool(err) {
+			// An earlier attempt made it to the mempool, so the submission has succeeded
+			return pendingTransactionFromSigned(signedTxn)
+		}
 		return nil, fmt.Errorf("submit transaction api err: %w", err)
 	}
 	return data, nil
@@ -623,17 +570,22 @@ func (rc *NodeClient) SubmitTransaction(signedTxn *SignedTransaction) (data *api
 // It will return the responses in the same order as the input transactions that failed.  If the response is empty, then
 // all transactions succeeded.
 func (rc *NodeClient) BatchSubmitTransaction(signedTxns []*SignedTransaction) (response *api.BatchSubmitTransactionResponse, err error) {
+	response, _, err = rc.batchSubmitTransaction(signedTxns)
+	return response, err
+}
+
+// batchSubmitTransaction is [NodeClient.BatchSubmitTransaction], also telling whether the batch was sent more than once
+func (rc *NodeClient) batchSubmitTransaction(signedTxns []*SignedTransaction) (*api.BatchSubmitTransactionResponse, bool, error) {
 	sblob, err := bcs.SerializeSequenceOnly(signedTxns)
 	if err != nil {
-		return
+		return nil, false, err
 	}
-	bodyReader := bytes.NewReader(sblob)
 	au := rc.baseUrl.JoinPath("transactions/batch")
-	response, err = Post[*api.BatchSubmitTransactionResponse](rc, au.String(), ContentTypeEndlessSignedTxnBcs, bodyReader)
+	response, resent, err := submit[*api.BatchSubmitTransactionResponse](rc, au.String(), sblob)
 	if err != nil {
-		return nil, fmt.Errorf("submit transaction api err: %w", err)
+		return nil, resent, fmt.Errorf("submit transaction api err: %w", err)
 	}
-	return response, nil
+	return response, resent, nil
 }
 
 // EstimateGasUnitPrice estimates the gas unit price for a transaction
@@ -692,7 +644,7 @@ func (rc *NodeClient) SimulateTransaction(rawTxn *RawTransaction, sender Transac
 		au.RawQuery = params.Encode()
 	}
 
-	data, err = Post[[]*api.UserTransaction](rc, au.String(), ContentTypeEndlessSignedTxnBcs, bodyReader)
+	data, err = post[[]*api.UserTransaction](rc, au.String(), "", ContentTypeEndlessSignedTxnBcs, bodyReader, requestKindRead)
 	if err != nil {
 		return nil, fmt.Errorf("simulate transaction api err: %w", err)
 	}
@@ -946,23 +898,19 @@ func (rc *NodeClient) buildTransactionInner(
 	}
 
 	// TODO: optionally simulate for max gas
-	// Wait on the errors
-	if chainIdErrChannel != nil {
-		chainIdErr := <-chainIdErrChannel
-		if chainIdErr != nil {
-			return nil, chainIdErr
-		}
-	}
-	if accountErrChannel != nil {
-		accountErr := <-accountErrChannel
-		if accountErr != nil {
-			return nil, accountErr
+	// Wait on the errors, the channels are buffered so an early return on cancellation will not leak the fetches
+	ctx := rc.Context()
+	for _, errChannel := range []chan error{chainIdErrChannel, accountErrChannel, gasPriceErrChannel} {
+		if errChannel == nil {
+			continue
 		}
-	}
-	if gasPriceErrChannel != nil {
-		gasPriceErr := <-gasPriceErrChannel
-		if gasPriceErr != nil {
-			return nil, gasPriceErr
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case innerErr := <-errChannel:
+			if innerErr != nil {
+				return nil, innerErr
+			}
 		}
 	}
 
@@ -1016,7 +964,7 @@ func (rc *NodeClient) View(payload *ViewPayload, ledgerVersion ...uint64) (data
 		params.Set("ledger_version", strconv.FormatUint(ledgerVersion[0], 10))
 		au.RawQuery = params.Encode()
 	}
-	data, err = Post[[]any](rc, au.String(), ContentTypeEndlessViewFunctionBcs, bodyReader)
+	data, err = post[[]any](rc, au.String(), "", ContentTypeEndlessViewFunctionBcs, bodyReader, requestKindRead)
 	if err != nil {
 		return nil, fmt.Errorf("view function api err: %w", err)
 	}
@@ -1174,118 +1122,202 @@ func (rc *NodeClient) Faucet(account Account, options ...any) error {
 
 // Get makes a GET request to the endpoint and parses the response into the given type with JSON
 func Get[T any](rc *NodeClient, getUrl string) (out T, err error) {
-	req, err := http.NewRequest("GET", getUrl, nil)
-	if err != nil {
-		return out, err
-	}
-	req.Header.Set(ClientHeader, ClientHeaderValue)
-
-	// Set all preset headers
-	for key, value := range rc.headers {
-		req.Header.Set(key, value)
-	}
-
-	response, err := rc.client.Do(req)
-	if err != nil {
-		err = fmt.Errorf("GET %s, %w", getUrl, err)
-		return out, err
-	}
+	out, _, err = getWithHeader[T](rc, getUrl)
+	return out, err
+}
 
-	if response.StatusCode >= 400 {
-		err = NewHttpError(response)
-		return out, err
-	}
-	blob, err := io.ReadAll(response.Body)
+// getWithHeader is [Get], but also returns the response headers e.g. for pagination cursors
+func getWithHeader[T any](rc *NodeClient, getUrl string) (out T, header http.Header, err error) {
+	response, err := rc.do(&nodeRequest{
+		method: http.MethodGet,
+		url:    getUrl,
+		kind:   requestKindRead,
+	})
 	if err != nil {
-		return out, fmt.Errorf("error getting response data, %w", err)
+		return out, nil, err
 	}
-	_ = response.Body.Close()
-	err = json.Unmarshal(blob, &out)
+	err = json.Unmarshal(response.body, &out)
 	if err != nil {
-		return out, err
+		return out, nil, err
 	}
-	return out, nil
+	return out, response.header, nil
 }
 
 // GetBCS makes a GET request to the endpoint and parses the response into the given type with BCS
 func (rc *NodeClient) GetBCS(getUrl string) (out []byte, err error) {
-	req, err := http.NewRequest("GET", getUrl, nil)
+	response, err := rc.do(&nodeRequest{
+		method: http.MethodGet,
+		url:    getUrl,
+		accept: "application/x-bcs",
+		kind:   requestKindRead,
+	})
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/x-bcs")
-	req.Header.Set(ClientHeader, ClientHeaderValue)
-
-	// Set all preset headers
-	for key, value := range rc.headers {
-		req.Header.Set(key, value)
-	}
-
-	response, err := rc.client.Do(req)
-	if err != nil {
-		err = fmt.Errorf("GET %s, %w", getUrl, err)
-		return
-	}
-	if response.StatusCode >= 400 {
-		err = NewHttpError(response)
-		return
-	}
-	blob, err := io.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("error getting response data, %w", err)
-		return
-	}
-	_ = response.Body.Close()
-	return blob, nil
+	return response.body, nil
 }
 
 // Post makes a POST request to the endpoint with the given body and parses the response into the given type with JSON
+//
+// The request is treated as non-idempotent, and will not be retried by the [RetryPolicy].
 func Post[T any](rc *NodeClient, postUrl string, contentType string, body io.Reader) (data T, err error) {
-	if body == nil {
-		body = http.NoBody
+	return post[T](rc, postUrl, "", contentType, body, requestKindWrite)
+}
+
+// PostAccept makes a POST request to the endpoint with the given body and Accept header, and parses the response into
+// the given type with JSON
+//
+// The request is treated as non-idempotent, and will not be retried by the [RetryPolicy].
+func PostAccept[T any](rc *NodeClient, postUrl string, AcceptType string, contentType string, body io.Reader) (data T, err error) {
+	return post[T](rc, postUrl, AcceptType, contentType, body, requestKindWrite)
+}
+
+// post is the shared implementation of [Post] and [PostAccept], with the kind deciding how the request is retried
+func post[T any](rc *NodeClient, postUrl string, acceptType string, contentType string, body io.Reader, kind requestKind) (data T, err error) {
+	var payload []byte
+	if body != nil {
+		// Buffer the body, so it can be replayed on retry
+		payload, err = io.ReadAll(body)
+		if err != nil {
+			return data, fmt.Errorf("error reading request body, %w", err)
+		}
 	}
-	req, err := http.NewRequest("POST", postUrl, body)
+	response, err := rc.do(&nodeRequest{
+		method:      http.MethodPost,
+		url:         postUrl,
+		accept:      acceptType,
+		contentType: contentType,
+		body:        payload,
+		kind:        kind,
+	})
 	if err != nil {
 		return data, err
 	}
-	req.Header.Set("Content-Type", contentType)
-	req.Header.Set(ClientHeader, ClientHeaderValue)
-
-	// Set all preset headers
-	for key, value := range rc.headers {
-		req.Header.Set(key, value)
-	}
+	err = json.Unmarshal(response.body, &data)
+	return data, err
+}
 
-	response, err := rc.client.Do(req)
+// submit posts signed transactions, also telling whether it was sent more than once, in which case an earlier attempt
+// may have been accepted
+func submit[T any](rc *NodeClient, postUrl string, body []byte) (data T, resent bool, err error) {
+	request := &nodeRequest{
+		method:      http.MethodPost,
+		url:         postUrl,
+		contentType: ContentTypeEndlessSignedTxnBcs,
+		body:        body,
+		kind:        requestKindSubmission,
+	}
+	response, err := rc.do(request)
+	resent = request.sent > 1
 	if err != nil {
-		err = fmt.Errorf("POST %s, %w", postUrl, err)
-		return data, err
+		return data, resent, err
 	}
-	if response.StatusCode >= 400 {
-		err = NewHttpError(response)
-		return data, err
+	err = json.Unmarshal(response.body, &data)
+	return data, resent, err
+}
+
+// requestKind classifies a request by whether it is safe to send more than once
+type requestKind uint8
+
+const (
+	requestKindRead       requestKind = iota // requestKindRead has no side effects, and is always safe to retry
+	requestKindWrite                         // requestKindWrite may have side effects, and is never retried
+	requestKindSubmission                    // requestKindSubmission submits transactions, and is retried only if allowed by the policy
+)
+
+// nodeRequest is a single HTTP call against the node.  It is kept as data rather than a [http.Request], so it can be
+// replayed on retry.
+type nodeRequest struct {
+	method      string      // HTTP method e.g. "GET"
+	url         string      // Full URL of the request
+	accept      string      // Accept header, omitted if empty
+	contentType string      // Content-Type header, omitted if empty
+	body        []byte      // Body of the request, nil for no body
+	kind        requestKind // Whether the request can be retried
+	operation   string      // Name reported to [Instrumentation], derived from the method and url if empty
+	sent        int         // Times the request was sent, across retries and failovers
+}
+
+// nodeResponse is the successful result of a [nodeRequest]
+type nodeResponse struct {
+	body   []byte      // Body of the response
+	header http.Header // Headers of the response
+}
+
+// do sends the request, retrying according to the client's [RetryPolicy]
+func (rc *NodeClient) do(request *nodeRequest) (response *nodeResponse, err error) {
+	if request.operation == "" {
+		request.operation = operationName(request.method, rc.baseUrl, request.url)
 	}
-	blob, err := io.ReadAll(response.Body)
-	if err != nil {
-		err = fmt.Errorf("error getting response data, %w", err)
-		return data, err
+	for attempt := 1; ; attempt++ {
+		response, err = rc.send(request)
+		if err == nil {
+			return response, nil
+		}
+		if !rc.retryPolicy.shouldRetry(request.kind, attempt, err) {
+			return nil, err
+		}
+		delay := rc.retryPolicy.backoff(attempt, err)
+		slog.Debug("retrying node request", "method", request.method, "url", request.url, "attempt", attempt, "delay", delay, "err", err)
+		rc.instrument().Retry(rc.Context(), request.operation, attempt, delay, err)
+		if sleepErr := rc.sleep(delay); sleepErr != nil {
+			// Cancelled while waiting, keeping why it was retrying
+			return nil, errors.Join(sleepErr, err)
+		}
 	}
-	_ = response.Body.Close()
+}
 
-	err = json.Unmarshal(blob, &data)
-	return data, err
+// send sends the request a single time, failing over between endpoints if the client has more than one
+func (rc *NodeClient) send(request *nodeRequest) (*nodeResponse, error) {
+	if rc.endpoints != nil {
+		return rc.endpoints.send(rc, request)
+	}
+	return rc.sendTo(request.url, request)
 }
 
-func PostAccept[T any](rc *NodeClient, postUrl string, AcceptType string, contentType string, body io.Reader) (data T, err error) {
-	if body == nil {
-		body = http.NoBody
+// sendTo sends the request a single time to the given URL, reporting it to the client's [Instrumentation]
+func (rc *NodeClient) sendTo(requestUrl string, request *nodeRequest) (response *nodeResponse, err error) {
+	operation := request.operation
+	if operation == "" {
+		operation = operationName(request.method, rc.baseUrl, requestUrl)
+	}
+	endpoint := requestUrl
+	if parsed, parseErr := url.Parse(requestUrl); parseErr == nil {
+		endpoint = parsed.Scheme + "://" + parsed.Host
 	}
-	req, err := http.NewRequest("POST", postUrl, body)
+	ctx, span := rc.instrument().StartSpan(rc.Context(), operation, endpoint)
+	start := time.Now()
+	result := SpanResult{RequestBytes: len(request.body)}
+	defer func() {
+		result.Duration = time.Since(start)
+		result.Err = err
+		var header http.Header
+		var httpErr *HttpError
+		if response != nil {
+			header = response.header
+			result.ResponseBytes = len(response.body)
+		} else if errors.As(err, &httpErr) {
+			header = httpErr.Header
+			result.ResponseBytes = len(httpErr.Body)
+		}
+		result.LedgerVersion, _ = ledgerVersionFromHeader(header)
+		span.End(result)
+	}()
+
+	body := io.Reader(http.NoBody)
+	if request.body != nil {
+		body = bytes.NewReader(request.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, request.method, requestUrl, body)
 	if err != nil {
-		return data, err
+		return nil, err
+	}
+	if request.accept != "" {
+		req.Header.Set("Accept", request.accept)
+	}
+	if request.contentType != "" {
+		req.Header.Set("Content-Type", request.contentType)
 	}
-	req.Header.Set("Accept", AcceptType)
-	req.Header.Set("Content-Type", contentType)
 	req.Header.Set(ClientHeader, ClientHeaderValue)
 
 	// Set all preset headers
@@ -1293,24 +1325,21 @@ func PostAccept[T any](rc *NodeClient, postUrl string, AcceptType string, conten
 		req.Header.Set(key, value)
 	}
 
-	response, err := rc.client.Do(req)
+	request.sent++
+	httpResponse, err := rc.roundTrip(req)
 	if err != nil {
-		err = fmt.Errorf("POST %s, %w", postUrl, err)
-		return data, err
+		return nil, fmt.Errorf("%s %s, %w", request.method, requestUrl, err)
 	}
-	if response.StatusCode >= 400 {
-		err = NewHttpError(response)
-		return data, err
+	result.StatusCode = httpResponse.StatusCode
+	if httpResponse.StatusCode >= 400 {
+		return nil, NewHttpError(httpResponse)
 	}
-	blob, err := io.ReadAll(response.Body)
+	blob, err := io.ReadAll(httpResponse.Body)
+	_ = httpResponse.Body.Close()
 	if err != nil {
-		err = fmt.Errorf("error getting response data, %w", err)
-		return data, err
+		return nil, fmt.Errorf("error getting response data, %w", err)
 	}
-	_ = response.Body.Close()
-
-	err = json.Unmarshal(blob, &data)
-	return data, err
+	return &nodeResponse{body: blob, header: httpResponse.Header}, nil
 }
 
 // ConcResponse is a concurrent response wrapper as a return type for all APIs.  It is meant to specifically be used in channels.
@@ -1318,14 +1347,3 @@ type ConcResponse[T any] struct {
 	Result T
 	Err    error
 }
-
-// fetch is a helper function to fetch data concurrently with a channel and support error and value together
-// TODO: Support multiple output channels?
-func fetch[T any](inner func() (T, error), result chan ConcResponse[T]) {
-	response, err := inner()
-	if err != nil {
-		result <- ConcResponse[T]{Err: err}
-	} else {
-		result <- ConcResponse[T]{Result: response}
-	}
-}
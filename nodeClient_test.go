@@ -0,0 +1,57 @@
+package endless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestNodeClient creates a NodeClient pointed at a local test server
+func newTestNodeClient(t *testing.T, handler http.Handler) *NodeClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewNodeClientWithHttpClient(server.URL+"/v1", 4, server.Client())
+	assert.NoError(t, err)
+	return client
+}
+
+func TestNodeClient_WithContextCancelsRequest(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.WithContext(ctx).Info()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The original client is unaffected by the view
+	assert.Equal(t, context.Background(), client.Context())
+}
+
+func TestNodeClient_WithContextStopsPolling(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"type":"pending_transaction","hash":"0x1234","sender":"0x1","sequence_number":"0","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"1"}`))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	_, err := client.WithContext(ctx).PollForTransaction("0x1234", PollPeriod(10*time.Millisecond), PollTimeout(10*time.Second))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
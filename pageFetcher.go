@@ -0,0 +1,168 @@
+package endless
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// PageFetcher configures how a range of items, such as transactions by version, is split into pages and fetched
+// concurrently.  Set it with [NodeClient.SetPageFetcher], it is used by [NodeClient.Transactions],
+// [NodeClient.AccountTransactions], and [NodeClient.TransactionsByVersions].
+//
+//	fetcher := DefaultPageFetcher()
+//	fetcher.Workers = 16
+//	client.SetPageFetcher(fetcher)
+type PageFetcher struct {
+	PageSize    uint64        // PageSize is the number of items per request, 100 if not set
+	Workers     int           // Workers is the maximum number of requests in flight, 1 if not set
+	PageRetries int           // PageRetries is the number of times a failed page is retried, on top of the client's RetryPolicy
+	RetryDelay  time.Duration // RetryDelay is the delay before retrying a failed page, growing linearly per retry
+}
+
+// DefaultPageFetcher fetches 100 items per page, with up to 8 pages in flight, retrying each page twice
+func DefaultPageFetcher() *PageFetcher {
+	return &PageFetcher{
+		PageSize:    100,
+		Workers:     8,
+		PageRetries: 2,
+		RetryDelay:  100 * time.Millisecond,
+	}
+}
+
+// SetPageFetcher sets how ranges of transactions are fetched, nil restores [DefaultPageFetcher]
+func (rc *NodeClient) SetPageFetcher(fetcher *PageFetcher) {
+	rc.pageFetcher = fetcher
+}
+
+// fetcher returns the page fetcher, never nil
+func (rc *NodeClient) fetcher() *PageFetcher {
+	if rc.pageFetcher == nil {
+		return DefaultPageFetcher()
+	}
+	return rc.pageFetcher
+}
+
+// FetchPages fetches count items starting at start, in pages of [PageFetcher.PageSize] across at most
+// [PageFetcher.Workers] goroutines.  The pages are returned in order.  If any page fails after its retries, the
+// remaining pages are cancelled, and the first error is returned.
+//
+// fetchPage is given a context that is cancelled when another page fails, and should stop early if it is.
+//
+//	txns, err := FetchPages(ctx, DefaultPageFetcher(), 1000, 500, func(ctx context.Context, start, limit uint64) ([]*api.CommittedTransaction, error) {
+//		return client.WithContext(ctx).Transactions(&start, &limit)
+//	})
+func FetchPages[T any](
+	ctx context.Context,
+	fetcher *PageFetcher,
+	start uint64,
+	count uint64,
+	fetchPage func(ctx context.Context, start uint64, limit uint64) ([]T, error),
+) ([]T, error) {
+	if fetcher == nil {
+		fetcher = DefaultPageFetcher()
+	}
+	pageSize := fetcher.PageSize
+	if pageSize == 0 {
+		pageSize = 100
+	}
+	if count == 0 {
+		return []T{}, nil
+	}
+	numPages := (count + pageSize - 1) / pageSize
+	workers := uint64(max(fetcher.Workers, 1))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pages := make([][]T, numPages)
+	indexes := make(chan uint64)
+	var firstErr error
+	var errOnce sync.Once
+	wg := sync.WaitGroup{}
+	for range min(workers, numPages) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				pageStart := start + i*pageSize
+				limit := min(pageSize, count-i*pageSize)
+				items, err := fetchPageWithRetries(ctx, fetcher, pageStart, limit, fetchPage)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+				pages[i] = items
+			}
+		}()
+	}
+
+	// Hand out pages in order, until done or something fails
+	func() {
+		defer close(indexes)
+		for i := range numPages {
+			select {
+			case <-ctx.Done():
+				return
+			case indexes <- i:
+			}
+		}
+	}()
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		// The caller's context was cancelled
+		return nil, err
+	}
+	out := make([]T, 0, count)
+	for _, page := range pages {
+		out = append(out, page...)
+	}
+	return out, nil
+}
+
+// fetchPageWithRetries fetches a single page, retrying errors that may be transient
+func fetchPageWithRetries[T any](
+	ctx context.Context,
+	fetcher *PageFetcher,
+	start uint64,
+	limit uint64,
+	fetchPage func(ctx context.Context, start uint64, limit uint64) ([]T, error),
+) ([]T, error) {
+	for attempt := 0; ; attempt++ {
+		items, err := fetchPage(ctx, start, limit)
+		if err == nil {
+			return items, nil
+		}
+		if attempt >= fetcher.PageRetries || !isRetryablePageError(ctx, err) {
+			return nil, err
+		}
+		timer := time.NewTimer(fetcher.RetryDelay * time.Duration(attempt+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryablePageError is false for errors that will happen again, such as a bad request
+func isRetryablePageError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	return true
+}
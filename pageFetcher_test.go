@@ -0,0 +1,133 @@
+package endless
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchPages_OrderAndWorkers(t *testing.T) {
+	var inflight, maxInflight atomic.Int32
+	fetcher := &PageFetcher{PageSize: 10, Workers: 3}
+	items, err := FetchPages(context.Background(), fetcher, 5, 95, func(ctx context.Context, start uint64, limit uint64) ([]uint64, error) {
+		current := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			seen := maxInflight.Load()
+			if current <= seen || maxInflight.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		// Later pages finish first, to check the order is kept
+		time.Sleep(time.Duration(100-start) * 50 * time.Microsecond)
+		page := make([]uint64, 0, limit)
+		for i := start; i < start+limit; i++ {
+			page = append(page, i)
+		}
+		return page, nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, items, 95)
+	for i, item := range items {
+		assert.Equal(t, uint64(5+i), item)
+	}
+	assert.LessOrEqual(t, maxInflight.Load(), int32(3))
+}
+
+func TestFetchPages_FirstError(t *testing.T) {
+	pageErr := errors.New("page 3 failed")
+	var calls atomic.Int32
+	fetcher := &PageFetcher{PageSize: 1, Workers: 2}
+	_, err := FetchPages(context.Background(), fetcher, 0, 1000, func(ctx context.Context, start uint64, limit uint64) ([]uint64, error) {
+		calls.Add(1)
+		if start == 3 {
+			return nil, pageErr
+		}
+		return []uint64{start}, nil
+	})
+	assert.ErrorIs(t, err, pageErr)
+	// The remaining pages were not fetched
+	assert.Less(t, calls.Load(), int32(100))
+}
+
+func TestFetchPages_Retries(t *testing.T) {
+	var calls atomic.Int32
+	fetcher := &PageFetcher{PageSize: 10, Workers: 1, PageRetries: 2, RetryDelay: time.Millisecond}
+	items, err := FetchPages(context.Background(), fetcher, 0, 10, func(ctx context.Context, start uint64, limit uint64) ([]uint64, error) {
+		if calls.Add(1) < 3 {
+			return nil, &HttpError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return []uint64{1}, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, items)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Client errors are not retried
+	calls.Store(0)
+	_, err = FetchPages(context.Background(), fetcher, 0, 10, func(ctx context.Context, start uint64, limit uint64) ([]uint64, error) {
+		calls.Add(1)
+		return nil, &HttpError{StatusCode: http.StatusBadRequest}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestNodeClient_TransactionsPageError(t *testing.T) {
+	var pages atomic.Int32
+	client := newTestLedger(t, 1000, &pages)
+	client.SetPageFetcher(&PageFetcher{PageSize: 100, Workers: 4})
+
+	start, limit := uint64(100), uint64(350)
+	txns, err := client.Transactions(&start, &limit)
+	assert.NoError(t, err)
+	assert.Len(t, txns, 350)
+	for i, txn := range txns {
+		assert.Equal(t, start+uint64(i), txn.Version())
+	}
+
+	// A failed page used to be dropped, returning no transactions and no error
+	start = 900
+	_, err = client.Transactions(&start, &limit)
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+
+	// The latest transactions are fetched backwards from the latest page
+	limit = 150
+	txns, err = client.Transactions(nil, &limit)
+	assert.NoError(t, err)
+	assert.Len(t, txns, 150)
+	assert.Equal(t, uint64(850), txns[0].Version())
+	assert.Equal(t, uint64(999), txns[149].Version())
+}
+
+func TestNodeClient_TransactionsByVersions(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		versions := make([]uint64, 0)
+		assert.NoError(t, json.Unmarshal(body, &versions))
+		txns := make([]string, 0, len(versions))
+		for _, version := range versions {
+			txns = append(txns, testUserTransactionJson(version, 0, true, VmStatusSuccess))
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(txns, ",") + "]"))
+	}))
+	client.SetPageFetcher(&PageFetcher{PageSize: 3, Workers: 2})
+
+	versions := []uint64{9, 3, 7, 1, 5, 2, 8}
+	txns, err := client.TransactionsByVersions(versions, false)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+	for i, txn := range txns {
+		assert.Equal(t, versions[i], txn.Version())
+	}
+}
@@ -3,6 +3,7 @@ package endless
 import (
 	//"log"
 	"fmt"
+	"sync"
 
 	"github.com/endless-labs/endless-go-sdk/bcs"
 	"github.com/endless-labs/endless-go-sdk/crypto"
@@ -11,22 +12,18 @@ import (
 
 //region RawTransaction
 
-var rawTransactionPrehash []byte
-
 const rawTransactionPrehashStr = "ENDLESS::RawTransaction"
 
+// rawTransactionPrehash is computed once, as transactions may be signed concurrently
+var rawTransactionPrehash = sync.OnceValue(func() []byte {
+	b32 := sha3.Sum256([]byte(rawTransactionPrehashStr))
+	return b32[:]
+})
+
 // RawTransactionPrehash Return the sha3-256 prehash for RawTransaction
 // Do not write to the []byte returned
 func RawTransactionPrehash() []byte {
-	// Cache the prehash
-	if rawTransactionPrehash == nil {
-		b32 := sha3.Sum256([]byte(rawTransactionPrehashStr))
-		out := make([]byte, len(b32))
-		copy(out, b32[:])
-		rawTransactionPrehash = out
-		return out
-	}
-	return rawTransactionPrehash
+	return rawTransactionPrehash()
 }
 
 type RawTransactionImpl interface {
@@ -131,22 +128,18 @@ func (txn *RawTransaction) Sign(signer crypto.Signer) (authenticator *crypto.Acc
 
 //region RawTransactionWithData
 
-var rawTransactionWithDataPrehash []byte
-
 const rawTransactionWithDataPrehashStr = "ENDLESS::RawTransactionWithData"
 
+// rawTransactionWithDataPrehash is computed once, as transactions may be signed concurrently
+var rawTransactionWithDataPrehash = sync.OnceValue(func() []byte {
+	b32 := sha3.Sum256([]byte(rawTransactionWithDataPrehashStr))
+	return b32[:]
+})
+
 // RawTransactionWithDataPrehash Return the sha3-256 prehash for RawTransactionWithData
 // Do not write to the []byte returned
 func RawTransactionWithDataPrehash() []byte {
-	// Cache the prehash
-	if rawTransactionWithDataPrehash == nil {
-		b32 := sha3.Sum256([]byte(rawTransactionWithDataPrehashStr))
-		out := make([]byte, len(b32))
-		copy(out, b32[:])
-		rawTransactionWithDataPrehash = out
-		return out
-	}
-	return rawTransactionWithDataPrehash
+	return rawTransactionWithDataPrehash()
 }
 
 type RawTransactionWithDataVariant uint32
@@ -0,0 +1,51 @@
+package endless
+
+import (
+	"fmt"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+)
+
+// ResourceClient reads a single account resource, it's implemented by [NodeClient] and [Client]
+type ResourceClient interface {
+	AccountResource(address AccountAddress, resourceType string, ledgerVersion ...uint64) (map[string]any, error)
+	AccountResourceBCS(address AccountAddress, resourceType string, ledgerVersion ...uint64) ([]byte, error)
+}
+
+// AccountResourceAs fetches a single account resource, and decodes it into a T.
+//
+// If *T implements [bcs.Unmarshaler], the resource is fetched as BCS and deserialized, which is exact and compact.
+// Otherwise, it's fetched as JSON and decoded with [UnmarshalMoveJson], so fields can be plain Go types, such as a
+// uint64 for a u64 that the node sends as a string.
+//
+//	account, err := AccountResourceAs[AccountData](client, address, ResourceTypeAccount)
+//
+//	type Registry struct {
+//		Owner AccountAddress `json:"owner"`
+//		Count uint64         `json:"count"`
+//	}
+//	registry, err := AccountResourceAs[Registry](client, address, "0xcafe::registry::Registry")
+//
+// Optionally, a ledgerVersion can be given to get the resource at a specific ledger version
+func AccountResourceAs[T any](client ResourceClient, address AccountAddress, resourceType string, ledgerVersion ...uint64) (*T, error) {
+	out := new(T)
+	if unmarshaler, ok := any(out).(bcs.Unmarshaler); ok {
+		blob, err := client.AccountResourceBCS(address, resourceType, ledgerVersion...)
+		if err != nil {
+			return nil, err
+		}
+		if err = bcs.Deserialize(unmarshaler, blob); err != nil {
+			return nil, fmt.Errorf("failed to deserialize %s: %w", resourceType, err)
+		}
+		return out, nil
+	}
+
+	resource, err := client.AccountResource(address, resourceType, ledgerVersion...)
+	if err != nil {
+		return nil, err
+	}
+	if err = decodeMoveJson(resource["data"], out); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", resourceType, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,117 @@
+package endless
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRegistry struct {
+	Owner    AccountAddress    `json:"owner"`
+	Count    uint64            `json:"count"`
+	Total    *big.Int          `json:"total"`
+	Small    uint8             `json:"small"`
+	Seed     []byte            `json:"seed"`
+	Admin    *AccountAddress   `json:"admin"`
+	Delegate *AccountAddress   `json:"delegate"`
+	Names    []string          `json:"names"`
+	Entries  []testEntry       `json:"entries"`
+	Labels   map[string]uint64 `json:"labels"`
+	Extra    any               `json:"extra"`
+}
+
+type testEntry struct {
+	Key   uint64
+	Value bool
+}
+
+func TestAccountResourceAs_Json(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/accounts/0x1/resource/0xcafe::registry::Registry", r.URL.Path)
+		assert.Equal(t, "", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"type":"0xcafe::registry::Registry","data":{
+			"owner":{"inner":"0x2"},
+			"count":"18446744073709551615",
+			"total":"340282366920938463463374607431768211455",
+			"small":7,
+			"seed":"0x0102",
+			"admin":{"vec":["0x3"]},
+			"delegate":{"vec":[]},
+			"names":["a","b"],
+			"entries":[{"key":"1","value":true}],
+			"labels":{"x":"5"},
+			"extra":{"anything":["goes"]},
+			"unknown":"ignored"
+		}}`))
+	}))
+
+	registry, err := AccountResourceAs[testRegistry](client, AccountOne, "0xcafe::registry::Registry")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2", registry.Owner.String())
+	assert.Equal(t, uint64(18446744073709551615), registry.Count)
+	assert.Equal(t, "340282366920938463463374607431768211455", registry.Total.String())
+	assert.Equal(t, uint8(7), registry.Small)
+	assert.Equal(t, []byte{1, 2}, registry.Seed)
+	assert.Equal(t, "0x3", registry.Admin.String())
+	assert.Nil(t, registry.Delegate)
+	assert.Equal(t, []string{"a", "b"}, registry.Names)
+	assert.Equal(t, []testEntry{{Key: 1, Value: true}}, registry.Entries)
+	assert.Equal(t, map[string]uint64{"x": 5}, registry.Labels)
+	assert.Equal(t, map[string]any{"anything": []any{"goes"}}, registry.Extra)
+}
+
+func TestUnmarshalMoveJson_Errors(t *testing.T) {
+	out := testRegistry{}
+	assert.Error(t, UnmarshalMoveJson([]byte(`{"small":"256"}`), &out))
+	assert.Error(t, UnmarshalMoveJson([]byte(`{"count":"-1"}`), &out))
+	assert.Error(t, UnmarshalMoveJson([]byte(`{"owner":5}`), &out))
+	assert.Error(t, UnmarshalMoveJson([]byte(`{"names":"a"}`), &out))
+	assert.Error(t, UnmarshalMoveJson([]byte(`{"admin":{"vec":["0x1","0x2"]}}`), &out))
+	assert.Error(t, UnmarshalMoveJson([]byte(`{}`), out))
+}
+
+func TestAccountResourceAs_Bcs(t *testing.T) {
+	store := FungibleStore{Metadata: AccountOne, Balance: *big.NewInt(1_000_000), Frozen: true}
+	blob, err := bcs.Serialize(&store)
+	assert.NoError(t, err)
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/accounts/0x1/resource/"+ResourceTypeFungibleStore, r.URL.Path)
+		assert.Equal(t, "application/x-bcs", r.Header.Get("Accept"))
+		assert.Equal(t, "5", r.URL.Query().Get("ledger_version"))
+		_, _ = w.Write(blob)
+	}))
+
+	fetched, err := AccountResourceAs[FungibleStore](client, AccountOne, ResourceTypeFungibleStore, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountOne, fetched.Metadata)
+	assert.Equal(t, "1000000", fetched.Balance.String())
+	assert.True(t, fetched.Frozen)
+
+	// Trailing bytes mean the layout doesn't match
+	blob = append(blob, 0)
+	_, err = AccountResourceAs[FungibleStore](client, AccountOne, ResourceTypeFungibleStore, 5)
+	assert.Error(t, err)
+}
+
+func TestAccountData_Bcs(t *testing.T) {
+	// Account resource from TestMoveResourceBCS
+	b64text := "AQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABB2FjY291bnQHQWNjb3VudAA6ASAoJOVv\nbuFF0+C4aKaA1KaWubBIsTAA65TF9lLMugWD50wAAAAAAAAAAAAAAAAAAAABAAAAAAAAAA=="
+	blob, err := decodeB64(b64text)
+	assert.NoError(t, err)
+	resources := bcs.DeserializeSequence[AccountResourceRecord](bcs.NewDeserializer(blob))
+
+	account := AccountData{}
+	assert.NoError(t, bcs.Deserialize(&account, resources[0].Data))
+	assert.Len(t, account.AuthenticationKey, 1)
+	assert.Len(t, account.AuthenticationKey[0], 32)
+	assert.Equal(t, uint64(76), account.SequenceNumber)
+	assert.Equal(t, uint64(1), account.NumSignaturesRequired)
+
+	// JSON decodes the same fields
+	jsonAccount := AccountData{}
+	assert.NoError(t, UnmarshalMoveJson([]byte(`{"authentication_key":["0x2824e56f"],"sequence_number":"3","guid_creation_num":"0","num_signatures_required":"1"}`), &jsonAccount))
+	assert.Equal(t, AccountData{AuthenticationKey: [][]byte{{0x28, 0x24, 0xe5, 0x6f}}, SequenceNumber: 3, NumSignaturesRequired: 1}, jsonAccount)
+}
@@ -0,0 +1,167 @@
+package endless
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// DefaultRetryStatusCodes are the HTTP status codes retried when [RetryPolicy.RetryStatusCodes] is not set
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how a [NodeClient] retries requests that fail with a transport error or a retryable HTTP
+// status.  Set it with [NodeClient.SetRetryPolicy] or by passing it to [NewClient].
+//
+// Reads are always safe to retry.  Transaction submissions are only retried when RetrySubmissions is set, and a
+// resubmission that is rejected because the transaction is already in the mempool is reported as a success.  Other
+// POST requests made with [Post] or [PostAccept] are never retried.
+//
+//	policy := DefaultRetryPolicy()
+//	policy.MaxAttempts = 5
+//	client.SetRetryPolicy(policy)
+type RetryPolicy struct {
+	MaxAttempts      int           // MaxAttempts is the total number of attempts including the first, 1 or less disables retries
+	InitialBackoff   time.Duration // InitialBackoff is the delay before the first retry
+	MaxBackoff       time.Duration // MaxBackoff caps any single delay, including ones requested by a Retry-After header, 0 for no cap
+	Multiplier       float64       // Multiplier is how much the delay grows per attempt, 2 if not set
+	Jitter           float64       // Jitter is the fraction of each delay, between 0 and 1, that is randomized
+	RetryStatusCodes []int         // RetryStatusCodes are the HTTP statuses that are retried, [DefaultRetryStatusCodes] if nil
+	RetrySubmissions bool          // RetrySubmissions allows transaction submissions to be retried
+
+	// ShouldRetry optionally overrides which errors are retried.  It is called after the attempt limit and the
+	// submission check have passed, with the 1-based number of the attempt that failed.
+	ShouldRetry func(attempt int, err error) bool
+}
+
+// DefaultRetryPolicy is a policy suitable for public nodes, retrying 3 times over roughly 2 seconds
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:      4,
+		InitialBackoff:   250 * time.Millisecond,
+		MaxBackoff:       10 * time.Second,
+		Multiplier:       2,
+		Jitter:           0.2,
+		RetrySubmissions: true,
+	}
+}
+
+// shouldRetry decides if a request of the given kind should be attempted again after the error
+func (policy *RetryPolicy) shouldRetry(kind requestKind, attempt int, err error) bool {
+	if policy == nil || attempt >= policy.MaxAttempts {
+		return false
+	}
+	switch kind {
+	case requestKindRead:
+	case requestKindSubmission:
+		if !policy.RetrySubmissions {
+			return false
+		}
+	default:
+		return false
+	}
+	// Never retry if the caller gave up
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if policy.ShouldRetry != nil {
+		return policy.ShouldRetry(attempt, err)
+	}
+
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		statusCodes := policy.RetryStatusCodes
+		if statusCodes == nil {
+			statusCodes = DefaultRetryStatusCodes
+		}
+		return slices.Contains(statusCodes, httpErr.StatusCode)
+	}
+	// Anything else is a transport error
+	return true
+}
+
+// backoff is the delay after the given failed attempt, honoring the Retry-After header if the node sent one
+func (policy *RetryPolicy) backoff(attempt int, err error) time.Duration {
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		if delay, ok := retryAfter(httpErr.Header); ok {
+			return policy.capBackoff(delay)
+		}
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxBackoff > 0 {
+		delay = math.Min(delay, float64(policy.MaxBackoff))
+	}
+	if policy.Jitter > 0 {
+		jitter := math.Min(policy.Jitter, 1)
+		delay = delay * (1 - jitter + 2*jitter*rand.Float64())
+	}
+	return policy.capBackoff(time.Duration(delay))
+}
+
+func (policy *RetryPolicy) capBackoff(delay time.Duration) time.Duration {
+	if policy.MaxBackoff > 0 && delay > policy.MaxBackoff {
+		return policy.MaxBackoff
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header, which can be either a number of seconds or an HTTP date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// isAlreadyInMempool checks if a submission was rejected because the same transaction is already in the mempool
+func isAlreadyInMempool(err error) bool {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode == "transaction_already_in_mempool" ||
+		strings.Contains(strings.ToLower(apiErr.Message), "already in mempool")
+}
+
+// pendingTransactionFromSigned builds the submission response locally, for when the node does not return one
+func pendingTransactionFromSigned(signedTxn *SignedTransaction) (*api.SubmitTransactionResponse, error) {
+	hash, err := signedTxn.Hash()
+	if err != nil {
+		return nil, err
+	}
+	rawTxn := signedTxn.Transaction
+	sender := rawTxn.Sender
+	return &api.SubmitTransactionResponse{
+		Hash:                    hash,
+		Sender:                  &sender,
+		SequenceNumber:          rawTxn.SequenceNumber,
+		MaxGasAmount:            rawTxn.MaxGasAmount,
+		GasUnitPrice:            rawTxn.GasUnitPrice,
+		ExpirationTimestampSecs: rawTxn.ExpirationTimestampSeconds,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package endless
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+// testSignedTransactions signs transfers from a new account, with sequence numbers counting up from first
+func testSignedTransactions(t *testing.T, first uint64, count int) []*SignedTransaction {
+	t.Helper()
+	sender, err := NewEd25519Account()
+	assert.NoError(t, err)
+	payload, err := CoinTransferPayload(nil, AccountOne, 1)
+	assert.NoError(t, err)
+	signedTxns := make([]*SignedTransaction, count)
+	for i := range signedTxns {
+		rawTxn := &RawTransaction{
+			Sender:                     sender.Address,
+			SequenceNumber:             first + uint64(i),
+			Payload:                    TransactionPayload{Payload: payload},
+			MaxGasAmount:               DefaultMaxGasAmount,
+			GasUnitPrice:               DefaultGasUnitPrice,
+			ExpirationTimestampSeconds: uint64(time.Now().Unix() + 60),
+			ChainId:                    4,
+		}
+		signedTxns[i], err = rawTxn.SignedTransaction(sender)
+		assert.NoError(t, err)
+	}
+	return signedTxns
+}
+
+func TestRetryPolicy_RetriesReads(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"chain_id":4,"ledger_version":"10"}`))
+	}))
+
+	// Without a policy, the first failure is returned
+	_, err := client.Info()
+	assert.Error(t, err)
+
+	calls.Store(0)
+	client.SetRetryPolicy(testRetryPolicy())
+	info, err := client.Info()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), info.LedgerVersion())
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryPolicy_CancelledBackoff(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	client.SetRetryPolicy(policy)
+
+	// Cancelling the wait for a retry is the error, along with the failure it was retrying
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := client.WithContext(ctx).Info()
+	assert.ErrorIs(t, err, context.Canceled)
+	httpErr := &HttpError{}
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+}
+
+func TestRetryPolicy_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	client.SetRetryPolicy(testRetryPolicy())
+
+	_, err := client.Info()
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryPolicy_Submission(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Transaction already in mempool","error_code":"transaction_already_in_mempool"}`))
+	}))
+	signedTxn := testSignedTransactions(t, 7, 1)[0]
+
+	policy := testRetryPolicy()
+	policy.RetrySubmissions = false
+	client.SetRetryPolicy(policy)
+	_, err := client.SubmitTransaction(signedTxn)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	calls.Store(0)
+	policy.RetrySubmissions = true
+	response, err := client.SubmitTransaction(signedTxn)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	expectedHash, err := signedTxn.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedHash, response.Hash)
+	assert.Equal(t, uint64(7), response.SequenceNumber)
+}
+
+func TestRetryPolicy_SubmissionAlreadyInMempool(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"Transaction already in mempool","error_code":"transaction_already_in_mempool"}`))
+	}))
+	policy := testRetryPolicy()
+	policy.RetrySubmissions = true
+	client.SetRetryPolicy(policy)
+
+	// Only a retry can be the one that got there first, submitting the same transaction again is still an error
+	_, err := client.SubmitTransaction(testSignedTransactions(t, 7, 1)[0])
+	assert.ErrorContains(t, err, "already in mempool")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1, nil))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3, nil))
+	assert.Equal(t, time.Second, policy.backoff(10, nil))
+
+	// Retry-After takes priority, but is still capped
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+	assert.Equal(t, time.Second, policy.backoff(1, &HttpError{StatusCode: http.StatusTooManyRequests, Header: header}))
+	policy.MaxBackoff = 0
+	assert.Equal(t, 30*time.Second, policy.backoff(1, &HttpError{StatusCode: http.StatusTooManyRequests, Header: header}))
+}
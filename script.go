@@ -66,11 +66,27 @@ type ScriptArgument struct {
 	Value   any                   // The value of the argument
 }
 
+// scriptArgumentEnum has the variants of [ScriptArgument], whose values are serialized by type below
+var scriptArgumentEnum = bcs.NewEnum[any]("ScriptArgument").RegisterVariants(
+	uint32(ScriptArgumentU8),
+	uint32(ScriptArgumentU64),
+	uint32(ScriptArgumentU128),
+	uint32(ScriptArgumentAddress),
+	uint32(ScriptArgumentU8Vector),
+	uint32(ScriptArgumentBool),
+	uint32(ScriptArgumentU16),
+	uint32(ScriptArgumentU32),
+	uint32(ScriptArgumentU256),
+	uint32(ScriptArgumentSerialized),
+)
+
 //region ScriptArgument bcs.Struct
 // TODO: consider making a separate function to parse the value at input time rather than build time
 
 func (sa *ScriptArgument) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(sa.Variant))
+	if !scriptArgumentEnum.MarshalVariant(ser, uint32(sa.Variant)) {
+		return
+	}
 	switch sa.Variant {
 	case ScriptArgumentU8:
 		value, ok := (sa.Value).(uint8)
@@ -136,7 +152,11 @@ func (sa *ScriptArgument) MarshalBCS(ser *bcs.Serializer) {
 }
 
 func (sa *ScriptArgument) UnmarshalBCS(des *bcs.Deserializer) {
-	sa.Variant = ScriptArgumentVariant(des.Uleb128())
+	variant, ok := scriptArgumentEnum.UnmarshalVariant(des)
+	if !ok {
+		return
+	}
+	sa.Variant = ScriptArgumentVariant(variant)
 	switch sa.Variant {
 	case ScriptArgumentU8:
 		sa.Value = des.U8()
@@ -0,0 +1,254 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MaxInFlight is an option to [NewSequenceNumberManager], the most transactions allocated a sequence number but not
+// yet committed, 100 by default.  The node's mempool rejects transactions too far ahead of the account.
+type MaxInFlight int
+
+// sequenceNumberExpirationSlack is how long past a transaction's expiration before its sequence number, if never
+// committed, is assumed lost.  It allows for the node's clock being behind.
+const sequenceNumberExpirationSlack = 30 * time.Second
+
+// SequenceNumberManager allocates the sequence numbers of an account to concurrent builders of transactions.  It caps
+// the transactions in flight, and reconciles with the account's sequence number on chain, so a single rejected or
+// expired transaction doesn't stall every transaction after it.
+//
+// A sequence number that won't be committed, because the transaction was rejected or expired, is a gap.  It's given
+// to the next transaction built, filling the hole so the transactions after it can commit.
+//
+//	manager, err := NewSequenceNumberManager(client, sender.AccountAddress(), MaxInFlight(50))
+//	sequenceNumber, err := manager.Next()
+//	rawTxn, err := client.BuildTransaction(sender.AccountAddress(), payload, SequenceNumber(sequenceNumber))
+//	manager.Expires(sequenceNumber, rawTxn.ExpirationTimestampSeconds)
+//	// sign, and submit
+//	if err != nil {
+//		err = manager.Release(sequenceNumber, err)
+//	}
+type SequenceNumberManager struct {
+	rc          *NodeClient
+	sender      AccountAddress
+	maxInFlight int
+	expiration  time.Duration
+	pollPeriod  time.Duration
+
+	mu       sync.Mutex
+	synced   bool                 // synced is whether the sequence number has been fetched
+	next     uint64               // next is the next sequence number never allocated
+	onChain  uint64               // onChain is the account's sequence number, as last known
+	inFlight map[uint64]time.Time // inFlight are the sequence numbers allocated, and when they're lost if not committed
+	gaps     []uint64             // gaps are the sequence numbers released, to allocate again, in order
+}
+
+// NewSequenceNumberManager creates a SequenceNumberManager for the sender.  The sequence number is fetched on first
+// use.
+//
+// Accepts options:
+//   - [MaxInFlight]
+//   - [ExpirationSeconds], how long after allocation a transaction expires, unless set by
+//     [SequenceNumberManager.Expires], [DefaultExpirationSeconds] by default
+//   - [PollPeriod], how often to check for committed transactions while at MaxInFlight, 100ms by default
+func NewSequenceNumberManager(rc *NodeClient, sender AccountAddress, options ...any) (*SequenceNumberManager, error) {
+	manager := &SequenceNumberManager{
+		rc:          rc,
+		sender:      sender,
+		maxInFlight: 100,
+		expiration:  time.Duration(DefaultExpirationSeconds) * time.Second,
+		pollPeriod:  100 * time.Millisecond,
+		inFlight:    make(map[uint64]time.Time),
+	}
+	for i, option := range options {
+		switch value := option.(type) {
+		case MaxInFlight:
+			if value < 1 {
+				return nil, errors.New("MaxInFlight must be at least 1")
+			}
+			manager.maxInFlight = int(value)
+		case ExpirationSeconds:
+			if value < 0 {
+				return nil, errors.New("ExpirationSeconds cannot be less than 0")
+			}
+			manager.expiration = time.Duration(value) * time.Second
+		case PollPeriod:
+			manager.pollPeriod = time.Duration(value)
+		default:
+			return nil, fmt.Errorf("NewSequenceNumberManager arg [%d] unknown option type %T", i+3, option)
+		}
+	}
+	return manager, nil
+}
+
+// Next allocates a sequence number, a gap if there is one.  While [MaxInFlight] transactions are in flight, it waits
+// for some to commit, or for the client's context to be done.
+func (manager *SequenceNumberManager) Next() (uint64, error) {
+	for {
+		manager.mu.Lock()
+		synced := manager.synced
+		if synced {
+			if sequenceNumber, ok := manager.allocate(); ok {
+				manager.mu.Unlock()
+				return sequenceNumber, nil
+			}
+		}
+		manager.mu.Unlock()
+
+		if synced {
+			// Full, wait for transactions to commit
+			if err := manager.rc.sleep(manager.pollPeriod); err != nil {
+				return 0, err
+			}
+		}
+		if err := manager.Resync(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// allocate takes the first gap, or the next sequence number if there is room.  The lock must be held.
+func (manager *SequenceNumberManager) allocate() (uint64, bool) {
+	var sequenceNumber uint64
+	switch {
+	case len(manager.gaps) > 0:
+		sequenceNumber = manager.gaps[0]
+		manager.gaps = manager.gaps[1:]
+	case len(manager.inFlight) < manager.maxInFlight:
+		sequenceNumber = manager.next
+		manager.next++
+	default:
+		return 0, false
+	}
+	manager.inFlight[sequenceNumber] = time.Now().Add(manager.expiration + sequenceNumberExpirationSlack)
+	return sequenceNumber, true
+}
+
+// Expires records the expiration of the transaction built with the sequence number, in seconds since the Unix epoch.
+// If it's never committed, the sequence number is given out again once the transaction has expired.
+func (manager *SequenceNumberManager) Expires(sequenceNumber uint64, expirationTimestampSeconds uint64) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if _, ok := manager.inFlight[sequenceNumber]; ok {
+		manager.inFlight[sequenceNumber] = time.Unix(int64(expirationTimestampSeconds), 0).Add(sequenceNumberExpirationSlack)
+	}
+}
+
+// Committed records that the transaction with the sequence number was committed, successful or not, which means
+// every sequence number before it was committed too
+func (manager *SequenceNumberManager) Committed(sequenceNumber uint64) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.reconcile(sequenceNumber+1, false)
+}
+
+// Release gives back a sequence number whose transaction won't be committed, with the error why.  The sequence number
+// is allocated again by [SequenceNumberManager.Next].  Release the sequence numbers of transactions that failed to
+// build or sign, that the node rejected, or that expired, but not of transactions that may still be committed.
+//
+// On [ErrSequenceNumberTooOld] or [ErrSequenceNumberTooNew], the account's sequence number is fetched again, and its
+// error is returned.
+func (manager *SequenceNumberManager) Release(sequenceNumber uint64, err error) error {
+	manager.mu.Lock()
+	if _, ok := manager.inFlight[sequenceNumber]; ok {
+		delete(manager.inFlight, sequenceNumber)
+		if sequenceNumber >= manager.onChain && !slices.Contains(manager.gaps, sequenceNumber) {
+			manager.gaps = append(manager.gaps, sequenceNumber)
+			slices.Sort(manager.gaps)
+		}
+	}
+	manager.mu.Unlock()
+
+	if errors.Is(err, ErrSequenceNumberTooOld) || errors.Is(err, ErrSequenceNumberTooNew) {
+		return manager.Resync()
+	}
+	return nil
+}
+
+// Resync fetches the account's sequence number, dropping what was committed, and releasing what was in flight past
+// its transaction's expiration
+func (manager *SequenceNumberManager) Resync() error {
+	account, err := manager.rc.Account(manager.sender)
+	if err != nil {
+		return err
+	}
+	sequenceNumber, err := account.SequenceNumber()
+	if err != nil {
+		return fmt.Errorf("sequence number of %s: %w", manager.sender.String(), err)
+	}
+
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if !manager.synced {
+		manager.synced = true
+		manager.onChain = sequenceNumber
+		manager.next = sequenceNumber
+		return nil
+	}
+	manager.reconcile(sequenceNumber, true)
+	return nil
+}
+
+// Reset sets the next sequence number, forgetting everything in flight
+func (manager *SequenceNumberManager) Reset(next uint64) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	manager.synced = true
+	manager.onChain = next
+	manager.next = next
+	manager.inFlight = make(map[uint64]time.Time)
+	manager.gaps = nil
+}
+
+// InFlight is the number of sequence numbers allocated but not yet committed or released
+func (manager *SequenceNumberManager) InFlight() int {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	return len(manager.inFlight)
+}
+
+// reconcile moves up to the sequence number on chain, optionally releasing sequence numbers whose transactions have
+// expired.  The lock must be held.
+func (manager *SequenceNumberManager) reconcile(onChain uint64, releaseLost bool) {
+	// A lagging node may report an older sequence number
+	manager.onChain = max(manager.onChain, onChain)
+	manager.next = max(manager.next, manager.onChain)
+
+	now := time.Now()
+	for sequenceNumber, lostAt := range manager.inFlight {
+		switch {
+		case sequenceNumber < manager.onChain:
+			delete(manager.inFlight, sequenceNumber)
+		case releaseLost && now.After(lostAt):
+			delete(manager.inFlight, sequenceNumber)
+			manager.gaps = append(manager.gaps, sequenceNumber)
+		}
+	}
+	manager.gaps = slices.DeleteFunc(manager.gaps, func(sequenceNumber uint64) bool {
+		return sequenceNumber < manager.onChain
+	})
+	slices.Sort(manager.gaps)
+}
+
+// isRejected tells whether the node rejected a request, so a transaction submitted won't be committed.  Other errors,
+// like timeouts, may have happened after the node accepted it.
+func isRejected(err error) bool {
+	batchErr := &BatchSubmitError{}
+	if errors.As(err, &batchErr) {
+		return true
+	}
+	httpErr := &HttpError{}
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.StatusCode >= http.StatusBadRequest && httpErr.StatusCode < http.StatusInternalServerError
+}
+
+// NewSequenceNumberManager creates a [SequenceNumberManager] for the sender, see [NewSequenceNumberManager]
+func (client *Client) NewSequenceNumberManager(sender AccountAddress, options ...any) (*SequenceNumberManager, error) {
+	return NewSequenceNumberManager(client.nodeClient, sender, options...)
+}
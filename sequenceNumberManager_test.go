@@ -0,0 +1,144 @@
+package endless
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSequenceNumberManager creates a SequenceNumberManager for an account with the sequence number on chain
+func newTestSequenceNumberManager(t *testing.T, onChain *atomic.Uint64, options ...any) *SequenceNumberManager {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"sequence_number":"%d","authentication_key":["0x01"],"num_signatures_required":1}`, onChain.Load())
+	}))
+	manager, err := NewSequenceNumberManager(client, AccountOne, options...)
+	assert.NoError(t, err)
+	return manager
+}
+
+func nextSequenceNumber(t *testing.T, manager *SequenceNumberManager) uint64 {
+	t.Helper()
+	sequenceNumber, err := manager.Next()
+	assert.NoError(t, err)
+	return sequenceNumber
+}
+
+func TestSequenceNumberManager_Gaps(t *testing.T) {
+	onChain := &atomic.Uint64{}
+	onChain.Store(5)
+	manager := newTestSequenceNumberManager(t, onChain)
+
+	assert.Equal(t, uint64(5), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(6), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(7), nextSequenceNumber(t, manager))
+	assert.Equal(t, 3, manager.InFlight())
+
+	// A rejected transaction's sequence number is filled by the next
+	assert.NoError(t, manager.Release(6, ErrInsufficientBalance))
+	assert.Equal(t, 2, manager.InFlight())
+	assert.Equal(t, uint64(6), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(8), nextSequenceNumber(t, manager))
+
+	// Committing one commits all before it
+	manager.Committed(6)
+	assert.Equal(t, 2, manager.InFlight())
+
+	// Out of date sequence numbers resync from the chain
+	onChain.Store(20)
+	assert.NoError(t, manager.Release(7, fmt.Errorf("submit transaction api err: %w", ErrSequenceNumberTooOld)))
+	assert.Equal(t, 0, manager.InFlight())
+	assert.Equal(t, uint64(20), nextSequenceNumber(t, manager))
+
+	manager.Reset(3)
+	assert.Equal(t, 0, manager.InFlight())
+	assert.Equal(t, uint64(3), nextSequenceNumber(t, manager))
+}
+
+func TestSequenceNumberManager_MaxInFlight(t *testing.T) {
+	onChain := &atomic.Uint64{}
+	manager := newTestSequenceNumberManager(t, onChain, MaxInFlight(2), PollPeriod(time.Millisecond))
+	assert.Equal(t, uint64(0), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(1), nextSequenceNumber(t, manager))
+
+	// Waits until a transaction commits
+	done := make(chan uint64)
+	go func() {
+		sequenceNumber, _ := manager.Next()
+		done <- sequenceNumber
+	}()
+	select {
+	case <-done:
+		t.Fatal("allocated past MaxInFlight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	onChain.Store(1)
+	assert.Equal(t, uint64(2), <-done)
+	assert.Equal(t, 2, manager.InFlight())
+
+	_, err := NewSequenceNumberManager(manager.rc, AccountOne, MaxInFlight(0))
+	assert.Error(t, err)
+	_, err = NewSequenceNumberManager(manager.rc, AccountOne, "bad option")
+	assert.ErrorContains(t, err, "unknown option type")
+}
+
+func TestSequenceNumberManager_Lost(t *testing.T) {
+	onChain := &atomic.Uint64{}
+	manager := newTestSequenceNumberManager(t, onChain, ExpirationSeconds(0))
+	assert.Equal(t, uint64(0), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(1), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(2), nextSequenceNumber(t, manager))
+
+	// Sequence numbers in flight past their transaction's expiration are given out again
+	expired := uint64(time.Now().Add(-sequenceNumberExpirationSlack - time.Second).Unix())
+	manager.Expires(0, expired)
+	manager.Expires(1, uint64(time.Now().Unix())+600)
+	manager.Expires(2, expired)
+	assert.NoError(t, manager.Resync())
+	assert.Equal(t, 1, manager.InFlight())
+	assert.Equal(t, uint64(0), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(2), nextSequenceNumber(t, manager))
+	assert.Equal(t, uint64(3), nextSequenceNumber(t, manager))
+
+	// Without an expiration, the manager's is assumed
+	manager.mu.Lock()
+	lostAt := manager.inFlight[3]
+	manager.mu.Unlock()
+	assert.WithinDuration(t, time.Now().Add(sequenceNumberExpirationSlack), lostAt, time.Second)
+
+	_, err := NewSequenceNumberManager(manager.rc, AccountOne, ExpirationSeconds(-1))
+	assert.Error(t, err)
+}
+
+func TestSequenceNumberManager_Concurrent(t *testing.T) {
+	onChain := &atomic.Uint64{}
+	manager := newTestSequenceNumberManager(t, onChain, MaxInFlight(1000))
+
+	sequenceNumbers := make([]uint64, 100)
+	wg := sync.WaitGroup{}
+	for i := range sequenceNumbers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sequenceNumbers[i], _ = manager.Next()
+		}()
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, sequenceNumbers, func() []uint64 {
+		expected := make([]uint64, 100)
+		for i := range expected {
+			expected[i] = uint64(i)
+		}
+		return expected
+	}())
+}
+
+func TestIsRejected(t *testing.T) {
+	assert.True(t, isRejected(fmt.Errorf("submit transaction api err: %w", &HttpError{StatusCode: http.StatusBadRequest})))
+	assert.False(t, isRejected(&HttpError{StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, isRejected(fmt.Errorf("timeout")))
+}
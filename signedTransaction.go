@@ -96,16 +96,23 @@ func (txn *SignedTransaction) Verify() error {
 	}
 }
 
-// TransactionPrefix is a cached hash prefix for taking transaction hashes
-var TransactionPrefix *[]byte
+// TransactionPrefix is a cached hash prefix for taking transaction hashes, computed up front as transactions may be
+// hashed concurrently
+var TransactionPrefix = func() *[]byte {
+	hash := Sha3256Hash([][]byte{[]byte(transactionPrefixStr)})
+	return &hash
+}()
+
+const transactionPrefixStr = "ENDLESS::Transaction"
 
 // Hash takes the hash of the SignedTransaction
 //
 // Note: At the moment, this assumes that the transaction is a UserTransaction
 func (txn *SignedTransaction) Hash() (string, error) {
-	if TransactionPrefix == nil {
-		hash := Sha3256Hash([][]byte{[]byte("ENDLESS::Transaction")})
-		TransactionPrefix = &hash
+	prefix := TransactionPrefix
+	if prefix == nil {
+		hash := Sha3256Hash([][]byte{[]byte(transactionPrefixStr)})
+		prefix = &hash
 	}
 
 	txnBytes, err := bcs.Serialize(txn)
@@ -116,7 +123,7 @@ func (txn *SignedTransaction) Hash() (string, error) {
 	// Transaction signature is defined as, the domain separated prefix based on struct (Transaction)
 	// Then followed by the type of the transaction for the enum, UserTransaction is 0
 	// Then followed by BCS encoded bytes of the signed transaction
-	hashBytes := Sha3256Hash([][]byte{*TransactionPrefix, {byte(UserTransactionVariant)}, txnBytes})
+	hashBytes := Sha3256Hash([][]byte{*prefix, {byte(UserTransactionVariant)}, txnBytes})
 
 	return base58.Encode(hashBytes), nil
 	//return BytesToHex(hashBytes), nil
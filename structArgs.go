@@ -0,0 +1,220 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+)
+
+// StructLayouts looks up the fields of Move structs, for converting struct arguments in [EntryFunctionFromAbi] and
+// [ViewFunctionFromAbi].  It's implemented by [AbiStructLayouts] for ABIs at hand, and by [NodeClient] for fetching
+// them.
+type StructLayouts interface {
+	StructLayout(structTag *StructTag) (*api.MoveStruct, error)
+}
+
+// AbiStructLayouts looks up struct layouts in module ABIs
+//
+//	payload, err := EntryFunctionFromAbi(abi, address, "registry", "register", nil, args, AbiStructLayouts{otherAbi})
+type AbiStructLayouts []*api.MoveModule
+
+// StructLayout returns the struct from the ABI of its module
+func (layouts AbiStructLayouts) StructLayout(structTag *StructTag) (*api.MoveStruct, error) {
+	for _, module := range layouts {
+		if module == nil || module.Address == nil || *module.Address != structTag.Address || module.Name != structTag.Module {
+			continue
+		}
+		return findStructLayout(module, structTag)
+	}
+	return nil, fmt.Errorf("no ABI for module of struct %s", structTag.String())
+}
+
+// StructLayout returns the struct from the ABI of its module, see [NodeClient.ModuleAbi]
+func (rc *NodeClient) StructLayout(structTag *StructTag) (*api.MoveStruct, error) {
+	abi, err := rc.ModuleAbi(structTag.Address, structTag.Module)
+	if err != nil {
+		return nil, err
+	}
+	return findStructLayout(abi, structTag)
+}
+
+// structLayoutsChain looks up struct layouts in each of its layouts in turn
+type structLayoutsChain []StructLayouts
+
+func (layouts structLayoutsChain) StructLayout(structTag *StructTag) (*api.MoveStruct, error) {
+	var errs []error
+	for _, layout := range layouts {
+		moveStruct, err := layout.StructLayout(structTag)
+		if err == nil {
+			return moveStruct, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no layout for struct %s", structTag.String())
+	}
+	return nil, errors.Join(errs...)
+}
+
+// functionStructLayouts returns the layouts for converting the arguments of a function, those of its own module first,
+// then any given in options
+func functionStructLayouts(abi any, options []any) (StructLayouts, error) {
+	layouts := structLayoutsChain{}
+	if module, ok := abi.(*api.MoveModule); ok {
+		layouts = append(layouts, AbiStructLayouts{module})
+	}
+	for i, option := range options {
+		switch option := option.(type) {
+		case StructLayouts:
+			if option != nil {
+				layouts = append(layouts, option)
+			}
+		default:
+			return nil, fmt.Errorf("option %d bad type %T", i+1, option)
+		}
+	}
+	return layouts, nil
+}
+
+func findStructLayout(module *api.MoveModule, structTag *StructTag) (*api.MoveStruct, error) {
+	for _, moveStruct := range module.Structs {
+		if moveStruct.Name == structTag.Name {
+			if len(moveStruct.GenericTypeParams) != len(structTag.TypeParams) {
+				return nil, fmt.Errorf("struct %s expects %d type arguments", structTag.String(), len(moveStruct.GenericTypeParams))
+			}
+			return moveStruct, nil
+		}
+	}
+	return nil, fmt.Errorf("struct %s not found in module %s", structTag.String(), module.Name)
+}
+
+// convertStructArg BCS encodes an argument of a struct type.  The argument is either a [bcs.Marshaler] encoding the
+// whole struct, or a map or Go struct with a value for each field of the struct's layout.  Go struct fields are matched
+// to Move fields by json tag, or by name ignoring case and underscores e.g. ExpiresAt for expires_at.
+func convertStructArg(structTag *StructTag, arg any, generics []TypeTag, layouts StructLayouts) ([]byte, error) {
+	value := reflect.ValueOf(arg)
+	if !value.IsValid() {
+		return nil, fmt.Errorf("cannot convert nil to struct %s", structTag.String())
+	}
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil, fmt.Errorf("cannot convert nil to struct %s", structTag.String())
+		}
+		if marshaler, ok := value.Interface().(bcs.Marshaler); ok {
+			return bcs.Serialize(marshaler)
+		}
+		value = value.Elem()
+	}
+	if value.CanAddr() {
+		if marshaler, ok := value.Addr().Interface().(bcs.Marshaler); ok {
+			return bcs.Serialize(marshaler)
+		}
+	} else if reflect.PointerTo(value.Type()).Implements(reflect.TypeFor[bcs.Marshaler]()) {
+		// A struct passed by value, which marshals through a pointer
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		return bcs.Serialize(pointer.Interface().(bcs.Marshaler))
+	}
+
+	if value.Kind() != reflect.Map && value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid input type %T for struct %s, must be a map, struct, or bcs.Marshaler", arg, structTag.String())
+	}
+	if layouts == nil {
+		return nil, fmt.Errorf("no layout for struct %s, pass a bcs.Marshaler", structTag.String())
+	}
+	layout, err := layouts.StructLayout(structTag)
+	if err != nil {
+		return nil, err
+	}
+	if value.Kind() == reflect.Map && value.Len() != len(layout.Fields) {
+		return nil, fmt.Errorf("struct %s has %d fields, got %d", structTag.String(), len(layout.Fields), value.Len())
+	}
+
+	// Field types refer to the struct's type parameters, which may refer to the function's
+	typeParams := make([]TypeTag, len(structTag.TypeParams))
+	for i, typeParam := range structTag.TypeParams {
+		typeParams[i], err = substituteGenerics(typeParam, generics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buffer := make([]byte, 0)
+	for _, field := range layout.Fields {
+		fieldValue, ok := structArgField(value, field.Name)
+		if !ok {
+			return nil, fmt.Errorf("missing field %s of struct %s", field.Name, structTag.String())
+		}
+		fieldType, err := ParseTypeTag(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		b, err := convertArg(*fieldType, fieldValue, typeParams, layouts)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of struct %s: %w", field.Name, structTag.String(), err)
+		}
+		buffer = append(buffer, b...)
+	}
+	return buffer, nil
+}
+
+// structArgField returns the value of a Move field from a map or Go struct
+func structArgField(value reflect.Value, name string) (any, bool) {
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		fieldValue := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+		if !fieldValue.IsValid() {
+			return nil, false
+		}
+		return fieldValue.Interface(), true
+	case reflect.Struct:
+		fieldValue, ok := structField(value, name)
+		if !ok {
+			return nil, false
+		}
+		return fieldValue.Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+// substituteGenerics replaces the generics in a type with their type arguments
+func substituteGenerics(typeTag TypeTag, generics []TypeTag) (TypeTag, error) {
+	switch inner := typeTag.Value.(type) {
+	case *GenericTag:
+		if inner.Num >= uint64(len(generics)) {
+			return TypeTag{}, errors.New("generic number out of bounds")
+		}
+		return generics[inner.Num], nil
+	case *VectorTag:
+		typeParam, err := substituteGenerics(inner.TypeParam, generics)
+		if err != nil {
+			return TypeTag{}, err
+		}
+		return TypeTag{Value: &VectorTag{TypeParam: typeParam}}, nil
+	case *ReferenceTag:
+		typeParam, err := substituteGenerics(inner.TypeParam, generics)
+		if err != nil {
+			return TypeTag{}, err
+		}
+		return TypeTag{Value: &ReferenceTag{TypeParam: typeParam}}, nil
+	case *StructTag:
+		typeParams := make([]TypeTag, len(inner.TypeParams))
+		for i, typeParam := range inner.TypeParams {
+			substituted, err := substituteGenerics(typeParam, generics)
+			if err != nil {
+				return TypeTag{}, err
+			}
+			typeParams[i] = substituted
+		}
+		return TypeTag{Value: &StructTag{Address: inner.Address, Module: inner.Module, Name: inner.Name, TypeParams: typeParams}}, nil
+	default:
+		return typeTag, nil
+	}
+}
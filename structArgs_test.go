@@ -0,0 +1,156 @@
+package endless
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testStructModuleJson = `{"address":"0xcafe","name":"keys","friends":[],"exposed_functions":[
+	{"name":"register","visibility":"public","is_entry":true,"is_view":false,"generic_type_params":[],"params":["&signer","0xcafe::keys::Key"],"return":[]},
+	{"name":"register_all","visibility":"public","is_entry":true,"is_view":false,"generic_type_params":[],"params":["&signer","vector<0xcafe::keys::Key>"],"return":[]},
+	{"name":"set","visibility":"public","is_entry":true,"is_view":false,"generic_type_params":[{"constraints":[]}],"params":["&signer","0xcafe::keys::Pair<T0>"],"return":[]},
+	{"name":"link","visibility":"public","is_entry":true,"is_view":false,"generic_type_params":[],"params":["&signer","0xbeef::other::Link"],"return":[]}
+],"structs":[
+	{"name":"Key","is_native":false,"abilities":["copy","drop"],"generic_type_params":[],"fields":[{"name":"id","type":"u128"},{"name":"name","type":"0x1::string::String"}]},
+	{"name":"Pair","is_native":false,"abilities":["copy","drop"],"generic_type_params":[{"constraints":[]}],"fields":[{"name":"first","type":"T0"},{"name":"rest","type":"vector<T0>"},{"name":"expires_at","type":"0x1::option::Option<u64>"}]}
+]}`
+
+const testOtherModuleJson = `{"address":"0xbeef","name":"other","friends":[],"exposed_functions":[],"structs":[
+	{"name":"Link","is_native":false,"abilities":["copy","drop"],"generic_type_params":[],"fields":[{"name":"to","type":"address"},{"name":"key","type":"0xcafe::keys::Key"}]}
+]}`
+
+// testKeyBytes is the BCS of a 0xcafe::keys::Key
+func testKeyBytes(id byte, name string) []byte {
+	out := append([]byte{id}, make([]byte, 15)...)
+	return append(append(out, byte(len(name))), name...)
+}
+
+func testStructModule(t *testing.T, moduleJson string) (*api.MoveModule, AccountAddress) {
+	t.Helper()
+	abi := &api.MoveModule{}
+	assert.NoError(t, json.Unmarshal([]byte(moduleJson), abi))
+	return abi, *abi.Address
+}
+
+func TestEntryFunctionFromAbi_StructArgs(t *testing.T) {
+	abi, cafe := testStructModule(t, testStructModuleJson)
+
+	type key struct {
+		Id   int
+		Name string `json:"name"`
+	}
+	for _, arg := range []any{
+		map[string]any{"id": 1, "name": "a"},
+		key{Id: 1, Name: "a"},
+		&key{Id: 1, Name: "a"},
+	} {
+		entryFunction, err := EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{arg})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{testKeyBytes(1, "a")}, entryFunction.Args)
+	}
+
+	entryFunction, err := EntryFunctionFromAbi(abi, cafe, "keys", "register_all", nil, []any{[]key{{Id: 1, Name: "a"}, {Id: 2, Name: "b"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, append(append([]byte{2}, testKeyBytes(1, "a")...), testKeyBytes(2, "b")...), entryFunction.Args[0])
+
+	// Generic fields take the function's type arguments
+	type pair struct {
+		First     any
+		Rest      []any
+		ExpiresAt *uint64
+	}
+	entryFunction, err = EntryFunctionFromAbi(abi, cafe, "keys", "set", []any{"0xcafe::keys::Key"}, []any{pair{
+		First: map[string]any{"id": 1, "name": "a"},
+		Rest:  []any{key{Id: 2, Name: "b"}},
+	}})
+	assert.NoError(t, err)
+	expected := append(testKeyBytes(1, "a"), 1)
+	expected = append(append(expected, testKeyBytes(2, "b")...), 0)
+	assert.Equal(t, expected, entryFunction.Args[0])
+	entryFunction, err = EntryFunctionFromAbi(abi, cafe, "keys", "set", []any{"u8"}, []any{map[string]any{"first": 7, "rest": "0x0809", "expires_at": 10}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{7, 2, 8, 9, 1, 10, 0, 0, 0, 0, 0, 0, 0}, entryFunction.Args[0])
+	entryFunction, err = EntryFunctionFromAbi(abi, cafe, "keys", "set", []any{"u16"}, []any{map[string]any{"first": 7, "rest": []any{8, uint16(9)}, "expires_at": nil}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{7, 0, 2, 8, 0, 9, 0, 0}, entryFunction.Args[0])
+
+	// A bcs.Marshaler is used as is
+	marshaler := &bcsKey{id: 3, name: "c"}
+	entryFunction, err = EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{marshaler})
+	assert.NoError(t, err)
+	assert.Equal(t, testKeyBytes(3, "c"), entryFunction.Args[0])
+
+	// Fields must match the layout
+	_, err = EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{map[string]any{"id": 1}})
+	assert.ErrorContains(t, err, "has 2 fields")
+	_, err = EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{map[string]any{"id": 1, "nam": "a"}})
+	assert.ErrorContains(t, err, "missing field name")
+	_, err = EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{map[string]any{"id": "x", "name": "a"}})
+	assert.ErrorContains(t, err, "field id")
+	_, err = EntryFunctionFromAbi(abi, cafe, "keys", "register", nil, []any{5})
+	assert.Error(t, err)
+
+	// Structs of other modules need their layouts
+	link := map[string]any{"to": "0x1", "key": key{Id: 1, Name: "a"}}
+	_, err = EntryFunctionFromAbi(abi, cafe, "keys", "link", nil, []any{link})
+	assert.Error(t, err)
+	other, _ := testStructModule(t, testOtherModuleJson)
+	entryFunction, err = EntryFunctionFromAbi(abi, cafe, "keys", "link", nil, []any{link}, AbiStructLayouts{other})
+	assert.NoError(t, err)
+	assert.Equal(t, append(AccountOne[:], testKeyBytes(1, "a")...), entryFunction.Args[0])
+
+	// Without layouts, only a bcs.Marshaler works
+	keyTag, err := ParseTypeTag("0xcafe::keys::Key")
+	assert.NoError(t, err)
+	_, err = ConvertArg(*keyTag, map[string]any{"id": 1, "name": "a"}, nil)
+	assert.ErrorContains(t, err, "no layout")
+	b, err := ConvertArg(*keyTag, bcsKey{id: 1, name: "a"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testKeyBytes(1, "a"), b)
+}
+
+func TestNodeClient_EntryFunctionWithArgs_StructArgs(t *testing.T) {
+	_, cafe := testStructModule(t, testStructModuleJson)
+	_, beef := testStructModule(t, testOtherModuleJson)
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/accounts/"+cafe.String()+"/module/keys"):
+			_, _ = w.Write([]byte(`{"bytecode":"0x00","abi":` + testStructModuleJson + `}`))
+		case strings.HasSuffix(r.URL.Path, "/accounts/"+beef.String()+"/module/other"):
+			_, _ = w.Write([]byte(`{"bytecode":"0x00","abi":` + testOtherModuleJson + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	// The layouts of other modules are fetched
+	entryFunction, err := client.EntryFunctionWithArgs(cafe, "keys", "link", nil, []any{map[string]any{"to": "0x1", "key": map[string]any{"id": 1, "name": "a"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, append(AccountOne[:], testKeyBytes(1, "a")...), entryFunction.Args[0])
+
+	_, err = client.StructLayout(&StructTag{Address: beef, Module: "other", Name: "Missing"})
+	assert.ErrorContains(t, err, "not found")
+
+	// A Client looks them up the same way, for its view functions
+	var layouts StructLayouts = &Client{nodeClient: client}
+	_, err = layouts.StructLayout(&StructTag{Address: beef, Module: "other", Name: "Missing"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+// bcsKey encodes a 0xcafe::keys::Key itself
+type bcsKey struct {
+	id   uint64
+	name string
+}
+
+func (key *bcsKey) MarshalBCS(ser *bcs.Serializer) {
+	ser.U64(key.id)
+	ser.U64(0)
+	ser.WriteString(key.name)
+}
@@ -0,0 +1,220 @@
+package endless
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/endless-labs/endless-go-sdk/internal/util"
+)
+
+// TableItemRequest is the body of a table item lookup, see [NodeClient.TableItem]
+type TableItemRequest struct {
+	KeyType   string `json:"key_type"`   // KeyType is the Move type of the table's keys e.g. "address"
+	ValueType string `json:"value_type"` // ValueType is the Move type of the table's values e.g. "u64"
+	Key       any    `json:"key"`        // Key in its JSON form, e.g. u64 as a string
+}
+
+// RawTableItemRequest is the body of a raw table item lookup, see [NodeClient.TableItemBCS]
+type RawTableItemRequest struct {
+	Key string `json:"key"` // Key is the BCS encoded key, as hex with a leading 0x
+}
+
+// TableItem fetches the value for the key in a Move table, decoded as JSON.  The key is converted from a Go value the
+// same way as entry function arguments, see [ConvertArg], so a u64 key can be given as a uint64, int, or string.
+//
+// The handle is found in the resource holding the table, or in [api.WriteSetChangeWriteTableItem].  A missing key
+// returns an error matching [ErrTableItemNotFound].
+//
+//	value, err := client.TableItem(handle, "address", "u64", AccountOne)
+//
+// Optionally, a ledgerVersion can be given to get the value at a specific ledger version
+func (rc *NodeClient) TableItem(handle string, keyType string, valueType string, key any, ledgerVersion ...uint64) (data any, err error) {
+	request, err := tableItemRequest(keyType, valueType, key)
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: %w", err)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: %w", err)
+	}
+	au := rc.baseUrl.JoinPath("tables", handle, "item")
+	setLedgerVersion(au, ledgerVersion)
+	data, err = post[any](rc, au.String(), "", ContentTypeApplicationJson, bytes.NewReader(body), requestKindRead)
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: %w", err)
+	}
+	return data, nil
+}
+
+// TableItemBCS fetches the value for the key in a Move table as raw BCS, to be deserialized with [bcs.Deserializer].
+// The key is BCS encoded locally with [ConvertArgWithLayouts], so the value type isn't needed.
+//
+//	blob, err := client.TableItemBCS(handle, "address", AccountOne)
+//	balance := bcs.NewDeserializer(blob).U64()
+//
+// Optionally, a ledgerVersion can be given to get the value at a specific ledger version
+func (rc *NodeClient) TableItemBCS(handle string, keyType string, key any, ledgerVersion ...uint64) (data []byte, err error) {
+	keyTag, err := ParseTypeTag(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: bad key type %s: %w", keyType, err)
+	}
+	keyBytes, err := ConvertArgWithLayouts(*keyTag, key, nil, rc)
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: bad key for %s: %w", keyType, err)
+	}
+	body, err := json.Marshal(RawTableItemRequest{Key: util.BytesToHex(keyBytes)})
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: %w", err)
+	}
+	au := rc.baseUrl.JoinPath("tables", handle, "raw_item")
+	setLedgerVersion(au, ledgerVersion)
+	response, err := rc.do(&nodeRequest{
+		method:      http.MethodPost,
+		url:         au.String(),
+		accept:      "application/x-bcs",
+		contentType: ContentTypeApplicationJson,
+		body:        body,
+		kind:        requestKindRead,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get table item api err: %w", err)
+	}
+	return response.body, nil
+}
+
+// setLedgerVersion adds the optional ledger version to the query
+func setLedgerVersion(au *url.URL, ledgerVersion []uint64) {
+	if len(ledgerVersion) > 0 {
+		params := au.Query()
+		params.Set("ledger_version", strconv.FormatUint(ledgerVersion[0], 10))
+		au.RawQuery = params.Encode()
+	}
+}
+
+// tableItemRequest checks the types, and converts the key to its JSON form
+func tableItemRequest(keyType string, valueType string, key any) (*TableItemRequest, error) {
+	keyTag, err := ParseTypeTag(keyType)
+	if err != nil {
+		return nil, fmt.Errorf("bad key type %s: %w", keyType, err)
+	}
+	if _, err = ParseTypeTag(valueType); err != nil {
+		return nil, fmt.Errorf("bad value type %s: %w", valueType, err)
+	}
+	jsonKey, err := ConvertArgJson(*keyTag, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bad key for %s: %w", keyType, err)
+	}
+	return &TableItemRequest{
+		KeyType:   keyTag.String(),
+		ValueType: valueType,
+		Key:       jsonKey,
+	}, nil
+}
+
+// ConvertArgJson converts a Go value to the JSON form of the Move type used by the node's JSON API, accepting the same
+// inputs as [ConvertArg].  u64, u128, and u256 become decimal strings, addresses and vector<u8> become hex strings.
+func ConvertArgJson(typeArg TypeTag, arg any, generics []TypeTag) (any, error) {
+	switch innerType := typeArg.Value.(type) {
+	case *U8Tag:
+		return ConvertToU8(arg)
+	case *U16Tag:
+		return ConvertToU16(arg)
+	case *U32Tag:
+		return ConvertToU32(arg)
+	case *U64Tag:
+		num, err := ConvertToU64(arg)
+		if err != nil {
+			return nil, err
+		}
+		return strconv.FormatUint(num, 10), nil
+	case *U128Tag:
+		num, err := ConvertToU128(arg)
+		if err != nil {
+			return nil, err
+		}
+		return num.String(), nil
+	case *U256Tag:
+		num, err := ConvertToU256(arg)
+		if err != nil {
+			return nil, err
+		}
+		return num.String(), nil
+	case *BoolTag:
+		return ConvertToBool(arg)
+	case *AddressTag:
+		address, err := ConvertToAddress(arg)
+		if err != nil {
+			return nil, err
+		}
+		return address.String(), nil
+	case *GenericTag:
+		if innerType.Num >= uint64(len(generics)) {
+			return nil, errors.New("generic number out of bounds")
+		}
+		return ConvertArgJson(generics[innerType.Num], arg, generics)
+	case *ReferenceTag:
+		return ConvertArgJson(innerType.TypeParam, arg, generics)
+	case *VectorTag:
+		if _, ok := innerType.TypeParam.Value.(*U8Tag); ok {
+			switch arg := arg.(type) {
+			case string:
+				b, err := util.ParseHex(arg)
+				if err != nil {
+					return nil, err
+				}
+				return util.BytesToHex(b), nil
+			case []byte:
+				return util.BytesToHex(arg), nil
+			default:
+				return nil, errors.New("invalid input type for vector<u8>")
+			}
+		}
+		value := reflect.ValueOf(arg)
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return nil, fmt.Errorf("invalid input type %T for %s", arg, typeArg.String())
+		}
+		items := make([]any, value.Len())
+		for i := range value.Len() {
+			item, err := ConvertArgJson(innerType.TypeParam, value.Index(i).Interface(), generics)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case *StructTag:
+		if AccountOne == innerType.Address {
+			switch {
+			case innerType.Module == "object" && innerType.Name == "Object":
+				return ConvertArgJson(TypeTag{&AddressTag{}}, arg, generics)
+			case innerType.Module == "string" && innerType.Name == "String":
+				str, ok := arg.(string)
+				if !ok {
+					return nil, errors.New("invalid input type for 0x1::string::String")
+				}
+				return str, nil
+			case innerType.Module == "option" && innerType.Name == "Option":
+				if 1 != len(innerType.TypeParams) {
+					return nil, errors.New("invalid input type for option, must have exactly one type arg")
+				}
+				if arg == nil {
+					return map[string]any{"vec": []any{}}, nil
+				}
+				item, err := ConvertArgJson(innerType.TypeParams[0], arg, generics)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{"vec": []any{item}}, nil
+			}
+		}
+		return nil, fmt.Errorf("%s is currently not supported as an input type", typeArg.String())
+	default:
+		return nil, errors.New("unknown type argument")
+	}
+}
@@ -0,0 +1,90 @@
+package endless
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTableHandle = "0x1b854694ae746cdbd8d44186ca4929b2b337df21d1c74633be19b2710552fdca"
+
+func TestNodeClient_TableItem(t *testing.T) {
+	var request map[string]any
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/tables/"+testTableHandle+"/item", r.URL.Path)
+		assert.Equal(t, "7", r.URL.Query().Get("ledger_version"))
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &request))
+		if request["key"] == "0x2" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Table Item not found","error_code":"table_item_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`"12345"`))
+	}))
+
+	value, err := client.TableItem(testTableHandle, "address", "u64", AccountOne, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", value)
+	assert.Equal(t, map[string]any{"key_type": "address", "value_type": "u64", "key": "0x1"}, request)
+
+	_, err = client.TableItem(testTableHandle, "address", "u64", "0x2", 7)
+	assert.ErrorIs(t, err, ErrTableItemNotFound)
+
+	// Keys are checked before sending
+	_, err = client.TableItem(testTableHandle, "u8", "u64", 256, 7)
+	assert.Error(t, err)
+	_, err = client.TableItem(testTableHandle, "address", "not a type", AccountOne, 7)
+	assert.Error(t, err)
+}
+
+func TestNodeClient_TableItemBCS(t *testing.T) {
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/tables/"+testTableHandle+"/raw_item", r.URL.Path)
+		assert.Equal(t, "application/x-bcs", r.Header.Get("Accept"))
+		request := RawTableItemRequest{}
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &request))
+		// u64 key 5 in BCS
+		assert.Equal(t, "0x0500000000000000", request.Key)
+		value, _ := bcs.SerializeU64(12345)
+		_, _ = w.Write(value)
+	}))
+
+	blob, err := client.TableItemBCS(testTableHandle, "u64", "5")
+	assert.NoError(t, err)
+	des := bcs.NewDeserializer(blob)
+	assert.Equal(t, uint64(12345), des.U64())
+	assert.NoError(t, des.Error())
+}
+
+func TestConvertArgJson(t *testing.T) {
+	convert := func(typeStr string, arg any) any {
+		tag, err := ParseTypeTag(typeStr)
+		assert.NoError(t, err)
+		value, err := ConvertArgJson(*tag, arg, nil)
+		assert.NoError(t, err)
+		return value
+	}
+
+	assert.Equal(t, uint8(1), convert("u8", 1))
+	assert.Equal(t, "18446744073709551615", convert("u64", uint64(18446744073709551615)))
+	assert.Equal(t, "340282366920938463463374607431768211455", convert("u128", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))))
+	assert.Equal(t, true, convert("bool", "true"))
+	assert.Equal(t, "0x1", convert("0x1::object::Object<0x1::fungible_asset::Metadata>", "0x01"))
+	assert.Equal(t, "0x0102", convert("vector<u8>", []byte{1, 2}))
+	assert.Equal(t, []any{"1", "2"}, convert("vector<u64>", []uint64{1, 2}))
+	assert.Equal(t, "name", convert("0x1::string::String", "name"))
+	assert.Equal(t, map[string]any{"vec": []any{}}, convert("0x1::option::Option<u64>", nil))
+	assert.Equal(t, map[string]any{"vec": []any{"3"}}, convert("0x1::option::Option<u64>", 3))
+
+	tag, _ := ParseTypeTag("0x1::my::Custom")
+	_, err := ConvertArgJson(*tag, map[string]any{}, nil)
+	assert.Error(t, err)
+}
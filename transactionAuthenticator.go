@@ -33,6 +33,14 @@ type TransactionAuthenticator struct {
 	Auth    TransactionAuthenticatorImpl
 }
 
+// transactionAuthenticatorEnum has the variants of [TransactionAuthenticator]
+var transactionAuthenticatorEnum = bcs.NewEnum[TransactionAuthenticatorImpl]("TransactionAuthenticator").
+	Register(uint32(TransactionAuthenticatorEd25519), func() TransactionAuthenticatorImpl { return &Ed25519TransactionAuthenticator{} }).
+	Register(uint32(TransactionAuthenticatorMultiEd25519), func() TransactionAuthenticatorImpl { return &MultiEd25519TransactionAuthenticator{} }).
+	Register(uint32(TransactionAuthenticatorMultiAgent), func() TransactionAuthenticatorImpl { return &MultiAgentTransactionAuthenticator{} }).
+	Register(uint32(TransactionAuthenticatorFeePayer), func() TransactionAuthenticatorImpl { return &FeePayerTransactionAuthenticator{} }).
+	Register(uint32(TransactionAuthenticatorSingleSender), func() TransactionAuthenticatorImpl { return &SingleSenderTransactionAuthenticator{} })
+
 func NewTransactionAuthenticator(auth *crypto.AccountAuthenticator) (*TransactionAuthenticator, error) {
 	txnAuth := &TransactionAuthenticator{}
 	switch auth.Variant {
@@ -78,32 +86,12 @@ func (ea *TransactionAuthenticator) Verify(msg []byte) bool {
 //region TransactionAuthenticator bcs.Struct
 
 func (ea *TransactionAuthenticator) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(ea.Variant))
-	ea.Auth.MarshalBCS(ser)
+	transactionAuthenticatorEnum.Marshal(ser, uint32(ea.Variant), ea.Auth)
 }
 
 func (ea *TransactionAuthenticator) UnmarshalBCS(des *bcs.Deserializer) {
-	kindNum := des.Uleb128()
-	if des.Error() != nil {
-		return
-	}
-	ea.Variant = TransactionAuthenticatorVariant(kindNum)
-	switch ea.Variant {
-	case TransactionAuthenticatorEd25519:
-		ea.Auth = &Ed25519TransactionAuthenticator{}
-	case TransactionAuthenticatorMultiEd25519:
-		ea.Auth = &MultiEd25519TransactionAuthenticator{}
-	case TransactionAuthenticatorMultiAgent:
-		ea.Auth = &MultiAgentTransactionAuthenticator{}
-	case TransactionAuthenticatorFeePayer:
-		ea.Auth = &FeePayerTransactionAuthenticator{}
-	case TransactionAuthenticatorSingleSender:
-		ea.Auth = &SingleSenderTransactionAuthenticator{}
-	default:
-		des.SetError(fmt.Errorf("unknown TransactionAuthenticator kind: %d", kindNum))
-		return
-	}
-	ea.Auth.UnmarshalBCS(des)
+	variant, auth := transactionAuthenticatorEnum.Unmarshal(des)
+	ea.Variant, ea.Auth = TransactionAuthenticatorVariant(variant), auth
 }
 
 //endregion
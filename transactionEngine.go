@@ -0,0 +1,328 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/crypto"
+)
+
+// EngineWorkers is an option to [NewTransactionEngine], the most transactions built, signed, and submitted at once,
+// 16 by default.  Waiting for the transactions doesn't count, it's bounded by [MaxInFlight] per sender.
+type EngineWorkers int
+
+// FeePayerAccount is an option to [NewTransactionEngine], a fee payer for every transaction
+//
+//	engine, err := NewTransactionEngine(client, senders, FeePayerAccount{Signer: sponsor})
+type FeePayerAccount struct {
+	Signer TransactionSigner // Signer signs as the fee payer
+}
+
+// engineMaxAttempts is the most times a payload is built and submitted, when rejected for its sequence number
+const engineMaxAttempts = 3
+
+// TransactionEngine sends transactions from a pool of sender accounts for sustained throughput, such as payouts or
+// load tests.  Each payload goes to the sender with the fewest transactions in flight, whose sequence numbers are
+// allocated by a [SequenceNumberManager].  It's built, signed, submitted, and waited for, with a result per payload.
+//
+//	engine, err := NewTransactionEngine(client, senders, EngineWorkers(32), MaxInFlight(50))
+//	go engine.Run(payloads, results)
+//	for result := range results {
+//		if result.Result.Status != TransactionStatusCommitted {
+//			// retry result.Id
+//		}
+//	}
+//	stats := engine.Stats()
+type TransactionEngine struct {
+	rc           *NodeClient
+	senders      []*engineSender
+	feePayer     TransactionSigner
+	workers      int
+	buildOptions []any
+	waitOptions  []any
+
+	next  atomic.Uint64 // next is the sender to start looking from, to spread ties
+	stats engineStats
+}
+
+// engineSender is a sender of a [TransactionEngine], with its sequence numbers
+type engineSender struct {
+	signer  TransactionSigner
+	manager *SequenceNumberManager
+}
+
+// TransactionEngineResult is the outcome of a payload sent by a [TransactionEngine]
+type TransactionEngineResult struct {
+	Id             uint64            // Id of the payload
+	Sender         AccountAddress    // Sender of the transaction, empty if no sender could be picked
+	SequenceNumber uint64            // SequenceNumber of the transaction, as last built
+	Attempts       int               // Attempts is the number of times the transaction was built and submitted
+	Result         TransactionResult // Result of waiting, pending with Err if it wasn't submitted
+	Latency        time.Duration     // Latency from taking the payload to the result
+}
+
+// TransactionEngineStats is a snapshot of the work of a [TransactionEngine]
+type TransactionEngineStats struct {
+	Payloads     uint64        // Payloads is the number of payloads taken
+	Submitted    uint64        // Submitted is the number of transactions the node accepted
+	Committed    uint64        // Committed is the number of transactions committed successfully
+	Failed       uint64        // Failed is the number of transactions committed that failed
+	NotCommitted uint64        // NotCommitted is the number of payloads that errored, expired, or timed out
+	Elapsed      time.Duration // Elapsed is the time since [TransactionEngine.Run] started
+	Throughput   float64       // Throughput is the committed transactions per second, successful or not
+	LatencyMean  time.Duration // LatencyMean is the mean latency of the results
+	LatencyMax   time.Duration // LatencyMax is the worst latency of the results
+}
+
+// engineStats are the counters behind [TransactionEngineStats]
+type engineStats struct {
+	mu           sync.Mutex
+	start        time.Time
+	payloads     uint64
+	submitted    uint64
+	committed    uint64
+	failed       uint64
+	notCommitted uint64
+	latencyTotal time.Duration
+	latencyMax   time.Duration
+}
+
+// NewTransactionEngine creates a TransactionEngine for the senders.
+//
+// Accepts options:
+//   - [EngineWorkers]
+//   - [FeePayerAccount]
+//   - [MaxInFlight] and [PollPeriod] for the [SequenceNumberManager] of each sender
+//   - [MaxGasAmount], [GasUnitPrice], [ExpirationSeconds], and [ChainIdOption] to build transactions
+//   - [PollTimeout] and [RequireSuccess] to wait for transactions, see [NodeClient.PollForTransaction]
+func NewTransactionEngine(rc *NodeClient, senders []TransactionSigner, options ...any) (*TransactionEngine, error) {
+	if len(senders) == 0 {
+		return nil, errors.New("NewTransactionEngine needs at least one sender")
+	}
+	engine := &TransactionEngine{
+		rc:      rc,
+		workers: 16,
+	}
+	managerOptions := make([]any, 0)
+	for i, option := range options {
+		switch value := option.(type) {
+		case EngineWorkers:
+			engine.workers = max(int(value), 1)
+		case FeePayerAccount:
+			engine.feePayer = value.Signer
+		case MaxInFlight:
+			managerOptions = append(managerOptions, value)
+		case PollPeriod:
+			managerOptions = append(managerOptions, value)
+			engine.waitOptions = append(engine.waitOptions, value)
+		case MaxGasAmount, GasUnitPrice, ExpirationSeconds, ChainIdOption:
+			engine.buildOptions = append(engine.buildOptions, value)
+		case PollTimeout, RequireSuccess:
+			engine.waitOptions = append(engine.waitOptions, value)
+		default:
+			return nil, fmt.Errorf("NewTransactionEngine arg [%d] unknown option type %T", i+3, option)
+		}
+	}
+	for _, signer := range senders {
+		manager, err := NewSequenceNumberManager(rc, signer.AccountAddress(), managerOptions...)
+		if err != nil {
+			return nil, err
+		}
+		engine.senders = append(engine.senders, &engineSender{signer: signer, manager: manager})
+	}
+	return engine, nil
+}
+
+// Run sends the payloads, yields a result per payload as it finishes.  Only [TransactionSubmissionTypeSingle] payloads
+// are supported, sent as fee payer transactions if there's a [FeePayerAccount].  Closes output chan `results` when
+// input chan `payloads` is closed, and every transaction is done.
+func (engine *TransactionEngine) Run(payloads chan TransactionBuildPayload, results chan TransactionEngineResult) {
+	defer close(results)
+	engine.stats.mu.Lock()
+	engine.stats.start = time.Now()
+	engine.stats.mu.Unlock()
+
+	// Workers build, sign, and submit, handing off the waiting so they can move on
+	waits := sync.WaitGroup{}
+	workers := sync.WaitGroup{}
+	for range engine.workers {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for payload := range payloads {
+				engine.send(payload, results, &waits)
+			}
+		}()
+	}
+	workers.Wait()
+	waits.Wait()
+}
+
+// Stats is a snapshot of the work so far
+func (engine *TransactionEngine) Stats() TransactionEngineStats {
+	stats := &engine.stats
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	out := TransactionEngineStats{
+		Payloads:     stats.payloads,
+		Submitted:    stats.submitted,
+		Committed:    stats.committed,
+		Failed:       stats.failed,
+		NotCommitted: stats.notCommitted,
+		LatencyMax:   stats.latencyMax,
+	}
+	if !stats.start.IsZero() {
+		out.Elapsed = time.Since(stats.start)
+	}
+	if out.Elapsed > 0 {
+		out.Throughput = float64(stats.committed+stats.failed) / out.Elapsed.Seconds()
+	}
+	if done := stats.committed + stats.failed + stats.notCommitted; done > 0 {
+		out.LatencyMean = stats.latencyTotal / time.Duration(done)
+	}
+	return out
+}
+
+// send builds, signs, and submits a payload, retrying if its sequence number is rejected, and then waits for it in
+// the background
+func (engine *TransactionEngine) send(payload TransactionBuildPayload, results chan TransactionEngineResult, waits *sync.WaitGroup) {
+	start := time.Now()
+	engine.count(func(stats *engineStats) { stats.payloads++ })
+	result := TransactionEngineResult{Id: payload.Id}
+	if payload.Type != TransactionSubmissionTypeSingle {
+		engine.finish(results, result, start, fmt.Errorf("unsupported transaction submission type %d", payload.Type))
+		return
+	}
+
+	sender := engine.pickSender()
+	result.Sender = sender.signer.AccountAddress()
+	for {
+		result.Attempts++
+		sequenceNumber, err := sender.manager.Next()
+		if err != nil {
+			engine.finish(results, result, start, err)
+			return
+		}
+		result.SequenceNumber = sequenceNumber
+
+		signedTxn, err := engine.buildAndSign(sender, payload.Inner, sequenceNumber)
+		if err != nil {
+			// Never submitted, so it's a gap for the next transaction to fill
+			_ = sender.manager.Release(sequenceNumber, err)
+			engine.finish(results, result, start, err)
+			return
+		}
+		sender.manager.Expires(sequenceNumber, signedTxn.Transaction.ExpirationTimestampSeconds)
+		submitted, err := engine.rc.SubmitTransaction(signedTxn)
+		if err != nil {
+			if isRejected(err) {
+				_ = sender.manager.Release(sequenceNumber, err)
+				if (errors.Is(err, ErrSequenceNumberTooOld) || errors.Is(err, ErrSequenceNumberTooNew)) && result.Attempts < engineMaxAttempts {
+					continue
+				}
+			}
+			engine.finish(results, result, start, err)
+			return
+		}
+		engine.count(func(stats *engineStats) { stats.submitted++ })
+
+		waits.Add(1)
+		go func() {
+			defer waits.Done()
+			waitOptions := append([]any{TransactionExpiration(submitted.ExpirationTimestampSecs)}, engine.waitOptions...)
+			userTxn, err := engine.rc.PollForTransaction(submitted.Hash, waitOptions...)
+			result.Result = newTransactionResult(submitted.Hash, userTxn, err)
+			switch result.Result.Status {
+			case TransactionStatusCommitted, TransactionStatusFailed:
+				sender.manager.Committed(sequenceNumber)
+			case TransactionStatusExpired:
+				_ = sender.manager.Release(sequenceNumber, result.Result.Err)
+			default:
+				// It may still commit, the sequence number stays in flight until it's known
+			}
+			engine.finish(results, result, start, nil)
+		}()
+		return
+	}
+}
+
+// pickSender picks the sender with the fewest transactions in flight
+func (engine *TransactionEngine) pickSender() *engineSender {
+	first := int(engine.next.Add(1) % uint64(len(engine.senders)))
+	best := engine.senders[first]
+	bestInFlight := best.manager.InFlight()
+	for i := 1; i < len(engine.senders) && bestInFlight > 0; i++ {
+		sender := engine.senders[(first+i)%len(engine.senders)]
+		if inFlight := sender.manager.InFlight(); inFlight < bestInFlight {
+			best, bestInFlight = sender, inFlight
+		}
+	}
+	return best
+}
+
+// buildAndSign builds the transaction with the sequence number, and signs it by the sender, and the fee payer if any
+func (engine *TransactionEngine) buildAndSign(sender *engineSender, payload TransactionPayload, sequenceNumber uint64) (*SignedTransaction, error) {
+	options := append([]any{SequenceNumber(sequenceNumber)}, engine.buildOptions...)
+	if engine.feePayer == nil {
+		rawTxn, err := engine.rc.BuildTransaction(sender.signer.AccountAddress(), payload, options...)
+		if err != nil {
+			return nil, err
+		}
+		return rawTxn.SignedTransaction(sender.signer)
+	}
+
+	feePayer := engine.feePayer.AccountAddress()
+	rawTxn, err := engine.rc.BuildTransactionMultiAgent(sender.signer.AccountAddress(), payload, append(options, FeePayer(&feePayer))...)
+	if err != nil {
+		return nil, err
+	}
+	senderAuth, err := rawTxn.Sign(sender.signer)
+	if err != nil {
+		return nil, err
+	}
+	feePayerAuth, err := rawTxn.Sign(engine.feePayer)
+	if err != nil {
+		return nil, err
+	}
+	signedTxn, ok := rawTxn.ToFeePayerSignedTransaction(senderAuth, feePayerAuth, []crypto.AccountAuthenticator{})
+	if !ok {
+		return nil, errors.New("failed to build fee payer signed transaction")
+	}
+	return signedTxn, nil
+}
+
+// finish records and yields a result, with the error if it wasn't submitted
+func (engine *TransactionEngine) finish(results chan TransactionEngineResult, result TransactionEngineResult, start time.Time, err error) {
+	if err != nil {
+		result.Result = TransactionResult{Status: TransactionStatusPending, Err: err}
+	}
+	result.Latency = time.Since(start)
+	engine.count(func(stats *engineStats) {
+		switch result.Result.Status {
+		case TransactionStatusCommitted:
+			stats.committed++
+		case TransactionStatusFailed:
+			stats.failed++
+		default:
+			stats.notCommitted++
+		}
+		stats.latencyTotal += result.Latency
+		stats.latencyMax = max(stats.latencyMax, result.Latency)
+	})
+	results <- result
+}
+
+// count updates the stats
+func (engine *TransactionEngine) count(update func(stats *engineStats)) {
+	engine.stats.mu.Lock()
+	defer engine.stats.mu.Unlock()
+	update(&engine.stats)
+}
+
+// NewTransactionEngine creates a [TransactionEngine] for the senders, see [NewTransactionEngine]
+func (client *Client) NewTransactionEngine(senders []TransactionSigner, options ...any) (*TransactionEngine, error) {
+	return NewTransactionEngine(client.nodeClient, senders, options...)
+}
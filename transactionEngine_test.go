@@ -0,0 +1,149 @@
+package endless
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEngineNode accepts transactions, which commit as soon as they're waited for.  The first submission is rejected
+// as too old, if asked.
+type testEngineNode struct {
+	mu             sync.Mutex
+	rejectFirst    bool
+	submissions    int
+	authenticators []TransactionAuthenticatorVariant
+}
+
+func (node *testEngineNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/accounts/"):
+		respondWith(http.StatusOK, `{"sequence_number":"5","authentication_key":["0x01"],"num_signatures_required":1}`, 0)(w)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/transactions":
+		body, _ := io.ReadAll(r.Body)
+		signedTxn := &SignedTransaction{}
+		if err := bcs.Deserialize(signedTxn, body); err != nil {
+			respondWith(http.StatusBadRequest, `{"message":"bad transaction","error_code":"invalid_input"}`, 0)(w)
+			return
+		}
+		node.mu.Lock()
+		node.submissions++
+		reject := node.rejectFirst && node.submissions == 1
+		node.authenticators = append(node.authenticators, signedTxn.Authenticator.Variant)
+		node.mu.Unlock()
+		if reject {
+			respondWith(http.StatusBadRequest, `{"message":"too old","error_code":"vm_error","vm_error_code":3}`, 0)(w)
+			return
+		}
+		hash, _ := signedTxn.Hash()
+		respondWith(http.StatusAccepted, fmt.Sprintf(`{"hash":"%s","sender":"0x1","sequence_number":"%d","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"1000"}`, hash, signedTxn.Transaction.SequenceNumber), 0)(w)
+	default:
+		respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60)(w)
+	}
+}
+
+func testEnginePayloads(t *testing.T, count int, payloadType TransactionSubmissionType) chan TransactionBuildPayload {
+	payload, err := CoinTransferPayload(nil, AccountOne, 1)
+	assert.NoError(t, err)
+	payloads := make(chan TransactionBuildPayload, count)
+	for i := range count {
+		payloads <- TransactionBuildPayload{Id: uint64(i), Type: payloadType, Inner: TransactionPayload{Payload: payload}}
+	}
+	close(payloads)
+	return payloads
+}
+
+func testEngineSenders(t *testing.T, count int) []TransactionSigner {
+	senders := make([]TransactionSigner, count)
+	for i := range senders {
+		sender, err := NewEd25519Account()
+		assert.NoError(t, err)
+		senders[i] = sender
+	}
+	return senders
+}
+
+func TestTransactionEngine(t *testing.T) {
+	node := &testEngineNode{rejectFirst: true}
+	client := newTestNodeClient(t, node)
+	senders := testEngineSenders(t, 2)
+	engine, err := NewTransactionEngine(client, senders,
+		EngineWorkers(4), MaxInFlight(3), GasUnitPrice(100), ChainIdOption(4), PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+
+	results := make(chan TransactionEngineResult)
+	go engine.Run(testEnginePayloads(t, 10, TransactionSubmissionTypeSingle), results)
+
+	ids := make(map[uint64]bool)
+	sequenceNumbers := make(map[string]map[uint64]bool)
+	retried := 0
+	for result := range results {
+		assert.NoError(t, result.Result.Err)
+		assert.Equal(t, TransactionStatusCommitted, result.Result.Status)
+		ids[result.Id] = true
+		sender := result.Sender.String()
+		if sequenceNumbers[sender] == nil {
+			sequenceNumbers[sender] = make(map[uint64]bool)
+		}
+		assert.False(t, sequenceNumbers[sender][result.SequenceNumber], "sequence number used twice")
+		sequenceNumbers[sender][result.SequenceNumber] = true
+		assert.GreaterOrEqual(t, result.SequenceNumber, uint64(5))
+		if result.Attempts > 1 {
+			retried++
+		}
+	}
+	assert.Len(t, ids, 10)
+	// The payloads are spread across the senders, and the rejected one is resubmitted
+	assert.Len(t, sequenceNumbers, 2)
+	assert.Equal(t, 1, retried)
+
+	stats := engine.Stats()
+	assert.Equal(t, uint64(10), stats.Payloads)
+	assert.Equal(t, uint64(10), stats.Submitted)
+	assert.Equal(t, uint64(10), stats.Committed)
+	assert.Zero(t, stats.NotCommitted)
+	assert.Greater(t, stats.Throughput, float64(0))
+	assert.GreaterOrEqual(t, stats.LatencyMax, stats.LatencyMean)
+	for _, sender := range engine.senders {
+		assert.Zero(t, sender.manager.InFlight())
+	}
+}
+
+func TestTransactionEngine_FeePayer(t *testing.T) {
+	node := &testEngineNode{}
+	client := newTestNodeClient(t, node)
+	feePayer := testEngineSenders(t, 1)[0]
+	engine, err := NewTransactionEngine(client, testEngineSenders(t, 1),
+		FeePayerAccount{Signer: feePayer}, GasUnitPrice(100), ChainIdOption(4), PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+
+	results := make(chan TransactionEngineResult)
+	go engine.Run(testEnginePayloads(t, 2, TransactionSubmissionTypeSingle), results)
+	for result := range results {
+		assert.Equal(t, TransactionStatusCommitted, result.Result.Status)
+	}
+	assert.Equal(t, []TransactionAuthenticatorVariant{TransactionAuthenticatorFeePayer, TransactionAuthenticatorFeePayer}, node.authenticators)
+}
+
+func TestTransactionEngine_Errors(t *testing.T) {
+	client := newTestNodeClient(t, &testEngineNode{})
+	_, err := NewTransactionEngine(client, nil)
+	assert.Error(t, err)
+	_, err = NewTransactionEngine(client, testEngineSenders(t, 1), "bad option")
+	assert.ErrorContains(t, err, "unknown option type")
+
+	engine, err := NewTransactionEngine(client, testEngineSenders(t, 1))
+	assert.NoError(t, err)
+	results := make(chan TransactionEngineResult, 1)
+	engine.Run(testEnginePayloads(t, 1, TransactionSubmissionTypeMultiAgent), results)
+	result := <-results
+	assert.ErrorContains(t, result.Result.Err, "unsupported")
+	assert.Equal(t, uint64(1), engine.Stats().NotCommitted)
+}
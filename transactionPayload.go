@@ -30,6 +30,14 @@ type TransactionPayload struct {
 	Payload TransactionPayloadImpl
 }
 
+// transactionPayloadEnum has the variants of [TransactionPayload], the deprecated [ModuleBundle] errors when deserialized
+var transactionPayloadEnum = bcs.NewEnum[TransactionPayloadImpl]("TransactionPayload").
+	Register(uint32(TransactionPayloadVariantScript), func() TransactionPayloadImpl { return &Script{} }).
+	Register(uint32(TransactionPayloadVariantModuleBundle), func() TransactionPayloadImpl { return &ModuleBundle{} }).
+	Register(uint32(TransactionPayloadVariantEntryFunction), func() TransactionPayloadImpl { return &EntryFunction{} }).
+	Register(uint32(TransactionPayloadVariantMultisig), func() TransactionPayloadImpl { return &Multisig{} }).
+	Register(uint32(TransactionPayloadVariantSafeEntryFunction), func() TransactionPayloadImpl { return &SafeEntryFunction{} })
+
 //region TransactionPayload bcs.Struct
 
 func (txn *TransactionPayload) MarshalBCS(ser *bcs.Serializer) {
@@ -37,31 +45,10 @@ func (txn *TransactionPayload) MarshalBCS(ser *bcs.Serializer) {
 		ser.SetError(fmt.Errorf("nil transaction payload"))
 		return
 	}
-	ser.Uleb128(uint32(txn.Payload.PayloadType()))
-	txn.Payload.MarshalBCS(ser)
+	transactionPayloadEnum.Marshal(ser, uint32(txn.Payload.PayloadType()), txn.Payload)
 }
 func (txn *TransactionPayload) UnmarshalBCS(des *bcs.Deserializer) {
-	payloadType := TransactionPayloadVariant(des.Uleb128())
-
-	switch payloadType {
-	case TransactionPayloadVariantScript:
-		txn.Payload = &Script{}
-	case TransactionPayloadVariantModuleBundle:
-		// Deprecated, should never be in production
-		des.SetError(fmt.Errorf("module bundle is not supported as a transaction payload"))
-		return
-	case TransactionPayloadVariantEntryFunction:
-		txn.Payload = &EntryFunction{}
-	case TransactionPayloadVariantMultisig:
-		txn.Payload = &Multisig{}
-	case TransactionPayloadVariantSafeEntryFunction:
-		txn.Payload = &SafeEntryFunction{}
-	default:
-		des.SetError(fmt.Errorf("bad txn payload kind, %d", payloadType))
-		return
-	}
-
-	txn.Payload.UnmarshalBCS(des)
+	_, txn.Payload = transactionPayloadEnum.Unmarshal(des)
 }
 
 //endregion
@@ -80,7 +67,8 @@ func (txn *ModuleBundle) MarshalBCS(ser *bcs.Serializer) {
 	ser.SetError(errors.New("ModuleBundle unimplemented"))
 }
 func (txn *ModuleBundle) UnmarshalBCS(des *bcs.Deserializer) {
-	des.SetError(errors.New("ModuleBundle unimplemented"))
+	// Deprecated, should never be in production
+	des.SetError(errors.New("module bundle is not supported as a transaction payload"))
 }
 
 //endregion ModuleBundle
@@ -118,11 +106,9 @@ func (sf *EntryFunction) UnmarshalBCS(des *bcs.Deserializer) {
 	sf.Module.UnmarshalBCS(des)
 	sf.Function = des.ReadString()
 	sf.ArgTypes = bcs.DeserializeSequence[TypeTag](des)
-	alen := des.Uleb128()
-	sf.Args = make([][]byte, alen)
-	for i := range alen {
-		sf.Args[i] = des.ReadBytes()
-	}
+	sf.Args = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, arg *[]byte) {
+		*arg = des.ReadBytes()
+	})
 }
 
 //endregion
@@ -154,11 +140,9 @@ func (sf *SafeEntryFunction) UnmarshalBCS(des *bcs.Deserializer) {
 	sf.Module.UnmarshalBCS(des)
 	sf.Function = des.ReadString()
 	sf.ArgTypes = bcs.DeserializeSequence[TypeTag](des)
-	alen := des.Uleb128()
-	sf.Args = make([][]byte, alen)
-	for i := range alen {
-		sf.Args[i] = des.ReadBytes()
-	}
+	sf.Args = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, arg *[]byte) {
+		*arg = des.ReadBytes()
+	})
 	sf.Hash = [32]byte(des.ReadFixedBytes(32))
 }
 
@@ -2,8 +2,10 @@ package endless
 
 import (
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/endless-labs/endless-go-sdk/api"
 )
@@ -41,6 +43,9 @@ type TransactionSubmissionResponse struct {
 	Err      error
 }
 
+// SequenceNumberTracker is a bare counter of sequence numbers.
+//
+// Deprecated: Use [SequenceNumberManager], which also reconciles with the account's sequence number on chain.
 type SequenceNumberTracker struct {
 	SequenceNumber atomic.Uint64
 }
@@ -66,22 +71,43 @@ func (client *Client) BuildTransactions(sender AccountAddress, payloads chan Tra
 }
 
 // BuildTransactions start a goroutine to process [TransactionPayload] and spit out [RawTransactionImpl].
+//
+// Sequence numbers are allocated by a [SequenceNumberManager].  Transactions in flight are only capped with the
+// option [MaxInFlight], as nothing here learns when they commit.  With it, building waits for the account's sequence
+// number on chain to catch up.  A sequence number sent to setSequenceNumber resets it.
 func (rc *NodeClient) BuildTransactions(sender AccountAddress, payloads chan TransactionBuildPayload, responses chan TransactionBuildResponse, setSequenceNumber chan uint64, options ...any) {
-	// Initialize state
-	account, err := rc.Account(sender)
+	manager, options, err := rc.newBuildSequenceNumberManager(sender, options)
 	if err != nil {
 		responses <- TransactionBuildResponse{Err: err}
 		close(responses)
 		return
 	}
-	sequenceNumber, err := account.SequenceNumber()
-	if err != nil {
+	rc.buildTransactions(manager, sender, payloads, responses, setSequenceNumber, options...)
+}
+
+// newBuildSequenceNumberManager creates the [SequenceNumberManager] for building transactions, taking its options
+// out of the build options.  It's uncapped unless [MaxInFlight] is given.
+func (rc *NodeClient) newBuildSequenceNumberManager(sender AccountAddress, options []any) (*SequenceNumberManager, []any, error) {
+	managerOptions := []any{MaxInFlight(math.MaxInt)}
+	buildOptions := make([]any, 0, len(options)+1)
+	for _, option := range options {
+		if _, ok := option.(MaxInFlight); ok {
+			managerOptions = append(managerOptions, option)
+		} else {
+			buildOptions = append(buildOptions, option)
+		}
+	}
+	manager, err := NewSequenceNumberManager(rc, sender, managerOptions...)
+	return manager, buildOptions, err
+}
+
+func (rc *NodeClient) buildTransactions(manager *SequenceNumberManager, sender AccountAddress, payloads chan TransactionBuildPayload, responses chan TransactionBuildResponse, setSequenceNumber chan uint64, options ...any) {
+	// Initialize state
+	if err := manager.Resync(); err != nil {
 		responses <- TransactionBuildResponse{Err: err}
 		close(responses)
 		return
 	}
-	snt := &SequenceNumberTracker{}
-	snt.SequenceNumber.Store(sequenceNumber)
 	optionsLast := len(options)
 	options = append(options, SequenceNumber(0))
 
@@ -93,32 +119,39 @@ func (rc *NodeClient) BuildTransactions(sender AccountAddress, payloads chan Tra
 				close(responses)
 				return
 			}
-			switch payload.Type {
-			case TransactionSubmissionTypeSingle:
-				curSequenceNumber := snt.Increment()
-				options[optionsLast] = SequenceNumber(curSequenceNumber)
-				txnResponse, err := rc.BuildTransaction(sender, payload.Inner, options...)
-				if err != nil {
-					responses <- TransactionBuildResponse{Err: err}
-				} else {
-					responses <- TransactionBuildResponse{Response: txnResponse}
-				}
-			case TransactionSubmissionTypeMultiAgent:
-				curSequenceNumber := snt.Increment()
-				options[optionsLast] = SequenceNumber(curSequenceNumber)
-				txnResponse, err := rc.BuildTransactionMultiAgent(sender, payload.Inner, options...)
-				if err != nil {
-					responses <- TransactionBuildResponse{Err: err}
-				} else {
-					responses <- TransactionBuildResponse{Response: txnResponse}
-				}
-			default:
+			if payload.Type != TransactionSubmissionTypeSingle && payload.Type != TransactionSubmissionTypeMultiAgent {
 				// Skip the payload
+				continue
+			}
+			curSequenceNumber, err := manager.Next()
+			if err != nil {
+				responses <- TransactionBuildResponse{Id: payload.Id, Err: err}
+				continue
+			}
+			options[optionsLast] = SequenceNumber(curSequenceNumber)
+			var txnResponse RawTransactionImpl
+			if payload.Type == TransactionSubmissionTypeSingle {
+				txnResponse, err = rc.BuildTransaction(sender, payload.Inner, options...)
+			} else {
+				txnResponse, err = rc.BuildTransactionMultiAgent(sender, payload.Inner, options...)
+			}
+			if err != nil {
+				// Never submitted, so it's a gap for the next transaction to fill
+				_ = manager.Release(curSequenceNumber, err)
+				responses <- TransactionBuildResponse{Id: payload.Id, Err: err}
+			} else {
+				if rawTxn, ok := innerRawTransaction(txnResponse); ok {
+					manager.Expires(curSequenceNumber, rawTxn.ExpirationTimestampSeconds)
+				}
+				responses <- TransactionBuildResponse{Id: payload.Id, Response: txnResponse}
 			}
+		case <-rc.Context().Done():
+			responses <- TransactionBuildResponse{Err: rc.Context().Err()}
+			close(responses)
+			return
 		case newSequenceNumber := <-setSequenceNumber:
 			// This can be used to update the sequence number at anytime
-			snt.Update(newSequenceNumber)
-			// TODO: We should periodically handle reconciliation of the sequence numbers, but this needs to know submission as well
+			manager.Reset(newSequenceNumber)
 		}
 	}
 }
@@ -132,10 +165,18 @@ func (client *Client) SubmitTransactions(requests chan TransactionSubmissionRequ
 // SubmitTransactions consumes signed transactions, submits to endless-node, yields responses.
 // closes output chan `responses` when input chan `signedTxns` is closed.
 func (rc *NodeClient) SubmitTransactions(requests chan TransactionSubmissionRequest, responses chan TransactionSubmissionResponse) {
+	rc.submitTransactions(requests, responses, nil)
+}
+
+// submitTransactions is [NodeClient.SubmitTransactions], calling onError if it's set before responding with an error
+func (rc *NodeClient) submitTransactions(requests chan TransactionSubmissionRequest, responses chan TransactionSubmissionResponse, onError func(request TransactionSubmissionRequest, err error)) {
 	defer close(responses)
 	for request := range requests {
 		response, err := rc.SubmitTransaction(request.SignedTxn)
 		if err != nil {
+			if onError != nil {
+				onError(request, err)
+			}
 			responses <- TransactionSubmissionResponse{Id: request.Id, Err: err}
 		} else {
 			responses <- TransactionSubmissionResponse{Id: request.Id, Response: response}
@@ -143,52 +184,133 @@ func (rc *NodeClient) SubmitTransactions(requests chan TransactionSubmissionRequ
 	}
 }
 
-// BatchSubmitTransactions consumes signed transactions, submits to endless-node, yields responses.
-// closes output chan `responses` when input chan `signedTxns` is closed.
-func (rc *NodeClient) BatchSubmitTransactions(requests chan TransactionSubmissionRequest, responses chan TransactionSubmissionResponse) {
+// BatchSize is an option to BatchSubmitTransactions, the most transactions submitted in one request, 20 by default
+type BatchSize int
+
+// BatchLinger is an option to BatchSubmitTransactions, the longest a transaction waits for its batch to fill before
+// the batch is submitted anyway, 50ms by default
+type BatchLinger time.Duration
+
+// BatchSubmitError is a transaction the node rejected in a batch submission.  It matches the node's errors with
+// [errors.Is], like [ErrSequenceNumberTooOld].
+type BatchSubmitError struct {
+	Index    uint32     // Index is the index of the transaction in its batch
+	ApiError *api.Error // ApiError is why the node rejected it
+}
+
+// Error returns the node's error
+func (e *BatchSubmitError) Error() string {
+	return fmt.Sprintf("batch transaction %d rejected: %s", e.Index, e.ApiError.Error())
+}
+
+// Unwrap returns the node's [*api.Error], and the sentinel errors it corresponds to
+func (e *BatchSubmitError) Unwrap() []error {
+	return append([]error{e.ApiError}, apiErrors(e.ApiError)...)
+}
+
+// BatchSubmitTransactions consumes signed transactions, submits them to endless-node in batches, yields a response
+// per request.  The response to a transaction that was submitted has its hash, and can be fed to
+// [NodeClient.WaitForSubmissions].  A transaction the node rejected has a [*BatchSubmitError].
+// Closes output chan `responses` when input chan `requests` is closed, after submitting what's left.
+//
+// Accepts options:
+//   - [BatchSize]
+//   - [BatchLinger]
+func (client *Client) BatchSubmitTransactions(requests chan TransactionSubmissionRequest, responses chan TransactionSubmissionResponse, options ...any) {
+	client.nodeClient.BatchSubmitTransactions(requests, responses, options...)
+}
+
+// BatchSubmitTransactions consumes signed transactions, submits them to endless-node in batches, yields a response
+// per request.  The response to a transaction that was submitted has its hash, and can be fed to
+// [NodeClient.WaitForSubmissions].  A transaction the node rejected has a [*BatchSubmitError].
+// Closes output chan `responses` when input chan `requests` is closed, after submitting what's left.
+//
+// A batch is submitted once it has [BatchSize] transactions, or [BatchLinger] after its first transaction.
+//
+//	go client.BatchSubmitTransactions(requests, submissions, BatchSize(50), BatchLinger(10*time.Millisecond))
+//	go client.WaitForSubmissions(submissions, results)
+func (rc *NodeClient) BatchSubmitTransactions(requests chan TransactionSubmissionRequest, responses chan TransactionSubmissionResponse, options ...any) {
 	defer close(responses)
 
-	inputs := make([]*SignedTransaction, 20)
-	ids := make([]uint64, 20)
-	i := uint32(0)
+	batchSize := 20
+	linger := 50 * time.Millisecond
+	for i, option := range options {
+		switch value := option.(type) {
+		case BatchSize:
+			batchSize = max(int(value), 1)
+		case BatchLinger:
+			linger = time.Duration(value)
+		default:
+			// Fail every request, rather than leave the sender blocked
+			err := fmt.Errorf("BatchSubmitTransactions arg [%d] unknown option type %T", i+3, option)
+			for request := range requests {
+				responses <- TransactionSubmissionResponse{Id: request.Id, Err: err}
+			}
+			return
+		}
+	}
 
-	for request := range requests {
-		// Collect 20 inputs before submitting
-		// TODO: Handle a timeout or something associated for it
-		inputs[i] = request.SignedTxn
-		ids[i] = request.Id
+	batch := make([]TransactionSubmissionRequest, 0, batchSize)
+	timer := time.NewTimer(linger)
+	timer.Stop()
+	defer timer.Stop()
+	flush := func() {
+		timer.Stop()
+		if len(batch) > 0 {
+			rc.submitBatch(batch, responses)
+			batch = batch[:0]
+		}
+	}
 
-		if i >= 19 {
-			i = 0
-			response, err := rc.BatchSubmitTransaction(inputs)
+	for {
+		select {
+		case request, ok := <-requests:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, request)
+			if len(batch) >= batchSize {
+				flush()
+			} else if len(batch) == 1 {
+				timer.Reset(linger)
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
 
-			// Process the responses
-			if err != nil {
-				// Error, means all failed
-				for j := uint32(0); j < i; j++ {
-					responses <- TransactionSubmissionResponse{Id: ids[j], Err: err}
-				}
-			} else {
-				// Partial failure, means we need to send errors for those that failed
-				// and responses for those that succeeded
-
-				for j := uint32(0); j < i; j++ {
-					failed := -1
-					for k := 0; k < len(response.TransactionFailures); k++ {
-						if response.TransactionFailures[k].TransactionIndex == j {
-							failed = k
-							break
-						}
-					}
-					if failed >= 0 {
-						responses <- TransactionSubmissionResponse{Id: ids[j], Response: nil}
-					} else {
-						responses <- TransactionSubmissionResponse{Id: ids[j], Err: fmt.Errorf("transaction failed: %s", response.TransactionFailures[failed].Error.Message)}
-					}
-				}
+// submitBatch submits a batch of transactions, responding for each in order
+func (rc *NodeClient) submitBatch(batch []TransactionSubmissionRequest, responses chan TransactionSubmissionResponse) {
+	signedTxns := make([]*SignedTransaction, len(batch))
+	for i, request := range batch {
+		signedTxns[i] = request.SignedTxn
+	}
+	response, resent, err := rc.batchSubmitTransaction(signedTxns)
+	if err != nil {
+		// The whole batch failed
+		for _, request := range batch {
+			responses <- TransactionSubmissionResponse{Id: request.Id, Err: err}
+		}
+		return
+	}
+
+	failures := make(map[uint32]*api.Error, len(response.TransactionFailures))
+	for _, failure := range response.TransactionFailures {
+		failures[failure.TransactionIndex] = &failure.Error
+	}
+	for i, request := range batch {
+		if apiErr, ok := failures[uint32(i)]; ok {
+			err := &BatchSubmitError{Index: uint32(i), ApiError: apiErr}
+			if !resent || !isAlreadyInMempool(err) {
+				responses <- TransactionSubmissionResponse{Id: request.Id, Err: err}
+				continue
 			}
+			// An earlier attempt made it to the mempool, so the submission has succeeded
 		}
-		i++
+		submitted, err := pendingTransactionFromSigned(request.SignedTxn)
+		responses <- TransactionSubmissionResponse{Id: request.Id, Response: submitted, Err: err}
 	}
 }
 
@@ -205,6 +327,8 @@ func (client *Client) BuildSignAndSubmitTransactions(
 
 // BuildSignAndSubmitTransactions starts up a goroutine to process transactions for a single [TransactionSender]
 // Closes output chan `responses` on completion of input chan `payloads`.
+//
+// Transactions in flight are uncapped unless the option [MaxInFlight] is given, see [NodeClient.BuildTransactions].
 func (rc *NodeClient) BuildSignAndSubmitTransactions(
 	sender TransactionSigner,
 	payloads chan TransactionBuildPayload,
@@ -304,6 +428,8 @@ func (client *Client) BuildSignAndSubmitTransactionsWithSignFunction(
 //
 // Closes output chan `responses` on completion of input chan `payloads`.
 //
+// Transactions in flight are uncapped unless the option [MaxInFlight] is given, see [NodeClient.BuildTransactions].
+//
 // This enables the ability to do fee payer, and other approaches while staying concurrent
 //
 //	func Example() {
@@ -355,15 +481,27 @@ func (rc *NodeClient) BuildSignAndSubmitTransactionsWithSignFunction(
 ) {
 	// TODO: Make internal buffer size configurable with an optional parameter
 
+	manager, buildOptions, err := rc.newBuildSequenceNumberManager(sender, buildOptions)
+	if err != nil {
+		responses <- TransactionSubmissionResponse{Err: err}
+		close(responses)
+		return
+	}
+
 	// Set up the channel handling building transactions
 	buildResponses := make(chan TransactionBuildResponse, 20)
 	setSequenceNumber := make(chan uint64)
-	go rc.BuildTransactions(sender, payloads, buildResponses, setSequenceNumber, buildOptions...)
+	go rc.buildTransactions(manager, sender, payloads, buildResponses, setSequenceNumber, buildOptions...)
 
 	submissionRequests := make(chan TransactionSubmissionRequest, 20)
 	// Note that, I change this to BatchSubmitTransactions, and it caused no change in performance.  The non-batched
 	// version is more flexible and gives actual responses.  It is may be that with large payloads that batch more performant.
-	go rc.SubmitTransactions(submissionRequests, responses)
+	go rc.submitTransactions(submissionRequests, responses, func(request TransactionSubmissionRequest, err error) {
+		// Rejected transactions leave a gap for the next transaction to fill
+		if isRejected(err) {
+			_ = manager.Release(request.SignedTxn.Transaction.SequenceNumber, err)
+		}
+	})
 
 	var wg sync.WaitGroup
 
@@ -377,6 +515,9 @@ func (rc *NodeClient) BuildSignAndSubmitTransactionsWithSignFunction(
 				defer wg.Done()
 				signedTxn, err := sign(buildResponse.Response)
 				if err != nil {
+					if rawTxn, ok := innerRawTransaction(buildResponse.Response); ok {
+						_ = manager.Release(rawTxn.SequenceNumber, err)
+					}
 					responses <- TransactionSubmissionResponse{Id: buildResponse.Id, Err: err}
 				} else {
 					submissionRequests <- TransactionSubmissionRequest{
@@ -391,3 +532,19 @@ func (rc *NodeClient) BuildSignAndSubmitTransactionsWithSignFunction(
 	wg.Wait()
 	close(submissionRequests)
 }
+
+// innerRawTransaction is the [RawTransaction] of a built transaction
+func innerRawTransaction(rawTxn RawTransactionImpl) (*RawTransaction, bool) {
+	switch rawTxn := rawTxn.(type) {
+	case *RawTransaction:
+		return rawTxn, true
+	case *RawTransactionWithData:
+		switch inner := rawTxn.Inner.(type) {
+		case *MultiAgentRawTransactionWithData:
+			return inner.RawTxn, true
+		case *MultiAgentWithFeePayerRawTransactionWithData:
+			return inner.RawTxn, true
+		}
+	}
+	return nil, false
+}
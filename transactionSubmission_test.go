@@ -0,0 +1,199 @@
+package endless
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+// testBatchNode answers batch submissions, rejecting the transactions at the failing indexes, and records the batch sizes
+type testBatchNode struct {
+	mu      sync.Mutex
+	batches []uint32
+	failing string // failing is the JSON of the transaction failures of every batch
+	status  int
+}
+
+func (node *testBatchNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/transactions/batch") {
+		body, _ := io.ReadAll(r.Body)
+		node.mu.Lock()
+		node.batches = append(node.batches, bcs.NewDeserializer(body).Uleb128())
+		node.mu.Unlock()
+		if node.status != 0 {
+			respondWith(node.status, `{"message":"oops","error_code":"internal_error"}`, 0)(w)
+			return
+		}
+		respondWith(http.StatusAccepted, `{"transaction_failures":[`+node.failing+`]}`, 0)(w)
+		return
+	}
+	respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60)(w)
+}
+
+func TestNodeClient_BatchSubmitTransactions(t *testing.T) {
+	node := &testBatchNode{failing: `{"error":{"message":"too old","error_code":"vm_error","vm_error_code":3},"transaction_index":1}`}
+	client := newTestNodeClient(t, node)
+	signedTxns := testSignedTransactions(t, 0, 5)
+
+	requests := make(chan TransactionSubmissionRequest, len(signedTxns))
+	responses := make(chan TransactionSubmissionResponse, len(signedTxns))
+	for i, signedTxn := range signedTxns {
+		requests <- TransactionSubmissionRequest{Id: uint64(100 + i), SignedTxn: signedTxn}
+	}
+	close(requests)
+	client.BatchSubmitTransactions(requests, responses, BatchSize(3), BatchLinger(time.Hour))
+
+	// The leftovers are submitted on close, and the failure of each batch maps back to its request
+	assert.Equal(t, []uint32{3, 2}, node.batches)
+	byId := make(map[uint64]TransactionSubmissionResponse)
+	for response := range responses {
+		byId[response.Id] = response
+	}
+	assert.Len(t, byId, len(signedTxns))
+	for i, signedTxn := range signedTxns {
+		response := byId[uint64(100+i)]
+		if i == 1 || i == 4 {
+			batchErr := &BatchSubmitError{}
+			assert.ErrorAs(t, response.Err, &batchErr)
+			assert.ErrorIs(t, response.Err, ErrSequenceNumberTooOld)
+			assert.True(t, isRejected(response.Err))
+			assert.Nil(t, response.Response)
+			continue
+		}
+		assert.NoError(t, response.Err)
+		hash, err := signedTxn.Hash()
+		assert.NoError(t, err)
+		assert.Equal(t, hash, response.Response.Hash)
+	}
+}
+
+func TestNodeClient_BatchSubmitTransactions_Linger(t *testing.T) {
+	node := &testBatchNode{}
+	client := newTestNodeClient(t, node)
+	signedTxns := testSignedTransactions(t, 0, 2)
+
+	requests := make(chan TransactionSubmissionRequest)
+	responses := make(chan TransactionSubmissionResponse)
+	go client.BatchSubmitTransactions(requests, responses, BatchSize(10), BatchLinger(5*time.Millisecond))
+
+	// A batch that doesn't fill is submitted anyway
+	for i, signedTxn := range signedTxns {
+		requests <- TransactionSubmissionRequest{Id: uint64(i), SignedTxn: signedTxn}
+	}
+	for range signedTxns {
+		assert.NoError(t, (<-responses).Err)
+	}
+	close(requests)
+	_, ok := <-responses
+	assert.False(t, ok)
+	assert.Equal(t, []uint32{2}, node.batches)
+
+	// The whole batch fails together
+	node.status = http.StatusInternalServerError
+	requests = make(chan TransactionSubmissionRequest, 2)
+	responses = make(chan TransactionSubmissionResponse, 2)
+	for i, signedTxn := range signedTxns {
+		requests <- TransactionSubmissionRequest{Id: uint64(i), SignedTxn: signedTxn}
+	}
+	close(requests)
+	client.BatchSubmitTransactions(requests, responses)
+	for response := range responses {
+		assert.ErrorContains(t, response.Err, "oops")
+	}
+
+	// Bad options fail every request
+	requests = make(chan TransactionSubmissionRequest, 1)
+	responses = make(chan TransactionSubmissionResponse, 1)
+	requests <- TransactionSubmissionRequest{Id: 7, SignedTxn: signedTxns[0]}
+	close(requests)
+	client.BatchSubmitTransactions(requests, responses, "bad option")
+	assert.ErrorContains(t, (<-responses).Err, "unknown option type")
+}
+
+func TestNodeClient_BatchSubmitTransactions_AlreadyInMempool(t *testing.T) {
+	node := &testBatchNode{failing: `{"error":{"message":"Transaction already in mempool","error_code":"transaction_already_in_mempool"},"transaction_index":0}`}
+	var calls atomic.Int32
+	unavailable := false
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 && unavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		node.ServeHTTP(w, r)
+	}))
+	policy := testRetryPolicy()
+	policy.RetrySubmissions = true
+	client.SetRetryPolicy(policy)
+	signedTxns := testSignedTransactions(t, 0, 1)
+	submit := func() TransactionSubmissionResponse {
+		requests := make(chan TransactionSubmissionRequest, 1)
+		responses := make(chan TransactionSubmissionResponse, 1)
+		requests <- TransactionSubmissionRequest{Id: 1, SignedTxn: signedTxns[0]}
+		close(requests)
+		client.BatchSubmitTransactions(requests, responses)
+		return <-responses
+	}
+
+	// Sent once, the transaction was already submitted by someone else
+	assert.ErrorContains(t, submit().Err, "already in mempool")
+
+	// Resent, the first attempt got there
+	calls.Store(0)
+	unavailable = true
+	response := submit()
+	assert.NoError(t, response.Err)
+	hash, err := signedTxns[0].Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, response.Response.Hash)
+}
+
+func TestNodeClient_WaitForSubmissions(t *testing.T) {
+	node := &testBatchNode{failing: `{"error":{"message":"bad signature","error_code":"invalid_signature"},"transaction_index":0}`}
+	client := newTestNodeClient(t, node)
+	signedTxns := testSignedTransactions(t, 0, 3)
+
+	requests := make(chan TransactionSubmissionRequest, len(signedTxns))
+	submissions := make(chan TransactionSubmissionResponse)
+	results := make(chan TransactionWaitResponse)
+	for i, signedTxn := range signedTxns {
+		requests <- TransactionSubmissionRequest{Id: uint64(i), SignedTxn: signedTxn}
+	}
+	close(requests)
+	go client.BatchSubmitTransactions(requests, submissions)
+	go client.WaitForSubmissions(submissions, results, PollPeriod(time.Millisecond), PollWorkers(2))
+
+	byId := make(map[uint64]TransactionResult)
+	for result := range results {
+		byId[result.Id] = result.Result
+	}
+	assert.Len(t, byId, len(signedTxns))
+	assert.Equal(t, TransactionStatusPending, byId[0].Status)
+	assert.ErrorIs(t, byId[0].Err, ErrInvalidSignature)
+	for _, id := range []uint64{1, 2} {
+		assert.Equal(t, TransactionStatusCommitted, byId[id].Status)
+		assert.Equal(t, uint64(77), byId[id].Transaction.Version)
+	}
+}
+
+func TestNodeClient_newBuildSequenceNumberManager(t *testing.T) {
+	client := newTestNodeClient(t, &testBatchNode{})
+
+	// Uncapped unless asked, as building never learns what committed
+	manager, options, err := client.newBuildSequenceNumberManager(AccountOne, []any{GasUnitPrice(100)})
+	assert.NoError(t, err)
+	assert.Equal(t, math.MaxInt, manager.maxInFlight)
+	assert.Equal(t, []any{GasUnitPrice(100)}, options)
+
+	manager, options, err = client.newBuildSequenceNumberManager(AccountOne, []any{MaxInFlight(5), GasUnitPrice(100)})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, manager.maxInFlight)
+	assert.Equal(t, []any{GasUnitPrice(100)}, options)
+}
@@ -0,0 +1,452 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+)
+
+// PollPeriod is an option to PollForTransactions
+type PollPeriod time.Duration
+
+// PollTimeout is an option to PollForTransactions
+type PollTimeout time.Duration
+
+// TransactionExpiration is an option to the transaction waits, the ExpirationTimestampSeconds of the transaction.  It
+// stops waiting once the transaction expires, even if the node never saw it.  Without it, the expiration is learned
+// from the pending transaction.
+type TransactionExpiration uint64
+
+// RequireSuccess is an option to the transaction waits, to error with the VM status of a transaction that committed
+// but failed, as converted by [VmStatusToError]
+//
+//	userTxn, err := client.WaitForTransaction(hash, RequireSuccess(true))
+//	abort := &MoveAbortError{}
+//	if errors.As(err, &abort) {
+//		// userTxn is the failed transaction
+//	}
+type RequireSuccess bool
+
+// transactionPollOptions are the options of the transaction waits
+type transactionPollOptions struct {
+	period         time.Duration
+	timeout        time.Duration
+	expiration     uint64
+	requireSuccess bool
+	workers        int
+}
+
+func getTransactionPollOptions(defaultPeriod, defaultTimeout time.Duration, options ...any) (out transactionPollOptions, err error) {
+	out.period = defaultPeriod
+	out.timeout = defaultTimeout
+	out.workers = 8
+	for i, arg := range options {
+		switch value := arg.(type) {
+		case PollPeriod:
+			out.period = time.Duration(value)
+		case PollTimeout:
+			out.timeout = time.Duration(value)
+		case TransactionExpiration:
+			out.expiration = uint64(value)
+		case RequireSuccess:
+			out.requireSuccess = bool(value)
+		case PollWorkers:
+			out.workers = max(int(value), 1)
+		default:
+			err = fmt.Errorf("PollForTransactions arg %d bad type %T", i+1, arg)
+			return
+		}
+	}
+	return
+}
+
+// WaitForTransaction waits for a transaction to be committed, long-polling the node.  It's the same as
+// [NodeClient.PollForTransaction].
+//
+// Optional arguments:
+//   - PollPeriod: time.Duration, how long to wait between requests for the transaction. Default 100ms.
+//   - PollTimeout: time.Duration, how long to wait for the transaction. Default 10s.
+//   - TransactionExpiration: uint64, when the transaction expires, if it may not have reached the node.
+//   - RequireSuccess: bool, whether a transaction that failed is an error. Default false.
+func (rc *NodeClient) WaitForTransaction(txnHash string, options ...any) (data *api.UserTransaction, err error) {
+	return rc.PollForTransaction(txnHash, options...)
+}
+
+// PollForTransaction waits up to 10 seconds for a transaction to be committed.  It long-polls the node's wait_by_hash
+// endpoint, or polls by hash at 10Hz if the node doesn't have it.  Accepts options [PollPeriod], [PollTimeout],
+// [TransactionExpiration], and [RequireSuccess].
+//
+// It stops early:
+//   - with [ErrTransactionExpired] once the ledger passes the transaction's expiration, as it can't be committed
+//   - with the error from [VmStatusToError] if the transaction failed, and [RequireSuccess] is set
+//   - with the error of any request, other than the transaction not being found yet
+//
+// Not just a degenerate case of PollForTransactions, it may return additional information for the single transaction polled.
+func (rc *NodeClient) PollForTransaction(hash string, options ...any) (*api.UserTransaction, error) {
+	pollOptions, err := getTransactionPollOptions(100*time.Millisecond, 10*time.Second, options...)
+	if err != nil {
+		return nil, err
+	}
+	waiter := newTransactionWaiter(rc, hash, pollOptions, true)
+	defer func() {
+		rc.instrument().TransactionWaitDone(rc.Context(), waiter.wait)
+	}()
+	deadline := waiter.start.Add(pollOptions.timeout)
+	for {
+		userTxn, err := waiter.check()
+		if err != nil || userTxn != nil {
+			return userTxn, err
+		}
+		if time.Now().After(deadline) {
+			return nil, waiter.fail(errors.New("PollForTransaction timeout"))
+		}
+		if err := rc.sleep(pollOptions.period); err != nil {
+			return nil, waiter.fail(err)
+		}
+	}
+}
+
+// PollForTransactions waits up to 10 seconds for transactions to be committed, polling at 10Hz.  The transactions
+// still outstanding are polled concurrently, by up to [PollWorkers] requests at a time.  Accepts the same options as
+// [NodeClient.PollForTransaction], and [PollWorkers].
+//
+// A request that fails, say while the node is unavailable, is retried until the timeout.  A transaction still pending
+// then has the error of its last request joined to the timeout.
+//
+// The results are in the order of the hashes.  The error is nil only if every transaction was committed, and
+// succeeded if [RequireSuccess] is set.  Otherwise, it joins the errors of the others.
+//
+//	results, err := client.PollForTransactions(hashes)
+//	for _, result := range results {
+//		if result.Status == TransactionStatusExpired {
+//			// resubmit
+//		}
+//	}
+func (rc *NodeClient) PollForTransactions(txnHashes []string, options ...any) ([]TransactionResult, error) {
+	pollOptions, err := getTransactionPollOptions(100*time.Millisecond, 10*time.Second, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]TransactionResult, len(txnHashes))
+	waiters := make([]*transactionWaiter, len(txnHashes))
+	outstanding := make([]int, len(txnHashes))
+	for i, hash := range txnHashes {
+		results[i] = TransactionResult{Hash: hash}
+		waiters[i] = newTransactionWaiter(rc, hash, pollOptions, false)
+		outstanding[i] = i
+	}
+	done := func(i int, userTxn *api.UserTransaction, err error) {
+		results[i] = waiters[i].result(userTxn, err)
+		rc.instrument().TransactionWaitDone(rc.Context(), waiters[i].wait)
+	}
+
+	deadline := time.Now().Add(pollOptions.timeout)
+	var stopErr error
+	for len(outstanding) > 0 {
+		if time.Now().After(deadline) {
+			stopErr = errors.New("PollForTransactions timeout")
+			break
+		}
+		if stopErr = rc.sleep(pollOptions.period); stopErr != nil {
+			break
+		}
+
+		// Check everything outstanding at once, with bounded concurrency
+		userTxns := make([]*api.UserTransaction, len(outstanding))
+		errs := make([]error, len(outstanding))
+		indexes := make(chan int)
+		wg := sync.WaitGroup{}
+		for range min(pollOptions.workers, len(outstanding)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range indexes {
+					userTxns[j], errs[j] = waiters[outstanding[j]].check()
+				}
+			}()
+		}
+		for j := range outstanding {
+			indexes <- j
+		}
+		close(indexes)
+		wg.Wait()
+
+		pending := outstanding[:0]
+		for j, i := range outstanding {
+			if userTxns[j] == nil && (errs[j] == nil || waiters[i].requestErr != nil) {
+				// Still pending, or the node may answer next time
+				pending = append(pending, i)
+				continue
+			}
+			done(i, userTxns[j], errs[j])
+		}
+		outstanding = pending
+	}
+	// Anything left over was not committed
+	for _, i := range outstanding {
+		done(i, nil, waiters[i].fail(errors.Join(stopErr, waiters[i].requestErr)))
+	}
+
+	failures := make([]error, 0)
+	for _, result := range results {
+		if result.Status != TransactionStatusCommitted && (result.Status != TransactionStatusFailed || pollOptions.requireSuccess) {
+			failures = append(failures, fmt.Errorf("transaction %s %s: %w", result.Hash, result.Status, result.Err))
+		}
+	}
+	if len(failures) > 0 {
+		return results, fmt.Errorf("%d of %d transactions not committed: %w", len(failures), len(results), errors.Join(failures...))
+	}
+	return results, nil
+}
+
+// TransactionWaitResponse is the outcome of waiting for a transaction submitted with [NodeClient.SubmitTransactions]
+// or [NodeClient.BatchSubmitTransactions]
+type TransactionWaitResponse struct {
+	Id     uint64            // Id of the submission request
+	Result TransactionResult // Result of waiting, pending with the submission's error if it wasn't submitted
+}
+
+// WaitForSubmissions consumes the responses of submitting transactions, waits for each submitted transaction, yields
+// the results as they finish.  Up to [PollWorkers] transactions are waited for at once, each for up to the
+// [PollTimeout], stopping early once it expires.
+// Closes output chan `results` when input chan `submissions` is closed, and every transaction is done.
+//
+// Accepts the same options as [NodeClient.PollForTransaction], and [PollWorkers].
+//
+//	go client.BatchSubmitTransactions(requests, submissions)
+//	go client.WaitForSubmissions(submissions, results, RequireSuccess(true))
+//	for result := range results {
+//		if result.Result.Err != nil {
+//			// resubmit result.Id
+//		}
+//	}
+func (rc *NodeClient) WaitForSubmissions(submissions chan TransactionSubmissionResponse, results chan TransactionWaitResponse, options ...any) {
+	defer close(results)
+	pollOptions, err := getTransactionPollOptions(100*time.Millisecond, 10*time.Second, options...)
+	if err != nil {
+		for submission := range submissions {
+			results <- TransactionWaitResponse{Id: submission.Id, Result: TransactionResult{Err: err}}
+		}
+		return
+	}
+
+	workers := make(chan struct{}, pollOptions.workers)
+	wg := sync.WaitGroup{}
+	for submission := range submissions {
+		if submission.Err != nil || submission.Response == nil {
+			err := submission.Err
+			if err == nil {
+				err = errors.New("no submitted transaction to wait for")
+			}
+			results <- TransactionWaitResponse{Id: submission.Id, Result: TransactionResult{Err: err}}
+			continue
+		}
+
+		workers <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			hash := submission.Response.Hash
+			waitOptions := append(slices.Clip(options), TransactionExpiration(submission.Response.ExpirationTimestampSecs))
+			userTxn, err := rc.PollForTransaction(hash, waitOptions...)
+			results <- TransactionWaitResponse{Id: submission.Id, Result: newTransactionResult(hash, userTxn, err)}
+		}()
+	}
+	wg.Wait()
+}
+
+// PollWorkers is an option to PollForTransactions, the most transactions polled at once, 8 by default
+type PollWorkers int
+
+// TransactionStatus is the outcome of waiting for a transaction
+type TransactionStatus uint8
+
+const (
+	TransactionStatusPending   TransactionStatus = iota // TransactionStatusPending wasn't seen committed before waiting stopped
+	TransactionStatusCommitted                          // TransactionStatusCommitted was committed, and executed successfully
+	TransactionStatusFailed                             // TransactionStatusFailed was committed, but failed with its VM status
+	TransactionStatusExpired                            // TransactionStatusExpired expired without being committed
+)
+
+var transactionStatusNames = map[TransactionStatus]string{
+	TransactionStatusPending:   "pending",
+	TransactionStatusCommitted: "committed",
+	TransactionStatusFailed:    "failed",
+	TransactionStatusExpired:   "expired",
+}
+
+// String returns the name of the status e.g. "expired"
+func (status TransactionStatus) String() string {
+	if name, ok := transactionStatusNames[status]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(status))
+}
+
+// TransactionResult is the outcome of waiting for one of the transactions of [NodeClient.PollForTransactions]
+type TransactionResult struct {
+	Hash        string               // Hash of the transaction
+	Status      TransactionStatus    // Status is what happened to the transaction
+	Transaction *api.UserTransaction // Transaction is the committed transaction, nil if it wasn't committed
+	Err         error                // Err is why it wasn't committed, or the error from [VmStatusToError] if it failed
+}
+
+// transactionWaiter waits for a single transaction
+type transactionWaiter struct {
+	rc         *NodeClient
+	hash       string
+	options    transactionPollOptions
+	longPoll   bool   // longPoll uses the wait_by_hash endpoint, until the node turns out not to have it
+	expiration uint64 // expiration is when the transaction expires in seconds, 0 if not known yet
+	requestErr error  // requestErr is the error of the last check's request, if it failed
+	start      time.Time
+	wait       TransactionWait
+}
+
+func newTransactionWaiter(rc *NodeClient, hash string, options transactionPollOptions, longPoll bool) *transactionWaiter {
+	return &transactionWaiter{
+		rc:         rc,
+		hash:       hash,
+		options:    options,
+		longPoll:   longPoll,
+		expiration: options.expiration,
+		start:      time.Now(),
+		wait:       TransactionWait{Hash: hash},
+	}
+}
+
+// check asks the node for the transaction once.  It returns the transaction once committed, nil if it's still
+// pending, or the error that waiting stopped for.
+func (waiter *transactionWaiter) check() (*api.UserTransaction, error) {
+	waiter.wait.Attempts++
+	waiter.requestErr = nil
+	txn, header, err := waiter.fetch()
+	switch {
+	case errors.Is(err, ErrTransactionNotFound):
+		// Not on this node yet, it may still be propagating
+	case err != nil:
+		waiter.requestErr = fmt.Errorf("wait for transaction api err: %w", err)
+		return nil, waiter.fail(waiter.requestErr)
+	case txn.Type == api.TransactionVariantPending:
+		if pending, err := txn.PendingTransaction(); err == nil {
+			waiter.expiration = pending.ExpirationTimestampSecs
+		}
+	case txn.Type == api.TransactionVariantUser:
+		userTxn, err := txn.UserTransaction()
+		if err != nil {
+			return nil, waiter.fail(err)
+		}
+		slog.Debug("txn done", "hash", waiter.hash)
+		waiter.wait.committed(userTxn, waiter.start)
+		if waiter.options.requireSuccess && !userTxn.Success {
+			failure := VmStatusToError(userTxn.VmStatus)
+			if failure == nil {
+				failure = &VmStatusError{VmStatus: userTxn.VmStatus}
+			}
+			return userTxn, fmt.Errorf("transaction %s failed: %w", waiter.hash, failure)
+		}
+		return userTxn, nil
+	default:
+		return nil, waiter.fail(fmt.Errorf("transaction %s is a %s, not a user transaction", waiter.hash, txn.Type))
+	}
+
+	// The ledger the node answered at has passed the expiration, so the transaction will never be committed
+	if ledgerTimestamp, ok := ledgerTimestampFromHeader(header); ok && waiter.expiration != 0 && ledgerTimestamp/1_000_000 >= waiter.expiration {
+		return nil, waiter.fail(fmt.Errorf("transaction %s expired at %d, the ledger is at %d: %w", waiter.hash, waiter.expiration, ledgerTimestamp/1_000_000, ErrTransactionExpired))
+	}
+	return nil, nil
+}
+
+// fetch gets the transaction, with the response headers even if it failed
+func (waiter *transactionWaiter) fetch() (*api.Transaction, http.Header, error) {
+	if waiter.longPoll {
+		restUrl := waiter.rc.baseUrl.JoinPath("transactions/wait_by_hash", waiter.hash)
+		txn, header, err := getWithHeader[*api.Transaction](waiter.rc, restUrl.String())
+		if !isUnsupportedEndpoint(err) {
+			return txn, errorHeader(header, err), err
+		}
+		slog.Debug("node doesn't support wait_by_hash, polling instead", "hash", waiter.hash)
+		waiter.longPoll = false
+	}
+	restUrl := waiter.rc.baseUrl.JoinPath("transactions/by_hash", waiter.hash)
+	txn, header, err := getWithHeader[*api.Transaction](waiter.rc, restUrl.String())
+	return txn, errorHeader(header, err), err
+}
+
+// fail records why waiting stopped
+func (waiter *transactionWaiter) fail(err error) error {
+	waiter.wait.Err = err
+	return err
+}
+
+// result is the outcome of waiting, from what check returned last
+func (waiter *transactionWaiter) result(userTxn *api.UserTransaction, err error) TransactionResult {
+	return newTransactionResult(waiter.hash, userTxn, err)
+}
+
+// newTransactionResult is the outcome of waiting for a transaction, from what waiting returned
+func newTransactionResult(hash string, userTxn *api.UserTransaction, err error) TransactionResult {
+	result := TransactionResult{Hash: hash, Transaction: userTxn, Err: err}
+	switch {
+	case userTxn != nil && userTxn.Success:
+		result.Status = TransactionStatusCommitted
+	case userTxn != nil:
+		result.Status = TransactionStatusFailed
+		if result.Err == nil {
+			result.Err = VmStatusToError(userTxn.VmStatus)
+		}
+	case errors.Is(err, ErrTransactionExpired):
+		result.Status = TransactionStatusExpired
+	default:
+		result.Status = TransactionStatusPending
+	}
+	return result
+}
+
+// isUnsupportedEndpoint tells whether the node doesn't have the endpoint at all, rather than the resource
+func isUnsupportedEndpoint(err error) bool {
+	var httpErr *HttpError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	switch httpErr.StatusCode {
+	case http.StatusNotFound:
+		return httpErr.ApiError == nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// errorHeader is the header of a response, or of the [HttpError] if it failed
+func errorHeader(header http.Header, err error) http.Header {
+	var httpErr *HttpError
+	if header == nil && errors.As(err, &httpErr) {
+		return httpErr.Header
+	}
+	return header
+}
+
+// ledgerTimestampFromHeader parses the ledger timestamp in microseconds the node answered at, false if it's not there
+func ledgerTimestampFromHeader(header http.Header) (uint64, bool) {
+	value := header.Get(HeaderLedgerTimestamp)
+	if value == "" {
+		return 0, false
+	}
+	timestamp, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return timestamp, true
+}
@@ -0,0 +1,211 @@
+package endless
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPendingTxnJson = `{"type":"pending_transaction","hash":"0x1234","sender":"0x1","sequence_number":"0","max_gas_amount":"1","gas_unit_price":"1","expiration_timestamp_secs":"100"}`
+
+const testNotFoundJson = `{"message":"Transaction not found by Transaction hash(0x1234)","error_code":"transaction_not_found"}`
+
+// testWaitNode answers with the responses in order, repeating the last, and records the endpoints requested
+type testWaitNode struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	endpoints []string
+}
+
+func newTestWaitNode(t *testing.T, responses ...func(w http.ResponseWriter)) (*NodeClient, *testWaitNode) {
+	node := &testWaitNode{responses: responses}
+	return newTestNodeClient(t, node), node
+}
+
+func (node *testWaitNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	endpoint, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/transactions/"), "/")
+	node.endpoints = append(node.endpoints, endpoint)
+	respond := node.responses[0]
+	if len(node.responses) > 1 {
+		node.responses = node.responses[1:]
+	}
+	node.mu.Unlock()
+	respond(w)
+}
+
+// respondWith answers with the body, at the ledger timestamp in seconds if it's not 0
+func respondWith(status int, body string, ledgerSeconds uint64) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		if ledgerSeconds != 0 {
+			w.Header().Set(HeaderLedgerTimestamp, strconv.FormatUint(ledgerSeconds*1_000_000, 10))
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestNodeClient_WaitForTransaction_LongPoll(t *testing.T) {
+	client, node := newTestWaitNode(t,
+		respondWith(http.StatusOK, testPendingTxnJson, 50),
+		respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60),
+	)
+	userTxn, err := client.WaitForTransaction("0x1234", PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(77), userTxn.Version)
+	assert.Equal(t, []string{"wait_by_hash", "wait_by_hash"}, node.endpoints)
+
+	// Nodes without wait_by_hash are polled by hash
+	client, node = newTestWaitNode(t,
+		respondWith(http.StatusNotFound, "Not Found", 0),
+		respondWith(http.StatusNotFound, testNotFoundJson, 50),
+		respondWith(http.StatusOK, testPendingTxnJson, 50),
+		respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60),
+	)
+	userTxn, err = client.WaitForTransaction("0x1234", PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(77), userTxn.Version)
+	assert.Equal(t, []string{"wait_by_hash", "by_hash", "by_hash", "by_hash"}, node.endpoints)
+}
+
+func TestNodeClient_WaitForTransaction_Expired(t *testing.T) {
+	// The expiration is learned from the pending transaction
+	client, _ := newTestWaitNode(t,
+		respondWith(http.StatusOK, testPendingTxnJson, 99),
+		respondWith(http.StatusNotFound, testNotFoundJson, 100),
+	)
+	start := time.Now()
+	_, err := client.WaitForTransaction("0x1234", PollPeriod(time.Millisecond), PollTimeout(10*time.Second))
+	assert.ErrorIs(t, err, ErrTransactionExpired)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// Or given, if the node never saw the transaction
+	client, _ = newTestWaitNode(t, respondWith(http.StatusNotFound, testNotFoundJson, 100))
+	_, err = client.PollForTransaction("0x1234", PollPeriod(time.Millisecond), TransactionExpiration(100))
+	assert.ErrorIs(t, err, ErrTransactionExpired)
+	results, err := client.PollForTransactions([]string{"0x1234"}, PollPeriod(time.Millisecond), TransactionExpiration(100))
+	assert.ErrorIs(t, err, ErrTransactionExpired)
+	assert.Equal(t, TransactionStatusExpired, results[0].Status)
+
+	// Without knowing the expiration, it waits until the timeout
+	_, err = client.PollForTransaction("0x1234", PollPeriod(time.Millisecond), PollTimeout(10*time.Millisecond))
+	assert.ErrorContains(t, err, "timeout")
+	assert.False(t, errors.Is(err, ErrTransactionExpired))
+}
+
+func TestNodeClient_WaitForTransaction_Failed(t *testing.T) {
+	vmStatus := "Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins"
+	client, _ := newTestWaitNode(t, respondWith(http.StatusOK, testUserTransactionJson(77, 0, false, vmStatus), 60))
+
+	// Failures are only errors if asked for
+	userTxn, err := client.WaitForTransaction("0x1234")
+	assert.NoError(t, err)
+	assert.False(t, userTxn.Success)
+
+	userTxn, err = client.WaitForTransaction("0x1234", RequireSuccess(true))
+	assert.NotNil(t, userTxn)
+	abort := &MoveAbortError{}
+	assert.ErrorAs(t, err, &abort)
+	assert.Equal(t, vmStatus, abort.VmStatus)
+	assert.ErrorIs(t, err, ErrTransactionNotApplied)
+	results, err := client.PollForTransactions([]string{"0x1234"}, PollPeriod(time.Millisecond), RequireSuccess(true))
+	assert.ErrorIs(t, err, ErrTransactionNotApplied)
+	assert.Equal(t, TransactionStatusFailed, results[0].Status)
+	assert.ErrorAs(t, results[0].Err, &abort)
+}
+
+func TestNodeClient_WaitForTransaction_Errors(t *testing.T) {
+	// Errors other than not found stop waiting
+	client, node := newTestWaitNode(t, respondWith(http.StatusInternalServerError, `{"message":"oops","error_code":"internal_error"}`, 0))
+	_, err := client.WaitForTransaction("0x1234", PollPeriod(time.Millisecond))
+	httpErr := &HttpError{}
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusInternalServerError, httpErr.StatusCode)
+	assert.Len(t, node.endpoints, 1)
+
+	_, err = client.WaitForTransaction("0x1234", "bad option")
+	assert.ErrorContains(t, err, "bad type")
+}
+
+func TestNodeClient_PollForTransactions(t *testing.T) {
+	vmStatus := "Move abort in 0x1::coin: EINSUFFICIENT_BALANCE(0x10006): Not enough coins"
+	var active, mostActive atomic.Int32
+	var pendingPolls, failedPolls atomic.Int32
+	client := newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer active.Add(-1)
+		if n := active.Add(1); n > mostActive.Load() {
+			mostActive.Store(n)
+		}
+		time.Sleep(time.Millisecond)
+		switch strings.TrimPrefix(r.URL.Path, "/v1/transactions/by_hash/") {
+		case "0x1":
+			respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60)(w)
+		case "0x2":
+			respondWith(http.StatusOK, testUserTransactionJson(77, 0, false, vmStatus), 60)(w)
+		case "0x3":
+			// Committed once it has been seen pending a few times
+			if pendingPolls.Add(1) < 3 {
+				respondWith(http.StatusOK, testPendingTxnJson, 60)(w)
+			} else {
+				respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60)(w)
+			}
+		case "0x4":
+			respondWith(http.StatusOK, testPendingTxnJson, 100)(w)
+		case "0x5":
+			// Committed once the node recovers
+			if failedPolls.Add(1) < 3 {
+				respondWith(http.StatusInternalServerError, `{"message":"oops","error_code":"internal_error"}`, 0)(w)
+			} else {
+				respondWith(http.StatusOK, testUserTransactionJson(77, 0, true, VmStatusSuccess), 60)(w)
+			}
+		case "0x7":
+			respondWith(http.StatusServiceUnavailable, `{"message":"oops","error_code":"internal_error"}`, 0)(w)
+		default:
+			respondWith(http.StatusNotFound, testNotFoundJson, 60)(w)
+		}
+	}))
+
+	hashes := []string{"0x1", "0x2", "0x3", "0x4", "0x5", "0x6", "0x7"}
+	results, err := client.PollForTransactions(hashes, PollPeriod(time.Millisecond), PollTimeout(200*time.Millisecond), PollWorkers(2))
+	assert.Len(t, results, len(hashes))
+	for i, result := range results {
+		assert.Equal(t, hashes[i], result.Hash)
+	}
+	assert.Equal(t, TransactionStatusCommitted, results[0].Status)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, TransactionStatusFailed, results[1].Status)
+	assert.ErrorIs(t, results[1].Err, ErrTransactionNotApplied)
+	assert.NotNil(t, results[1].Transaction)
+	assert.Equal(t, TransactionStatusCommitted, results[2].Status)
+	assert.Equal(t, uint64(77), results[2].Transaction.Version)
+	assert.Equal(t, TransactionStatusExpired, results[3].Status)
+	assert.ErrorIs(t, results[3].Err, ErrTransactionExpired)
+	assert.Equal(t, TransactionStatusCommitted, results[4].Status)
+	assert.NoError(t, results[4].Err)
+	assert.Equal(t, TransactionStatusPending, results[5].Status)
+	assert.ErrorContains(t, results[5].Err, "timeout")
+	httpErr := &HttpError{}
+	assert.Equal(t, TransactionStatusPending, results[6].Status)
+	assert.ErrorContains(t, results[6].Err, "timeout")
+	assert.ErrorAs(t, results[6].Err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+
+	// Failed transactions aren't an error without RequireSuccess, the others are
+	assert.ErrorContains(t, err, "3 of 7 transactions not committed")
+	assert.False(t, errors.Is(err, ErrTransactionNotApplied))
+	assert.ErrorIs(t, err, ErrTransactionExpired)
+
+	// Requests are concurrent, but bounded
+	assert.LessOrEqual(t, mostActive.Load(), int32(2))
+
+	results, err = client.PollForTransactions([]string{"0x1"}, PollPeriod(time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, "committed", results[0].Status.String())
+}
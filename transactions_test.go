@@ -1,6 +1,8 @@
 package endless
 
 import (
+	"bytes"
+
 	"github.com/endless-labs/endless-go-sdk/bcs"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -59,3 +61,16 @@ func TestTPMarshal(t *testing.T) {
 	// without a payload, it should fail
 	assert.Error(t, ser.Error())
 }
+
+func TestEntryFunctionLimits(t *testing.T) {
+	// An argument count far past the limit errors without allocating it
+	ser := &bcs.Serializer{}
+	ser.Struct(&ModuleId{Address: AccountOne, Name: "coin"})
+	ser.WriteString("transfer")
+	ser.Uleb128(0)
+	ser.Uleb128(200_000_000)
+	des := bcs.NewReaderDeserializer(bytes.NewReader(ser.ToBytes()), bcs.Limits{MaxSequenceLength: 10_000})
+	entryFunction := &EntryFunction{}
+	des.Struct(entryFunction)
+	assert.ErrorContains(t, des.Error(), "exceeds the limit of 10000")
+}
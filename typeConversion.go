@@ -7,10 +7,18 @@ import (
 	"github.com/endless-labs/endless-go-sdk/bcs"
 	"github.com/endless-labs/endless-go-sdk/internal/util"
 	"math/big"
+	"reflect"
 	"strconv"
 )
 
-func EntryFunctionFromAbi(abi any, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any) (*EntryFunction, error) {
+// EntryFunctionFromAbi builds an [EntryFunction] from a module or function ABI, converting simple inputs to BCS encoded
+// ones with [ConvertArg].  Struct arguments are converted with the layouts in the module ABI, and any [StructLayouts]
+// given as options.
+func EntryFunctionFromAbi(abi any, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, options ...any) (*EntryFunction, error) {
+	layouts, err := functionStructLayouts(abi, options)
+	if err != nil {
+		return nil, err
+	}
 	var function *api.MoveFunction
 
 	switch abi := abi.(type) {
@@ -87,7 +95,7 @@ func EntryFunctionFromAbi(abi any, moduleAddress AccountAddress, moduleName stri
 
 	convertedArgs := make([][]byte, len(args))
 	for i, arg := range args {
-		b, err := ConvertArg(argTypes[i], arg, argTypes)
+		b, err := convertArg(argTypes[i], arg, convertedTypeArgs, layouts)
 		if err != nil {
 			return nil, err
 		}
@@ -575,7 +583,23 @@ func ConvertToVector(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, erro
 	}
 }
 
+// ConvertArg BCS encodes a simple input as the given Move type, with generics as the type arguments.  Struct arguments
+// other than 0x1::object::Object, 0x1::string::String, and 0x1::option::Option must be a [bcs.Marshaler], see
+// [ConvertArgWithLayouts] to pass maps or Go structs.
 func ConvertArg(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, error) {
+	return convertArg(typeArg, arg, generics, nil)
+}
+
+// ConvertArgWithLayouts BCS encodes a simple input like [ConvertArg], looking up struct layouts to convert maps or Go
+// structs to struct arguments.  A Go struct's fields are matched to the Move fields by json tag, or by name ignoring
+// case and underscores.
+//
+//	b, err := ConvertArgWithLayouts(keyType, map[string]any{"id": 1, "name": "key"}, nil, client)
+func ConvertArgWithLayouts(typeArg TypeTag, arg any, generics []TypeTag, layouts StructLayouts) ([]byte, error) {
+	return convertArg(typeArg, arg, generics, layouts)
+}
+
+func convertArg(typeArg TypeTag, arg any, generics []TypeTag, layouts StructLayouts) ([]byte, error) {
 	switch innerType := typeArg.Value.(type) {
 	case *U8Tag:
 		num, err := ConvertToU8(arg)
@@ -633,24 +657,39 @@ func ConvertArg(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, error) {
 		}
 
 		tag := generics[genericNum]
-		return ConvertArg(tag, arg, generics)
+		return convertArg(tag, arg, generics, layouts)
 	case *ReferenceTag:
 		// Convert based on inner type
-		return ConvertArg(innerType.TypeParam, arg, generics)
+		return convertArg(innerType.TypeParam, arg, generics, layouts)
 	case *VectorTag:
 		// This has two paths:
 		// 1. Hex strings are allowed for vector<u8>
 		// 2. Otherwise, everything is just parsed as an array of the inner type
 		vecTag := innerType
+		var b []byte
+		var err error
 		switch vecTag.TypeParam.Value.(type) {
 		case *U8Tag:
-			return ConvertToVectorU8(arg)
+			b, err = ConvertToVectorU8(arg)
+		case *GenericTag:
+			// Convert as a vector of the type argument, so vector<T0> is as vector<u8> when T0 is u8
+			itemType, err := substituteGenerics(vecTag.TypeParam, generics)
+			if err != nil {
+				return nil, err
+			}
+			return convertArg(TypeTag{Value: &VectorTag{TypeParam: itemType}}, arg, generics, layouts)
+		case *StructTag, *VectorTag, *ReferenceTag:
+			return convertToVectorOf(vecTag.TypeParam, arg, generics, layouts)
 		default:
-			return ConvertToVector(vecTag.TypeParam, arg, generics)
+			b, err = ConvertToVector(vecTag.TypeParam, arg, generics)
+		}
+		if value := reflect.ValueOf(arg); err != nil && (value.Kind() == reflect.Array || (value.Kind() == reflect.Slice && !value.IsNil())) {
+			// Other slices are converted item by item e.g. []any
+			return convertToVectorOf(vecTag.TypeParam, arg, generics, layouts)
 		}
+		return b, err
 	case *StructTag:
 		structTag := innerType
-		// TODO: We should be able to support custom structs, but for now only support known
 		if AccountOne == structTag.Address {
 			switch structTag.Module {
 			case "object":
@@ -658,7 +697,7 @@ func ConvertArg(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, error) {
 					// TODO: Move to function
 					// Handle as address, inner type doesn't matter
 					// TODO: Improve error message
-					return ConvertArg(TypeTag{&AddressTag{}}, arg, generics)
+					return convertArg(TypeTag{&AddressTag{}}, arg, generics, layouts)
 				}
 			case "string":
 				if structTag.Name == "String" {
@@ -679,13 +718,15 @@ func ConvertArg(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, error) {
 					// Get inner type
 					typeParam := structTag.TypeParams[0]
 
-					// Handle special case of "none", it's a single 0 byte
-					if arg == nil {
+					// Handle special case of "none", it's a single 0 byte, as is a nil pointer e.g. from a Go struct field
+					if value := reflect.ValueOf(arg); arg == nil || (value.Kind() == reflect.Pointer && value.IsNil()) {
 						return bcs.SerializeU8(0)
+					} else if value.Kind() == reflect.Pointer && value.Elem().Kind() != reflect.Struct {
+						arg = value.Elem().Interface()
 					}
 
 					// Otherwise, it's a single byte 1, and the encoded arg
-					b, err := ConvertArg(typeParam, arg, generics)
+					b, err := convertArg(typeParam, arg, generics, layouts)
 					if err != nil {
 						return nil, err
 					}
@@ -693,9 +734,32 @@ func ConvertArg(typeArg TypeTag, arg any, generics []TypeTag) ([]byte, error) {
 				}
 			}
 		}
+		return convertStructArg(structTag, arg, generics, layouts)
 	default:
 		return nil, errors.New("unknown type argument")
 	}
+}
 
-	return nil, errors.New("failed to convert type argument")
+// convertToVectorOf BCS encodes a slice or array of any type as a vector, converting each item as the item type
+func convertToVectorOf(itemType TypeTag, arg any, generics []TypeTag, layouts StructLayouts) ([]byte, error) {
+	value := reflect.ValueOf(arg)
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("invalid input type %T for vector<%s>", arg, itemType.String())
+	}
+	length, err := util.IntToU32(value.Len())
+	if err != nil {
+		return nil, err
+	}
+	buffer, err := bcs.SerializeUleb128(length)
+	if err != nil {
+		return nil, err
+	}
+	for i := range value.Len() {
+		b, err := convertArg(itemType, value.Index(i).Interface(), generics, layouts)
+		if err != nil {
+			return nil, err
+		}
+		buffer = append(buffer, b...)
+	}
+	return buffer, nil
 }
@@ -47,6 +47,20 @@ type TypeTag struct {
 	Value TypeTagImpl
 }
 
+// typeTagEnum has the variants of [TypeTag] used on chain, a [GenericTag] or [ReferenceTag] can't be serialized
+var typeTagEnum = bcs.NewEnum[TypeTagImpl]("TypeTag").
+	Register(uint32(TypeTagBool), func() TypeTagImpl { return &BoolTag{} }).
+	Register(uint32(TypeTagU8), func() TypeTagImpl { return &U8Tag{} }).
+	Register(uint32(TypeTagU64), func() TypeTagImpl { return &U64Tag{} }).
+	Register(uint32(TypeTagU128), func() TypeTagImpl { return &U128Tag{} }).
+	Register(uint32(TypeTagAddress), func() TypeTagImpl { return &AddressTag{} }).
+	Register(uint32(TypeTagSigner), func() TypeTagImpl { return &SignerTag{} }).
+	Register(uint32(TypeTagVector), func() TypeTagImpl { return &VectorTag{} }).
+	Register(uint32(TypeTagStruct), func() TypeTagImpl { return &StructTag{} }).
+	Register(uint32(TypeTagU16), func() TypeTagImpl { return &U16Tag{} }).
+	Register(uint32(TypeTagU32), func() TypeTagImpl { return &U32Tag{} }).
+	Register(uint32(TypeTagU256), func() TypeTagImpl { return &U256Tag{} })
+
 type parseInfo struct {
 	expectedTypes int
 	types         []TypeTag
@@ -314,8 +328,11 @@ func (tt *TypeTag) String() string {
 // Implements:
 //   - [bcs.Marshaler]
 func (tt *TypeTag) MarshalBCS(ser *bcs.Serializer) {
-	ser.Uleb128(uint32(tt.Value.GetType()))
-	ser.Struct(tt.Value)
+	if tt.Value == nil {
+		ser.SetError(errors.New("nil TypeTag"))
+		return
+	}
+	typeTagEnum.Marshal(ser, uint32(tt.Value.GetType()), tt.Value)
 }
 
 // UnmarshalBCS deserializes the TypeTag from bytes
@@ -323,35 +340,7 @@ func (tt *TypeTag) MarshalBCS(ser *bcs.Serializer) {
 // Implements:
 //   - [bcs.Unmarshaler]
 func (tt *TypeTag) UnmarshalBCS(des *bcs.Deserializer) {
-	variant := TypeTagVariant(des.Uleb128())
-	switch variant {
-	case TypeTagAddress:
-		tt.Value = &AddressTag{}
-	case TypeTagSigner:
-		tt.Value = &SignerTag{}
-	case TypeTagBool:
-		tt.Value = &BoolTag{}
-	case TypeTagU8:
-		tt.Value = &U8Tag{}
-	case TypeTagU16:
-		tt.Value = &U16Tag{}
-	case TypeTagU32:
-		tt.Value = &U32Tag{}
-	case TypeTagU64:
-		tt.Value = &U64Tag{}
-	case TypeTagU128:
-		tt.Value = &U128Tag{}
-	case TypeTagU256:
-		tt.Value = &U256Tag{}
-	case TypeTagVector:
-		tt.Value = &VectorTag{}
-	case TypeTagStruct:
-		tt.Value = &StructTag{}
-	default:
-		des.SetError(fmt.Errorf("unknown TypeTag enum %d", variant))
-		return
-	}
-	des.Struct(tt.Value)
+	_, tt.Value = typeTagEnum.Unmarshal(des)
 }
 
 //endregion
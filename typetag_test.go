@@ -88,5 +88,13 @@ func TestInvalidTypeTag(t *testing.T) {
 	bytes := serializer.ToBytes()
 	tag := &TypeTag{}
 	err := bcs.Deserialize(tag, bytes)
+	unknown := &bcs.UnknownVariantError{}
+	assert.ErrorAs(t, err, &unknown)
+	assert.Equal(t, &bcs.UnknownVariantError{Enum: "TypeTag", Variant: 65535}, unknown)
+
+	// Generics can't be serialized
+	_, err = bcs.Serialize(&TypeTag{Value: &GenericTag{Num: 0}})
+	assert.ErrorAs(t, err, &unknown)
+	_, err = bcs.Serialize(&TypeTag{})
 	assert.Error(t, err)
 }
@@ -0,0 +1,294 @@
+package endless
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/internal/util"
+)
+
+// ViewClient calls view functions, it's implemented by [NodeClient] and [Client]
+type ViewClient interface {
+	ModuleAbi(address AccountAddress, moduleName string, ledgerVersion ...uint64) (*api.MoveModule, error)
+	View(payload *ViewPayload, ledgerVersion ...uint64) ([]any, error)
+}
+
+// ViewFunctionFromAbi builds a [ViewPayload] from a module or function ABI, converting simple inputs to BCS encoded
+// ones the same way as [EntryFunctionFromAbi], including struct arguments with any [StructLayouts] given as options.
+// It also returns the function's return types, for decoding the results with [ConvertMoveValue] given the payload's
+// ArgTypes as generics.
+func ViewFunctionFromAbi(abi any, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, options ...any) (*ViewPayload, []TypeTag, error) {
+	layouts, err := functionStructLayouts(abi, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	var function *api.MoveFunction
+	switch abi := abi.(type) {
+	case *api.MoveModule:
+		for _, fun := range abi.ExposedFunctions {
+			if fun.Name == functionName {
+				function = fun
+				break
+			}
+		}
+	case *api.MoveFunction:
+		function = abi
+	default:
+		return nil, nil, fmt.Errorf("unknown abi type: %T", abi)
+	}
+	if function == nil {
+		return nil, nil, fmt.Errorf("view function %s not found in module %s", functionName, moduleName)
+	}
+	if !function.IsView {
+		return nil, nil, fmt.Errorf("function %s is not a view function in module %s", functionName, moduleName)
+	}
+	if len(typeArgs) != len(function.GenericTypeParams) {
+		return nil, nil, fmt.Errorf("view function %s expects %d type arguments, got %d", functionName, len(function.GenericTypeParams), len(typeArgs))
+	}
+	if len(args) != len(function.Params) {
+		return nil, nil, fmt.Errorf("view function %s expects %d arguments, got %d", functionName, len(function.Params), len(args))
+	}
+
+	convertedTypeArgs := make([]TypeTag, len(typeArgs))
+	for i, typeArg := range typeArgs {
+		tag, err := ConvertTypeTag(typeArg)
+		if err != nil {
+			return nil, nil, err
+		}
+		convertedTypeArgs[i] = *tag
+	}
+
+	convertedArgs := make([][]byte, len(args))
+	for i, arg := range args {
+		argType, err := ParseTypeTag(function.Params[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		b, err := convertArg(*argType, arg, convertedTypeArgs, layouts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("view function %s arg %d: %w", functionName, i, err)
+		}
+		convertedArgs[i] = b
+	}
+
+	returnTypes := make([]TypeTag, len(function.Return))
+	for i, typeStr := range function.Return {
+		returnType, err := ParseTypeTag(typeStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		returnTypes[i] = *returnType
+	}
+
+	return &ViewPayload{
+		Module: ModuleId{
+			Address: moduleAddress,
+			Name:    moduleName,
+		},
+		Function: functionName,
+		ArgTypes: convertedTypeArgs,
+		Args:     convertedArgs,
+	}, returnTypes, nil
+}
+
+// ViewWithArgs calls a view function using the on-chain module ABI, converting simple inputs to BCS encoded ones like
+// [NodeClient.EntryFunctionWithArgs], and converting the results to Go values of their return types, see
+// [ConvertMoveValue].
+//
+//	values, err := client.ViewWithArgs(AccountOne, "primary_fungible_store", "balance", []any{"0x1::fungible_asset::Metadata"}, []any{address, metadata})
+//	balance := values[0].(*big.Int)
+//
+// Optionally, a ledgerVersion can be given to call the function at a specific ledger version
+func (rc *NodeClient) ViewWithArgs(moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, ledgerVersion ...uint64) ([]any, error) {
+	return viewWithArgs(rc, moduleAddress, moduleName, functionName, typeArgs, args, ledgerVersion...)
+}
+
+// ViewInto calls a view function like [NodeClient.ViewWithArgs], and decodes the results into a T with
+// [UnmarshalMoveJson].  A function with one return value is decoded into T directly, otherwise T is a slice, or a
+// struct whose exported fields take the return values in order.
+//
+//	balance, err := ViewInto[uint64](client, AccountOne, "coin", "balance", []any{"0x1::endless_coin::EndlessCoin"}, []any{address})
+//
+//	type Supply struct {
+//		Current big.Int
+//		Max     *big.Int
+//	}
+//	supply, err := ViewInto[Supply](client, address, "my_coin", "supply", nil, nil)
+//
+// Optionally, a ledgerVersion can be given to call the function at a specific ledger version
+func ViewInto[T any](client ViewClient, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, ledgerVersion ...uint64) (*T, error) {
+	values, _, _, err := viewJson(client, moduleAddress, moduleName, functionName, typeArgs, args, ledgerVersion...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(T)
+	target := reflect.ValueOf(out).Elem()
+	switch {
+	case len(values) == 1:
+		err = decodeMoveJson(values[0], out)
+	case target.Kind() == reflect.Struct:
+		fields := make([]reflect.Value, 0, target.NumField())
+		for i := range target.NumField() {
+			if target.Type().Field(i).IsExported() {
+				fields = append(fields, target.Field(i))
+			}
+		}
+		if len(fields) != len(values) {
+			return nil, fmt.Errorf("view function %s returned %d values, but %T has %d fields", functionName, len(values), *out, len(fields))
+		}
+		for i, value := range values {
+			if err = decodeMoveJsonValue(value, fields[i], fmt.Sprintf("[%d]", i)); err != nil {
+				break
+			}
+		}
+	default:
+		err = decodeMoveJson(values, out)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode view function %s result: %w", functionName, err)
+	}
+	return out, nil
+}
+
+// viewWithArgs is the shared implementation of [NodeClient.ViewWithArgs] and [Client.ViewWithArgs]
+func viewWithArgs(client ViewClient, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, ledgerVersion ...uint64) ([]any, error) {
+	values, returnTypes, typeTags, err := viewJson(client, moduleAddress, moduleName, functionName, typeArgs, args, ledgerVersion...)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]any, len(values))
+	for i, value := range values {
+		converted[i], err = ConvertMoveValue(returnTypes[i], value, typeTags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert view function %s result %d: %w", functionName, i, err)
+		}
+	}
+	return converted, nil
+}
+
+// viewJson calls the view function, returning its results as JSON, along with their types and the type arguments
+func viewJson(client ViewClient, moduleAddress AccountAddress, moduleName string, functionName string, typeArgs []any, args []any, ledgerVersion ...uint64) (values []any, returnTypes []TypeTag, typeTags []TypeTag, err error) {
+	// The function may differ at the ledger version asked for
+	abi, err := client.ModuleAbi(moduleAddress, moduleName, ledgerVersion...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	options := make([]any, 0, 1)
+	if layouts, ok := client.(StructLayouts); ok {
+		// Structs from other modules are fetched too
+		options = append(options, layouts)
+	}
+	payload, returnTypes, err := ViewFunctionFromAbi(abi, moduleAddress, moduleName, functionName, typeArgs, args, options...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	values, err = client.View(payload, ledgerVersion...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(values) != len(returnTypes) {
+		return nil, nil, nil, fmt.Errorf("view function %s returned %d values, expected %d", functionName, len(values), len(returnTypes))
+	}
+	return values, returnTypes, payload.ArgTypes, nil
+}
+
+// ConvertMoveValue converts a Move value in the JSON form returned by the node to a Go value of its type:
+//   - u8, u16, u32, and u64 become uint8, uint16, uint32, and uint64
+//   - u128 and u256 become *big.Int
+//   - address, signer, and 0x1::object::Object become [AccountAddress]
+//   - vector<u8> becomes []byte, other vectors become []any of their converted items
+//   - 0x1::string::String becomes string
+//   - 0x1::option::Option becomes nil for none, or the converted value
+//
+// Other structs are left in their JSON form, as a map[string]any.  Generics are replaced by the given type arguments.
+func ConvertMoveValue(typeTag TypeTag, value any, generics []TypeTag) (any, error) {
+	switch innerType := typeTag.Value.(type) {
+	case *U8Tag:
+		return ConvertToU8(numberString(value))
+	case *U16Tag:
+		return ConvertToU16(numberString(value))
+	case *U32Tag:
+		return ConvertToU32(numberString(value))
+	case *U64Tag:
+		return ConvertToU64(numberString(value))
+	case *U128Tag, *U256Tag:
+		num, ok := new(big.Int).SetString(numberString(value), 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typeTag.String(), value)
+		}
+		return num, nil
+	case *BoolTag:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid bool value %v", value)
+		}
+		return b, nil
+	case *AddressTag, *SignerTag:
+		address := AccountAddress{}
+		if err := decodeMoveJson(value, &address); err != nil {
+			return nil, err
+		}
+		return address, nil
+	case *GenericTag:
+		if innerType.Num >= uint64(len(generics)) {
+			return nil, errors.New("generic number out of bounds")
+		}
+		return ConvertMoveValue(generics[innerType.Num], value, generics)
+	case *ReferenceTag:
+		return ConvertMoveValue(innerType.TypeParam, value, generics)
+	case *VectorTag:
+		if _, ok := innerType.TypeParam.Value.(*U8Tag); ok {
+			str, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid vector<u8> value %v", value)
+			}
+			return util.ParseHex(str)
+		}
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typeTag.String(), value)
+		}
+		converted := make([]any, len(items))
+		for i, item := range items {
+			convertedItem, err := ConvertMoveValue(innerType.TypeParam, item, generics)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = convertedItem
+		}
+		return converted, nil
+	case *StructTag:
+		if AccountOne == innerType.Address {
+			switch {
+			case innerType.Module == "object" && innerType.Name == "Object":
+				return ConvertMoveValue(TypeTag{&AddressTag{}}, value, generics)
+			case innerType.Module == "string" && innerType.Name == "String":
+				str, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid 0x1::string::String value %v", value)
+				}
+				return str, nil
+			case innerType.Module == "option" && innerType.Name == "Option" && len(innerType.TypeParams) == 1:
+				object, ok := value.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("invalid option value %v", value)
+				}
+				vec, ok := object["vec"].([]any)
+				if !ok || len(vec) > 1 {
+					return nil, fmt.Errorf("invalid option value %v", value)
+				}
+				if len(vec) == 0 {
+					return nil, nil
+				}
+				return ConvertMoveValue(innerType.TypeParams[0], vec[0], generics)
+			}
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unknown type %s", typeTag.String())
+	}
+}
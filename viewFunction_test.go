@@ -0,0 +1,141 @@
+package endless
+
+import (
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/endless-labs/endless-go-sdk/api"
+	"github.com/endless-labs/endless-go-sdk/bcs"
+	"github.com/stretchr/testify/assert"
+)
+
+const testViewModuleJson = `{"bytecode":"0x00","abi":{"address":"0xcafe","name":"registry","friends":[],"structs":[],"exposed_functions":[
+	{"name":"lookup","visibility":"public","is_entry":false,"is_view":true,"generic_type_params":[],"params":["address","u64"],
+		"return":["u64","u128","address","vector<u8>","vector<vector<u64>>","0x1::option::Option<0x1::string::String>","0x1::object::Object<0x1::fungible_asset::Metadata>","0xcafe::registry::Entry"]},
+	{"name":"get","visibility":"public","is_entry":false,"is_view":true,"generic_type_params":[{"constraints":[]}],"params":[],"return":["T0","bool"]},
+	{"name":"register","visibility":"public","is_entry":true,"is_view":false,"generic_type_params":[],"params":["&signer"],"return":[]}
+]}}`
+
+// newTestViewNode serves the registry module at the returned address, and answers every view call with results
+func newTestViewNode(t *testing.T, results string, requests *[]*ViewPayload) (*NodeClient, AccountAddress) {
+	t.Helper()
+	cafe := AccountAddress{}
+	assert.NoError(t, cafe.ParseStringRelaxed("0xcafe"))
+	return newTestNodeClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/accounts/"+cafe.String()+"/module/registry"):
+			_, _ = w.Write([]byte(testViewModuleJson))
+		case strings.HasSuffix(r.URL.Path, "/view"):
+			body, _ := io.ReadAll(r.Body)
+			des := bcs.NewDeserializer(body)
+			payload := &ViewPayload{}
+			payload.Module.UnmarshalBCS(des)
+			payload.Function = des.ReadString()
+			payload.ArgTypes = bcs.DeserializeSequence[TypeTag](des)
+			payload.Args = bcs.DeserializeSequenceWithFunction(des, func(des *bcs.Deserializer, out *[]byte) {
+				*out = des.ReadBytes()
+			})
+			assert.NoError(t, des.Error())
+			*requests = append(*requests, payload)
+			_, _ = w.Write([]byte(results))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})), cafe
+}
+
+func TestNodeClient_ViewWithArgs(t *testing.T) {
+	requests := make([]*ViewPayload, 0)
+	client, cafe := newTestViewNode(t, `["18446744073709551615","340282366920938463463374607431768211455","0x2","0x0102",[["1"],[]],{"vec":["name"]},{"inner":"0x3"},{"id":"4"}]`, &requests)
+
+	values, err := client.ViewWithArgs(cafe, "registry", "lookup", nil, []any{"0x1", 5})
+	assert.NoError(t, err)
+	max128, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+	two, three := AccountAddress{}, AccountAddress{}
+	two[31], three[31] = 2, 3
+	assert.Equal(t, []any{
+		uint64(18446744073709551615),
+		max128,
+		two,
+		[]byte{1, 2},
+		[]any{[]any{uint64(1)}, []any{}},
+		"name",
+		three,
+		map[string]any{"id": "4"},
+	}, values)
+
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "lookup", requests[0].Function)
+	assert.Equal(t, AccountOne[:], requests[0].Args[0])
+	assert.Equal(t, []byte{5, 0, 0, 0, 0, 0, 0, 0}, requests[0].Args[1])
+
+	// Checked against the ABI before calling
+	_, err = client.ViewWithArgs(cafe, "registry", "register", nil, []any{})
+	assert.ErrorContains(t, err, "not a view function")
+	_, err = client.ViewWithArgs(cafe, "registry", "lookup", nil, []any{"0x1"})
+	assert.Error(t, err)
+	_, err = client.ViewWithArgs(cafe, "registry", "lookup", nil, []any{"0x1", "not a number"})
+	assert.Error(t, err)
+	_, err = client.ViewWithArgs(cafe, "registry", "missing", nil, nil)
+	assert.Error(t, err)
+	assert.Len(t, requests, 1)
+}
+
+// testLedgerViewClient records the ledger versions its ABIs and view calls are asked for
+type testLedgerViewClient struct {
+	ViewClient
+	abiVersions  [][]uint64
+	viewVersions [][]uint64
+}
+
+func (client *testLedgerViewClient) ModuleAbi(address AccountAddress, moduleName string, ledgerVersion ...uint64) (*api.MoveModule, error) {
+	client.abiVersions = append(client.abiVersions, ledgerVersion)
+	return client.ViewClient.ModuleAbi(address, moduleName, ledgerVersion...)
+}
+
+func (client *testLedgerViewClient) View(payload *ViewPayload, ledgerVersion ...uint64) ([]any, error) {
+	client.viewVersions = append(client.viewVersions, ledgerVersion)
+	return client.ViewClient.View(payload, ledgerVersion...)
+}
+
+func TestViewWithArgs_LedgerVersion(t *testing.T) {
+	requests := make([]*ViewPayload, 0)
+	node, cafe := newTestViewNode(t, `["7",false]`, &requests)
+	client := &testLedgerViewClient{ViewClient: node}
+
+	// The ABI is of the module at the same ledger version as the call
+	_, err := viewWithArgs(client, cafe, "registry", "get", []any{"u64"}, nil, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]uint64{{12}}, client.abiVersions)
+	assert.Equal(t, [][]uint64{{12}}, client.viewVersions)
+}
+
+func TestViewInto(t *testing.T) {
+	requests := make([]*ViewPayload, 0)
+	client, cafe := newTestViewNode(t, `[{"vec":["7"]},true]`, &requests)
+
+	type result struct {
+		Value *uint64
+		Found bool
+	}
+	out, err := ViewInto[result](client, cafe, "registry", "get", []any{"0x1::option::Option<u64>"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), *out.Value)
+	assert.True(t, out.Found)
+	assert.Equal(t, "0x1::option::Option<u64>", requests[0].ArgTypes[0].String())
+
+	// Generic return types are converted with the type arguments
+	values, err := client.ViewWithArgs(cafe, "registry", "get", []any{"0x1::option::Option<u64>"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{uint64(7), true}, values)
+
+	all, err := ViewInto[[]any](client, cafe, "registry", "get", []any{"u64"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, *all, 2)
+
+	_, err = ViewInto[struct{ Only bool }](client, cafe, "registry", "get", []any{"u64"}, nil)
+	assert.Error(t, err)
+}